@@ -202,7 +202,7 @@ func ApproveTransaction(c *gin.Context) {
 		return
 	}
 
-	if err := services.ApproveTransaction(userID, req); err != nil {
+	if err := services.ApproveTransaction(userID, req, utils.GetRequestMeta(c)); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -220,7 +220,7 @@ func RejectTransaction(c *gin.Context) {
 		return
 	}
 
-	if err := services.RejectTransaction(userID, req); err != nil {
+	if err := services.RejectTransaction(userID, req, utils.GetRequestMeta(c)); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"backend-go/dto"
+	"backend-go/services"
+	"backend-go/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================================================
+// APPROVAL DELEGATION
+// ============================================================================
+
+// CreateApprovalDelegation - POST /approval-delegations
+func CreateApprovalDelegation(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req dto.CreateApprovalDelegationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := services.CreateApprovalDelegation(userID, req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Approval delegation created successfully", result)
+}
+
+// GetApprovalDelegations - GET /approval-delegations?role=delegator|delegate&active_only=true
+func GetApprovalDelegations(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var filter dto.ApprovalDelegationFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	results, err := services.GetApprovalDelegations(userID, filter)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Approval delegations retrieved successfully", results)
+}
+
+// UpdateApprovalDelegation - PUT /approval-delegations/:id
+func UpdateApprovalDelegation(c *gin.Context) {
+	userID := c.GetString("user_id")
+	id := c.Param("id")
+
+	var req dto.UpdateApprovalDelegationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := services.UpdateApprovalDelegation(userID, id, req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Approval delegation updated successfully", result)
+}
+
+// RevokeApprovalDelegation - DELETE /approval-delegations/:id
+func RevokeApprovalDelegation(c *gin.Context) {
+	userID := c.GetString("user_id")
+	id := c.Param("id")
+
+	if err := services.RevokeApprovalDelegation(userID, id); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Approval delegation revoked successfully", nil)
+}
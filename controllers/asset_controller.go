@@ -4,19 +4,30 @@ import (
 	"backend-go/dto"
 	"backend-go/services"
 	"backend-go/utils"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 )
 
+// GetAllAssets - GET /assets, ?export=csv|xlsx|pdf untuk download seluruh hasil filter
 func GetAllAssets(c *gin.Context) {
 	var filter dto.AssetListFilter
+	if isExportRequest(c) {
+		filter.Page, filter.Limit = 1, 1 // pagination tidak dipakai export
+	}
 
 	if err := c.ShouldBindQuery(&filter); err != nil {
 		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
+	if isExportRequest(c) {
+		streamExport(c, "asset-register", "Asset Register", services.AssetExportColumns,
+			func(w utils.ExportWriter) error { return services.ExportAssets(filter, w) })
+		return
+	}
+
 	if filter.Page == 0 {
 		filter.Page = 1
 	}
@@ -63,3 +74,64 @@ func GetAssetValueHistory(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "Asset value history retrieved successfully", history)
 }
+
+// ============================================================
+// LABEL
+// ============================================================
+
+func GetAssetLabel(c *gin.Context) {
+	assetNumber := c.Param("number")
+
+	image, err := services.GetAssetLabelPNG(assetNumber, c.Query("format"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", "label-"+assetNumber+".png"))
+	c.Data(http.StatusOK, "image/png", image)
+}
+
+func GetAssetLabelSheet(c *gin.Context) {
+	var req dto.AssetLabelSheetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	pdf, err := services.GetAssetLabelSheetPDF(req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", `inline; filename="asset-labels.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+func LookupAssetByLabel(c *gin.Context) {
+	var req dto.AssetLabelLookupRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	asset, err := services.LookupAssetByLabel(req.Payload)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Asset retrieved successfully", asset)
+}
+
+// ScanAssetLabel - target URL di QR label lama; label baru berisi ASSET:{number} (LookupAssetByLabel)
+func ScanAssetLabel(c *gin.Context) {
+	asset, err := services.LookupAssetByLabel(c.Param("number"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Asset retrieved successfully", asset)
+}
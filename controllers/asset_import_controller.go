@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"backend-go/dto"
+	"backend-go/services"
+	"backend-go/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ImportAssets - multipart form: file (CSV / XLSX), column_mapping, sheet, dry_run, batch_size
+func ImportAssets(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req dto.ImportAssetsRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	file, fileHeader, err := c.Request.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+
+	result, err := services.ImportAssets(userID, req, file, fileHeader)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if result.DryRun {
+		utils.SuccessResponse(c, http.StatusOK, "Asset import validated successfully", result)
+		return
+	}
+	utils.SuccessResponse(c, http.StatusCreated, "Asset import processed", result)
+}
+
+func GetAssetImports(c *gin.Context) {
+	var filter dto.AssetImportFilter
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.Limit == 0 {
+		filter.Limit = 10
+	}
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	imports, total, err := services.GetAssetImports(filter)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"data":  imports,
+		"total": total,
+		"page":  filter.Page,
+		"limit": filter.Limit,
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Asset imports retrieved successfully", response)
+}
+
+func GetAssetImportByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	result, err := services.GetAssetImportByID(uint(id))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Asset import retrieved successfully", result)
+}
@@ -113,3 +113,40 @@ func GetProfile(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "Profile retrieved successfully", user)
 }
+
+// RegisterFCMToken - POST /auth/fcm-token
+func RegisterFCMToken(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req dto.RegisterFCMTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	token, err := services.RegisterFCMToken(userID, req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to register FCM token")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "FCM token registered successfully", token)
+}
+
+// UnregisterFCMToken - DELETE /auth/fcm-token
+func UnregisterFCMToken(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req dto.UnregisterFCMTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if err := services.UnregisterFCMToken(userID, req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "FCM token unregistered successfully", nil)
+}
@@ -6,6 +6,7 @@ import (
 	"backend-go/utils"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -108,7 +109,19 @@ func GetMonthlyDepreciationCalculations(c *gin.Context) {
 		return
 	}
 
-	calculations, err := services.GetMonthlyDepreciationCalculations(period)
+	if isExportRequest(c) {
+		if _, err := time.Parse("2006-01", period); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "invalid period format, use YYYY-MM")
+			return
+		}
+		streamExport(c, "depreciation-"+period, "Monthly Depreciation "+period, services.MonthlyDepreciationExportColumns,
+			func(w utils.ExportWriter) error {
+				return services.ExportMonthlyDepreciationCalculations(period, c.Query("book_code"), w)
+			})
+		return
+	}
+
+	calculations, err := services.GetMonthlyDepreciationCalculations(period, c.Query("book_code"))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -141,10 +154,149 @@ func LockMonthlyDepreciation(c *gin.Context) {
 		return
 	}
 
-	if err := services.LockMonthlyDepreciation(period); err != nil {
+	if err := services.LockMonthlyDepreciation(period, c.Query("book_code")); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
 	utils.SuccessResponse(c, http.StatusOK, "Monthly depreciation locked successfully for period "+period, nil)
 }
+
+// ReverseMonthlyDepreciation - POST /depreciation/calculations/reverse
+// Batalkan kalkulasi periode (termasuk yang sudah di-lock) supaya bisa dihitung ulang
+func ReverseMonthlyDepreciation(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req dto.ReverseDepreciationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	reversals, err := services.ReverseMonthlyDepreciation(userID, req, utils.GetRequestMeta(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Monthly depreciation reversed successfully for period "+req.Period, reversals)
+}
+
+func GetDepreciationReversals(c *gin.Context) {
+	reversals, err := services.GetDepreciationReversals(c.Query("period"), c.Query("book_code"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Depreciation reversals retrieved successfully", reversals)
+}
+
+// ============================================================================
+// Depreciation Books
+// ============================================================================
+
+func GetDepreciationBooks(c *gin.Context) {
+	books, err := services.GetDepreciationBooks()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Depreciation books retrieved successfully", books)
+}
+
+func GetFiscalTaxGroups(c *gin.Context) {
+	utils.SuccessResponse(c, http.StatusOK, "Fiscal tax groups retrieved successfully", services.GetFiscalTaxGroups())
+}
+
+// GetDepreciationReconciliation - GET /depreciation/reconciliation?period=YYYY-MM&tax_rate=22
+func GetDepreciationReconciliation(c *gin.Context) {
+	period := c.Query("period")
+	if _, err := time.Parse("2006-01", period); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "period is required (format: YYYY-MM)")
+		return
+	}
+
+	taxRate := services.DefaultDeferredTaxRate
+	if raw := c.Query("tax_rate"); raw != "" {
+		parsed, err := strconv.ParseFloat(raw, 64)
+		if err != nil || parsed < 0 || parsed > 100 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "invalid tax_rate")
+			return
+		}
+		taxRate = parsed
+	}
+
+	report, err := services.GetDepreciationReconciliation(period, taxRate)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Depreciation reconciliation retrieved successfully", report)
+}
+
+// ============================================================================
+// Forecast Depreciation
+// ============================================================================
+
+// ForecastAssetDepreciation - GET /depreciation/forecast/assets/:id
+// Histori aktual + proyeksi; query what-if: calculation_method, useful_life_months, depreciation_rate, residual_type, residual_value
+func ForecastAssetDepreciation(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	var req dto.DepreciationForecastRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	forecast, err := services.ForecastAssetDepreciation(uint(id), req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if isExportRequest(c) {
+		streamExport(c, "depreciation-forecast-"+forecast.AssetNumber, "Depreciation Forecast "+forecast.AssetNumber+" ("+forecast.BookCode+")",
+			services.DepreciationScheduleExportColumns,
+			func(w utils.ExportWriter) error {
+				return services.ExportAssetDepreciationForecast(forecast, w)
+			})
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Depreciation forecast retrieved successfully", forecast)
+}
+
+// GetDepreciationForecastSummary - GET /depreciation/forecast/summary?fiscal_year=YYYY
+func GetDepreciationForecastSummary(c *gin.Context) {
+	var req dto.DepreciationForecastSummaryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	summary, err := services.GetDepreciationForecastSummary(req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if isExportRequest(c) {
+		year := strconv.Itoa(summary.FiscalYear)
+		streamExport(c, "depreciation-forecast-"+year, "Depreciation Forecast "+year+" ("+summary.BookCode+")",
+			services.DepreciationForecastSummaryExportColumns,
+			func(w utils.ExportWriter) error {
+				return services.ExportDepreciationForecastSummary(summary, w)
+			})
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Depreciation forecast summary retrieved successfully", summary)
+}
@@ -76,6 +76,12 @@ func GetAllDisposals(c *gin.Context) {
 		filter.EndDate = &v
 	}
 
+	if isExportRequest(c) {
+		streamExport(c, "disposals", "Asset Disposals", services.DisposalExportColumns,
+			func(w utils.ExportWriter) error { return services.ExportDisposals(filter, w) })
+		return
+	}
+
 	results, total, err := services.GetAllDisposals(filter)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
@@ -151,7 +157,7 @@ func SubmitDisposal(c *gin.Context) {
 	var req dto.SubmitDisposalRequest
 	_ = c.ShouldBindJSON(&req)
 
-	result, err := services.SubmitDisposal(userID, transactionNumber, req)
+	result, err := services.SubmitDisposal(userID, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -175,7 +181,7 @@ func SetDisposalSaleValues(c *gin.Context) {
 		return
 	}
 
-	result, err := services.SetDisposalSaleValues(userID, transactionNumber, req)
+	result, err := services.SetDisposalSaleValues(userID, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -267,7 +273,7 @@ func ExecuteDisposal(c *gin.Context) {
 	var req dto.ExecuteDisposalRequest
 	_ = c.ShouldBindJSON(&req)
 
-	result, err := services.ExecuteDisposal(userID, transactionNumber, req)
+	result, err := services.ExecuteDisposal(userID, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -288,7 +294,7 @@ func ConfirmDisposalFinance(c *gin.Context) {
 	var req dto.ConfirmDisposalFinanceRequest
 	_ = c.ShouldBindJSON(&req)
 
-	result, err := services.ConfirmDisposalFinance(userID, transactionNumber, req)
+	result, err := services.ConfirmDisposalFinance(userID, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -309,7 +315,7 @@ func ConfirmDisposalTax(c *gin.Context) {
 	var req dto.ConfirmDisposalTaxRequest
 	_ = c.ShouldBindJSON(&req)
 
-	result, err := services.ConfirmDisposalTax(userID, transactionNumber, req)
+	result, err := services.ConfirmDisposalTax(userID, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -329,7 +335,7 @@ func ConfirmDisposalAssetDeletion(c *gin.Context) {
 	var req dto.ConfirmDisposalAssetDeletionRequest
 	_ = c.ShouldBindJSON(&req)
 
-	result, err := services.ConfirmDisposalAssetDeletion(userID, transactionNumber, req)
+	result, err := services.ConfirmDisposalAssetDeletion(userID, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -352,7 +358,7 @@ func RejectDisposal(c *gin.Context) {
 		return
 	}
 
-	result, err := services.RejectDisposal(userID, transactionNumber, req)
+	result, err := services.RejectDisposal(userID, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"backend-go/utils"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================================
+// EXPORT LIST
+// List endpoint yang mendukung ?export=csv|xlsx|pdf memanggil streamExport
+// dengan filter yang sama (tanpa pagination).
+// Header response baru dikirim saat byte pertama ditulis, jadi error sebelum
+// itu (query gagal, PDF kebanyakan baris) tetap dibalas JSON biasa.
+// ============================================================
+
+func isExportRequest(c *gin.Context) bool {
+	return c.Query("export") != ""
+}
+
+func streamExport(c *gin.Context, fileName, title string, columns []utils.ExportColumn, fill func(utils.ExportWriter) error) {
+	format, err := utils.NormalizeExportFormat(c.Query("export"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	contentType, extension := utils.ExportContentType(format)
+	out := &exportResponseWriter{
+		c:           c,
+		contentType: contentType,
+		fileName:    fmt.Sprintf("%s-%s%s", fileName, time.Now().Format("20060102-150405"), extension),
+	}
+
+	writer, err := utils.NewExportWriter(format, out, title, columns)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := fill(writer); err != nil {
+		writer.Discard()
+		failExport(c, out, err)
+		return
+	}
+	if err := writer.Close(); err != nil {
+		failExport(c, out, err)
+		return
+	}
+}
+
+func failExport(c *gin.Context, out *exportResponseWriter, err error) {
+	if out.started {
+		// Sebagian file sudah terkirim, status tidak bisa diganti lagi
+		log.Printf("export %s aborted: %v", out.fileName, err)
+		c.Abort()
+		return
+	}
+
+	status := http.StatusInternalServerError
+	if errors.Is(err, utils.ErrPDFExportTooLarge) {
+		status = http.StatusBadRequest
+	}
+	utils.ErrorResponse(c, status, err.Error())
+}
+
+type exportResponseWriter struct {
+	c           *gin.Context
+	contentType string
+	fileName    string
+	started     bool
+}
+
+func (w *exportResponseWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.c.Header("Content-Type", w.contentType)
+		w.c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.fileName))
+		w.c.Header("Cache-Control", "no-store")
+		w.c.Status(http.StatusOK)
+	}
+
+	n, err := w.c.Writer.Write(p)
+	w.c.Writer.Flush()
+	return n, err
+}
@@ -73,6 +73,12 @@ func GetAllMutationsFlow(c *gin.Context) {
 		filter.EndDate = &endDate
 	}
 
+	if isExportRequest(c) {
+		streamExport(c, "mutations", "Asset Mutations", services.MutationExportColumns,
+			func(w utils.ExportWriter) error { return services.ExportMutations(filter, w) })
+		return
+	}
+
 	results, total, err := services.GetAllMutationDrafts(filter)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
@@ -150,7 +156,7 @@ func SubmitMutation(c *gin.Context) {
 	var req dto.SubmitMutationRequest
 	_ = c.ShouldBindJSON(&req)
 
-	result, err := services.SubmitMutation(userID, transactionNumber, req)
+	result, err := services.SubmitMutation(userID, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -205,7 +211,7 @@ func ConfirmMutationReceiving(c *gin.Context) {
 	var req dto.SubmitMutationRequest // reuse — hanya butuh notes
 	_ = c.ShouldBindJSON(&req)
 
-	result, err := services.ConfirmMutationReceiving(userID, transactionNumber, req.Notes)
+	result, err := services.ConfirmMutationReceiving(userID, transactionNumber, req.Notes, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -225,7 +231,7 @@ func ExecuteMutation(c *gin.Context) {
 	var req dto.ExecuteMutationRequest
 	_ = c.ShouldBindJSON(&req)
 
-	result, err := services.ExecuteMutation(userID, transactionNumber, req)
+	result, err := services.ExecuteMutation(userID, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -248,7 +254,7 @@ func RejectMutation(c *gin.Context) {
 		return
 	}
 
-	result, err := services.RejectMutation(userID, transactionNumber, req)
+	result, err := services.RejectMutation(userID, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
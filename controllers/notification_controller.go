@@ -0,0 +1,119 @@
+package controllers
+
+import (
+	"backend-go/dto"
+	"backend-go/services"
+	"backend-go/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================================================
+// NOTIFICATION INBOX
+// ============================================================================
+
+// GetNotifications - GET /notifications?unread_only=true&type=&page=&limit=
+func GetNotifications(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var filter dto.NotificationFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	if filter.Page == 0 {
+		filter.Page = 1
+	}
+	if filter.Limit == 0 {
+		filter.Limit = 20
+	}
+
+	notifications, total, err := services.GetUserNotifications(userID, filter)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := map[string]interface{}{
+		"data":  notifications,
+		"total": total,
+		"page":  filter.Page,
+		"limit": filter.Limit,
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notifications retrieved successfully", response)
+}
+
+// GetUnreadNotificationCount - GET /notifications/unread-count
+func GetUnreadNotificationCount(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	count, err := services.CountUnreadNotifications(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Unread notification count retrieved successfully",
+		dto.UnreadNotificationCountResponse{Unread: count})
+}
+
+// MarkNotificationRead - PUT /notifications/:id/read
+func MarkNotificationRead(c *gin.Context) {
+	userID := c.GetString("user_id")
+	id := c.Param("id")
+
+	if err := services.MarkNotificationRead(userID, id); err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notification marked as read", nil)
+}
+
+// GetEmailPreferences - GET /notifications/email-preferences
+func GetEmailPreferences(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	result, err := services.GetEmailPreferences(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email preferences retrieved successfully", result)
+}
+
+// UpdateEmailPreferences - PUT /notifications/email-preferences
+func UpdateEmailPreferences(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req dto.UpdateEmailPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := services.UpdateEmailPreferences(userID, req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Email preferences updated successfully", result)
+}
+
+// MarkAllNotificationsRead - PUT /notifications/read-all
+func MarkAllNotificationsRead(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	updated, err := services.MarkAllNotificationsRead(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "All notifications marked as read", gin.H{"updated": updated})
+}
@@ -28,7 +28,7 @@ func SubmitProcurement(c *gin.Context) {
 		return
 	}
 
-	result, err := services.SubmitProcurement(userID, transactionNumber, req)
+	result, err := services.SubmitProcurement(userID, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -57,7 +57,7 @@ func VerifyProcurement(c *gin.Context) {
 		return
 	}
 
-	result, err := services.VerifyProcurement(userID, branchCode, transactionNumber, req)
+	result, err := services.VerifyProcurement(userID, branchCode, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -105,7 +105,7 @@ func CompleteProcurementApproval(c *gin.Context) {
 		return
 	}
 
-	result, err := services.CompleteProcurementApproval(userID, transactionNumber)
+	result, err := services.CompleteProcurementApproval(userID, transactionNumber, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -133,7 +133,7 @@ func ProcessProcurementBudget(c *gin.Context) {
 		return
 	}
 
-	result, err := services.ProcessProcurementBudget(userID, transactionNumber, req)
+	result, err := services.ProcessProcurementBudget(userID, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -161,7 +161,7 @@ func ExecuteProcurementAsset(c *gin.Context) {
 		return
 	}
 
-	result, err := services.ExecuteProcurementAsset(userID, transactionNumber, req)
+	result, err := services.ExecuteProcurementAsset(userID, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -189,7 +189,7 @@ func CreateAssetGR(c *gin.Context) {
 		return
 	}
 
-	result, err := services.CreateAssetGR(userID, transactionNumber, req)
+	result, err := services.CreateAssetGR(userID, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -238,7 +238,7 @@ func RejectProcurement(c *gin.Context) {
 		return
 	}
 
-	result, err := services.RejectProcurement(userID, transactionNumber, req)
+	result, err := services.RejectProcurement(userID, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -266,7 +266,7 @@ func ReviseProcurement(c *gin.Context) {
 		return
 	}
 
-	result, err := services.ReviseProcurement(userID, transactionNumber, req)
+	result, err := services.ReviseProcurement(userID, transactionNumber, req, utils.GetRequestMeta(c))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
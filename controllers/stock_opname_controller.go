@@ -5,6 +5,7 @@ import (
 	"backend-go/services"
 	"backend-go/utils"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
@@ -100,3 +101,406 @@ func DeleteStockOpname(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "Stock opname deleted successfully", nil)
 }
+
+// ============================================================
+// STAGED FLOW
+// ============================================================
+
+func StartStockOpnameCounting(c *gin.Context) {
+	userID := c.GetString("user_id")
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	var req dto.StockOpnameStageRequest
+	_ = c.ShouldBindJSON(&req)
+
+	result, err := services.StartStockOpnameCounting(userID, transactionNumber, req, utils.GetRequestMeta(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stock opname counting started", result)
+}
+
+func UpdateStockOpnameCounts(c *gin.Context) {
+	userID := c.GetString("user_id")
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	var req dto.UpdateStockOpnameCountsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := services.UpdateStockOpnameCounts(userID, transactionNumber, req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stock opname counts updated successfully", result)
+}
+
+func SubmitStockOpnameCount(c *gin.Context) {
+	userID := c.GetString("user_id")
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	var req dto.StockOpnameStageRequest
+	_ = c.ShouldBindJSON(&req)
+
+	result, err := services.SubmitStockOpnameCount(userID, transactionNumber, req, utils.GetRequestMeta(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stock opname count submitted for review", result)
+}
+
+func ConfirmStockOpnameReview(c *gin.Context) {
+	userID := c.GetString("user_id")
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	var req dto.StockOpnameStageRequest
+	_ = c.ShouldBindJSON(&req)
+
+	result, err := services.ConfirmStockOpnameReview(userID, transactionNumber, req, utils.GetRequestMeta(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stock opname review confirmed", result)
+}
+
+func ReturnStockOpnameToCounting(c *gin.Context) {
+	userID := c.GetString("user_id")
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	var req dto.StockOpnameStageRequest
+	_ = c.ShouldBindJSON(&req)
+
+	result, err := services.ReturnStockOpnameToCounting(userID, transactionNumber, req, utils.GetRequestMeta(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stock opname returned to counting", result)
+}
+
+func InitiateStockOpnameApproval(c *gin.Context) {
+	userID := c.GetString("user_id")
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	var req dto.InitiateApprovalRequest
+	_ = c.ShouldBindJSON(&req)
+
+	if err := services.InitiateStockOpnameApproval(userID, transactionNumber, req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Approval initiated successfully", nil)
+}
+
+func GetStockOpnameApprovalStatus(c *gin.Context) {
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	result, err := services.GetTransactionApprovalStatus(transactionNumber, services.TxStockOpname)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Approval status retrieved successfully", result)
+}
+
+func RejectStockOpname(c *gin.Context) {
+	userID := c.GetString("user_id")
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	var req dto.RejectStockOpnameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := services.RejectStockOpname(userID, transactionNumber, req, utils.GetRequestMeta(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stock opname rejected", result)
+}
+
+// ============================================================
+// OPNAME PER CABANG
+// ============================================================
+
+func StartBranchStockOpname(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req dto.StartBranchStockOpnameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := services.StartBranchStockOpname(userID, req, utils.GetRequestMeta(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Branch stock opname started", result)
+}
+
+func GetStockOpnameWorksheet(c *gin.Context) {
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	result, err := services.GetStockOpnameWorksheet(transactionNumber)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stock opname worksheet retrieved successfully", result)
+}
+
+// ============================================================
+// FOUND ITEMS & VARIANCE
+// ============================================================
+
+func AddStockOpnameFoundItem(c *gin.Context) {
+	userID := c.GetString("user_id")
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	var req dto.CreateStockOpnameFoundItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := services.AddStockOpnameFoundItem(userID, transactionNumber, req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Found item recorded successfully", result)
+}
+
+func RemoveStockOpnameFoundItem(c *gin.Context) {
+	userID := c.GetString("user_id")
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "invalid id")
+		return
+	}
+
+	if err := services.RemoveStockOpnameFoundItem(userID, transactionNumber, uint(id)); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Found item removed successfully", nil)
+}
+
+func GetStockOpnameVariance(c *gin.Context) {
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	result, err := services.GetStockOpnameVariance(transactionNumber)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stock opname variance retrieved successfully", result)
+}
+
+func CreateStockOpnameFollowUp(c *gin.Context) {
+	userID := c.GetString("user_id")
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	var req dto.CreateStockOpnameFollowUpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := services.CreateStockOpnameFollowUp(userID, transactionNumber, req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Stock opname follow up drafts created", result)
+}
+
+// ============================================================
+// OFFLINE SYNC (mobile scanner)
+// ============================================================
+
+func GetStockOpnameSyncWorksheets(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	result, err := services.GetStockOpnameSyncWorksheets(userID, c.Query("branch_code"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stock opname worksheets retrieved successfully", result)
+}
+
+func SyncStockOpnameCounts(c *gin.Context) {
+	userID := c.GetString("user_id")
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	var req dto.SyncStockOpnameCountsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := services.SyncStockOpnameCounts(userID, transactionNumber, req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stock opname counts synced", result)
+}
+
+func GetStockOpnameSyncConflicts(c *gin.Context) {
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	result, err := services.GetStockOpnameSyncConflicts(transactionNumber)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Stock opname sync conflicts retrieved successfully", result)
+}
+
+func UploadStockOpnameCountPhoto(c *gin.Context) {
+	userID := c.GetString("user_id")
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	var req dto.UploadStockOpnameCountPhotoRequest
+	if err := c.ShouldBind(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	file, fileHeader, err := c.Request.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+
+	result, err := services.UploadStockOpnameCountPhoto(userID, transactionNumber, req, file, fileHeader)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Photo uploaded successfully", result)
+}
+
+func GetStockOpnameCountPhotos(c *gin.Context) {
+	transactionNumber := c.Query("transaction_number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "transaction_number is required")
+		return
+	}
+
+	var assetID *uint
+	if raw := c.Query("asset_id"); raw != "" {
+		id, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "invalid asset_id")
+			return
+		}
+		value := uint(id)
+		assetID = &value
+	}
+
+	result, err := services.GetStockOpnameCountPhotos(transactionNumber, assetID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Photos retrieved successfully", result)
+}
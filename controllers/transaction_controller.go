@@ -4,6 +4,7 @@ import (
 	"backend-go/dto"
 	"backend-go/services"
 	"backend-go/utils"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -22,15 +23,24 @@ func GetTransactionByNumber(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Transaction retrieved successfully", transaction)
 }
 
-// GetAllTransactions - GET /transactions
+// GetAllTransactions - GET /transactions, ?export=csv|xlsx|pdf untuk download seluruh hasil filter
 func GetAllTransactions(c *gin.Context) {
 	var filter dto.TransactionListFilter
+	if isExportRequest(c) {
+		filter.Page, filter.Limit = 1, 1 // pagination tidak dipakai export
+	}
 
 	if err := c.ShouldBindQuery(&filter); err != nil {
 		utils.ValidationErrorResponse(c, err)
 		return
 	}
 
+	if isExportRequest(c) {
+		streamExport(c, "transactions", "Transactions", services.TransactionExportColumns,
+			func(w utils.ExportWriter) error { return services.ExportTransactions(filter, w) })
+		return
+	}
+
 	// Set defaults
 	if filter.Page == 0 {
 		filter.Page = 1
@@ -87,3 +97,38 @@ func GetMyTransactions(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "My transactions retrieved successfully", response)
 }
+
+// GetTransactionDocument - GET /transactions/document?number= → PDF dokumen resmi + tanda tangan
+func GetTransactionDocument(c *gin.Context) {
+	transactionNumber := c.Query("number")
+	if transactionNumber == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "number is required")
+		return
+	}
+
+	pdf, err := services.GetTransactionDocumentPDF(transactionNumber)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", transactionNumber+".pdf"))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// VerifyTransactionDocument - GET /transactions/verify?number=&code= (public, target QR di dokumen)
+func VerifyTransactionDocument(c *gin.Context) {
+	var req dto.TransactionDocumentVerifyRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	result, err := services.VerifyTransactionDocument(req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, result.Message, result)
+}
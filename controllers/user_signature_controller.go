@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"backend-go/dto"
+	"backend-go/services"
+	"backend-go/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ============================================================
+// USER SIGNATURE (profil user yang login)
+// ============================================================
+
+// GetMySignatures - GET /auth/me/signatures
+func GetMySignatures(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	signatures, err := services.GetUserSignatures(userID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Signatures retrieved successfully", signatures)
+}
+
+// UploadMySignature - POST /auth/me/signatures (multipart/form-data: file)
+func UploadMySignature(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	file, fileHeader, err := c.Request.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+
+	signature, err := services.UploadUserSignature(userID, file, fileHeader)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Signature uploaded successfully", signature)
+}
+
+// DrawMySignature - POST /auth/me/signatures/draw
+func DrawMySignature(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	var req dto.DrawUserSignatureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ValidationErrorResponse(c, err)
+		return
+	}
+
+	signature, err := services.SaveDrawnUserSignature(userID, req)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Signature saved successfully", signature)
+}
+
+// GetMySignatureImage - GET /auth/me/signatures/:id/image
+func GetMySignatureImage(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	image, mimeType, err := services.GetUserSignatureImage(userID, c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, err.Error())
+		return
+	}
+
+	c.Data(http.StatusOK, mimeType, image)
+}
+
+// ActivateMySignature - PUT /auth/me/signatures/:id/activate
+func ActivateMySignature(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	signature, err := services.ActivateUserSignature(userID, c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Signature activated successfully", signature)
+}
+
+// DeactivateMySignature - PUT /auth/me/signatures/:id/deactivate
+func DeactivateMySignature(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	if err := services.DeactivateUserSignature(userID, c.Param("id")); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Signature deactivated successfully", nil)
+}
@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+// ============================================================================
+// APPROVAL DELEGATION DTOs
+// ============================================================================
+
+type CreateApprovalDelegationRequest struct {
+	DelegateID       string   `json:"delegate_id" binding:"required"`
+	StartDate        string   `json:"start_date" binding:"required"` // format YYYY-MM-DD
+	EndDate          string   `json:"end_date" binding:"required"`   // format YYYY-MM-DD, inklusif
+	FlowCodes        []string `json:"flow_codes"`                    // kosong = semua flow
+	TransactionTypes []string `json:"transaction_types"`             // kosong = semua jenis transaksi
+	Reason           *string  `json:"reason"`
+}
+
+type UpdateApprovalDelegationRequest struct {
+	StartDate        *string  `json:"start_date"`
+	EndDate          *string  `json:"end_date"`
+	FlowCodes        []string `json:"flow_codes"`
+	TransactionTypes []string `json:"transaction_types"`
+	Reason           *string  `json:"reason"`
+}
+
+type ApprovalDelegationFilter struct {
+	Role       string `form:"role" binding:"omitempty,oneof=delegator delegate"` // delegator = yang saya berikan, delegate = yang saya terima
+	ActiveOnly bool   `form:"active_only"`
+}
+
+type ApprovalDelegationResponse struct {
+	ID               string     `json:"id"`
+	DelegatorID      string     `json:"delegator_id"`
+	DelegatorName    *string    `json:"delegator_name,omitempty"`
+	DelegateID       string     `json:"delegate_id"`
+	DelegateName     *string    `json:"delegate_name,omitempty"`
+	StartDate        time.Time  `json:"start_date"`
+	EndDate          time.Time  `json:"end_date"`
+	FlowCodes        []string   `json:"flow_codes"`
+	TransactionTypes []string   `json:"transaction_types"`
+	Reason           *string    `json:"reason"`
+	IsActive         bool       `json:"is_active"`
+	IsCurrent        bool       `json:"is_current"` // aktif dan sedang dalam periode
+	RevokedAt        *time.Time `json:"revoked_at"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
@@ -11,6 +11,10 @@ type CreateApprovalFlowRequest struct {
 	BranchCode          string   `json:"branch_code"`
 	FlowName            string   `json:"flow_name" binding:"required,min=2,max=100"`
 	ApprovalWay         string   `json:"approval_way" binding:"required,oneof=sequential parallel conditional"`
+	TimeoutAction       string   `json:"timeout_action" binding:"omitempty,oneof=none escalate skip reject"`
+	EscalationRoleID    *string  `json:"escalation_role_id"`
+	EscalationUserID    *string  `json:"escalation_user_id"`
+	RequireSignature    bool     `json:"require_signature"`
 	AssignmentType      string   `json:"assignment_type" binding:"required,oneof=general user_specific"`
 	AssignedUserID      *string  `json:"assigned_user_id"` // Required if assignment_type = user_specific
 	IsCustomizable      bool     `json:"is_customizable"`
@@ -28,6 +32,10 @@ type UpdateApprovalFlowRequest struct {
 	BranchCode          string   `json:"branch_code"`
 	FlowName            string   `json:"flow_name" binding:"omitempty,min=2,max=100"`
 	ApprovalWay         string   `json:"approval_way" binding:"omitempty,oneof=sequential parallel conditional"`
+	TimeoutAction       string   `json:"timeout_action" binding:"omitempty,oneof=none escalate skip reject"`
+	EscalationRoleID    *string  `json:"escalation_role_id"`
+	EscalationUserID    *string  `json:"escalation_user_id"`
+	RequireSignature    *bool    `json:"require_signature"`
 	AssignmentType      string   `json:"assignment_type" binding:"omitempty,oneof=general user_specific"`
 	AssignedUserID      *string  `json:"assigned_user_id"`
 	IsCustomizable      *bool    `json:"is_customizable"`
@@ -42,6 +50,10 @@ type ApprovalFlowResponse struct {
 	BranchCode          string  `json:"branch_code"`
 	FlowName            string  `json:"flow_name"`
 	ApprovalWay         string  `json:"approval_way"`
+	TimeoutAction       string  `json:"timeout_action"`
+	EscalationRoleID    *string `json:"escalation_role_id"`
+	EscalationUserID    *string `json:"escalation_user_id"`
+	RequireSignature    bool    `json:"require_signature"`
 	AssignmentType      string  `json:"assignment_type"`
 	AssignedUserID      *string `json:"assigned_user_id"`
 	AssignedUsername    *string `json:"assigned_username,omitempty"`
@@ -73,40 +85,46 @@ type ApprovalFlowResponse struct {
 // ============================================================================
 
 type CreateApprovalFlowStepRequest struct {
-	FlowID       string  `json:"flow_id" binding:"required"`
-	StepOrder    int     `json:"step_order" binding:"required,min=1"`
-	StepName     string  `json:"step_name" binding:"required,min=2,max=100"`
-	StepRole     string  `json:"step_role" binding:"required,oneof=creator reviewer approver receiver"`
-	RoleID       *string `json:"role_id"`
-	BranchID     *string `json:"branch_id"`
-	Structure    *string `json:"structure"`
-	IsRequired   bool    `json:"is_required"`
-	CanSkip      bool    `json:"can_skip"`
-	IsVisible    bool    `json:"is_visible"`
-	Type         string  `json:"type" binding:"omitempty,oneof=it non-it all"`                    // it, non-it, all
-	Category     string  `json:"category" binding:"omitempty,oneof=budget non-budget return all"` // budget, non-budget, return, all
-	ApprovalWay  string  `json:"approval_way" binding:"omitempty,oneof=web upload"`               // web, upload
-	AutoApprove  bool    `json:"auto_approve"`
-	TimeoutHours *int    `json:"timeout_hours"`
-	Conditions   *string `json:"conditions"`
+	FlowID           string  `json:"flow_id" binding:"required"`
+	StepOrder        int     `json:"step_order" binding:"required,min=1"`
+	QuorumType       string  `json:"quorum_type" binding:"omitempty,oneof=all any n_of_m"`
+	QuorumCount      *int    `json:"quorum_count" binding:"omitempty,min=1"`
+	StepName         string  `json:"step_name" binding:"required,min=2,max=100"`
+	StepRole         string  `json:"step_role" binding:"required,oneof=creator reviewer approver receiver"`
+	RoleID           *string `json:"role_id"`
+	BranchID         *string `json:"branch_id"`
+	Structure        *string `json:"structure"`
+	IsRequired       bool    `json:"is_required"`
+	CanSkip          bool    `json:"can_skip"`
+	IsVisible        bool    `json:"is_visible"`
+	Type             string  `json:"type" binding:"omitempty,oneof=it non-it all"`                    // it, non-it, all
+	Category         string  `json:"category" binding:"omitempty,oneof=budget non-budget return all"` // budget, non-budget, return, all
+	ApprovalWay      string  `json:"approval_way" binding:"omitempty,oneof=web upload"`               // web, upload
+	AutoApprove      bool    `json:"auto_approve"`
+	AutoApproveRules *string `json:"auto_approve_rules"`
+	TimeoutHours     *int    `json:"timeout_hours"`
+	Conditions       *string `json:"conditions"`
 }
 
 type UpdateApprovalFlowStepRequest struct {
-	StepOrder    *int    `json:"step_order" binding:"omitempty,min=1"`
-	StepName     string  `json:"step_name" binding:"omitempty,min=2,max=100"`
-	StepRole     string  `json:"step_role" binding:"omitempty,oneof=creator reviewer approver receiver"`
-	RoleID       *string `json:"role_id"`
-	BranchID     *string `json:"branch_id"`
-	Structure    *string `json:"structure"`
-	IsRequired   *bool   `json:"is_required"`
-	CanSkip      *bool   `json:"can_skip"`
-	IsVisible    *bool   `json:"is_visible"`
-	Type         *string `json:"type" binding:"omitempty,oneof=it non-it all"`
-	Category     *string `json:"category" binding:"omitempty,oneof=budget non-budget return all"`
-	ApprovalWay  *string `json:"approval_way" binding:"omitempty,oneof=web upload"`
-	AutoApprove  *bool   `json:"auto_approve"`
-	TimeoutHours *int    `json:"timeout_hours"`
-	Conditions   *string `json:"conditions"`
+	StepOrder        *int    `json:"step_order" binding:"omitempty,min=1"`
+	QuorumType       *string `json:"quorum_type" binding:"omitempty,oneof=all any n_of_m"`
+	QuorumCount      *int    `json:"quorum_count" binding:"omitempty,min=1"`
+	StepName         string  `json:"step_name" binding:"omitempty,min=2,max=100"`
+	StepRole         string  `json:"step_role" binding:"omitempty,oneof=creator reviewer approver receiver"`
+	RoleID           *string `json:"role_id"`
+	BranchID         *string `json:"branch_id"`
+	Structure        *string `json:"structure"`
+	IsRequired       *bool   `json:"is_required"`
+	CanSkip          *bool   `json:"can_skip"`
+	IsVisible        *bool   `json:"is_visible"`
+	Type             *string `json:"type" binding:"omitempty,oneof=it non-it all"`
+	Category         *string `json:"category" binding:"omitempty,oneof=budget non-budget return all"`
+	ApprovalWay      *string `json:"approval_way" binding:"omitempty,oneof=web upload"`
+	AutoApprove      *bool   `json:"auto_approve"`
+	AutoApproveRules *string `json:"auto_approve_rules"`
+	TimeoutHours     *int    `json:"timeout_hours"`
+	Conditions       *string `json:"conditions"`
 }
 
 type UpdateBulkStepOrderFlowStep struct {
@@ -114,27 +132,30 @@ type UpdateBulkStepOrderFlowStep struct {
 }
 
 type ApprovalFlowStepResponse struct {
-	ID           string    `json:"id"`
-	FlowID       string    `json:"flow_id"`
-	StepOrder    int       `json:"step_order"`
-	StepName     string    `json:"step_name"`
-	StepRole     string    `json:"step_role"`
-	RoleID       *string   `json:"role_id"`
-	RoleName     *string   `json:"role_name,omitempty"`
-	BranchID     *string   `json:"branch_id"`
-	BranchName   *string   `json:"branch_name,omitempty"`
-	Structure    *string   `json:"structure"`
-	IsRequired   bool      `json:"is_required"`
-	CanSkip      bool      `json:"can_skip"`
-	IsVisible    bool      `json:"is_visible"`
-	Type         string    `json:"type"`
-	Category     string    `json:"category"`
-	ApprovalWay  string    `json:"approval_way"`
-	AutoApprove  bool      `json:"auto_approve"`
-	TimeoutHours *int      `json:"timeout_hours"`
-	Conditions   *string   `json:"conditions"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID               string    `json:"id"`
+	FlowID           string    `json:"flow_id"`
+	StepOrder        int       `json:"step_order"`
+	QuorumType       string    `json:"quorum_type"`
+	QuorumCount      *int      `json:"quorum_count"`
+	StepName         string    `json:"step_name"`
+	StepRole         string    `json:"step_role"`
+	RoleID           *string   `json:"role_id"`
+	RoleName         *string   `json:"role_name,omitempty"`
+	BranchID         *string   `json:"branch_id"`
+	BranchName       *string   `json:"branch_name,omitempty"`
+	Structure        *string   `json:"structure"`
+	IsRequired       bool      `json:"is_required"`
+	CanSkip          bool      `json:"can_skip"`
+	IsVisible        bool      `json:"is_visible"`
+	Type             string    `json:"type"`
+	Category         string    `json:"category"`
+	ApprovalWay      string    `json:"approval_way"`
+	AutoApprove      bool      `json:"auto_approve"`
+	AutoApproveRules *string   `json:"auto_approve_rules"`
+	TimeoutHours     *int      `json:"timeout_hours"`
+	Conditions       *string   `json:"conditions"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
 
 // ============================================================================
@@ -176,6 +197,8 @@ type TransactionApprovalResponse struct {
 	RejectedAt        *time.Time `json:"rejected_at"`
 	RejectedBy        *string    `json:"rejected_by"`
 	RejectedByName    *string    `json:"rejected_by_name,omitempty"`
+	EscalatedAt       *time.Time `json:"escalated_at"`
+	OnBehalfOfUserID  *string    `json:"on_behalf_of_user_id,omitempty"` // diisi di pending list kalau step milik delegator
 	Notes             *string    `json:"notes"`
 	Metadata          *string    `json:"metadata"`
 	CreatedAt         time.Time  `json:"created_at"`
@@ -202,8 +225,11 @@ type ApprovalSignatureResponse struct {
 	ID                string    `json:"id"`
 	TransactionNumber string    `json:"transaction_number"`
 	TransactionType   string    `json:"transaction_type"`
-	UserID            string    `json:"user_id"`
-	Username          string    `json:"username,omitempty"`
+	UserID            *string   `json:"user_id"`
+	Username          *string   `json:"username,omitempty"`
+	OnBehalfOfUserID  *string   `json:"on_behalf_of_user_id"`
+	OnBehalfOfName    *string   `json:"on_behalf_of_name,omitempty"`
+	DelegationID      *string   `json:"delegation_id"`
 	RoleID            *string   `json:"role_id"`
 	RoleName          *string   `json:"role_name,omitempty"`
 	StepRole          string    `json:"step_role"`
@@ -235,7 +261,9 @@ type TransactionApprovalSummary struct {
 	TotalSteps        int                           `json:"total_steps"`
 	CompletedSteps    int                           `json:"completed_steps"`
 	CurrentStep       *ApprovalFlowStepResponse     `json:"current_step,omitempty"`
-	Status            string                        `json:"status"` // pending, in_progress, approved, rejected
+	CurrentStepOrder  *int                          `json:"current_step_order,omitempty"` // grup (step_order) yang sedang aktif
+	CurrentSteps      []TransactionApprovalResponse `json:"current_steps"`                // step yang bisa diproses sekarang
+	Status            string                        `json:"status"`                       // pending, in_progress, approved, rejected
 	Approvals         []TransactionApprovalResponse `json:"approvals"`
 	CreatedAt         time.Time                     `json:"created_at"`
 }
@@ -46,3 +46,19 @@ type AssetListFilter struct {
 	Page        int     `form:"page" binding:"min=1"`
 	Limit       int     `form:"limit" binding:"min=1,max=100"`
 }
+
+// ============================================================
+// LABEL
+// ============================================================
+
+// AssetLabelSheetRequest - isi sheet: asset_numbers, transaction_number
+// (semua asset hasil GR procurement), atau keduanya
+type AssetLabelSheetRequest struct {
+	AssetNumbers      []string `json:"asset_numbers" binding:"omitempty,max=1000"`
+	TransactionNumber *string  `json:"transaction_number"`
+	Format            string   `json:"format" binding:"omitempty,oneof=QR CODE128"` // default QR
+}
+
+type AssetLabelLookupRequest struct {
+	Payload string `form:"payload" binding:"required"`
+}
@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+// ============================================================
+// IMPORT REGISTER ASSET (data lama)
+// multipart form, file CSV / XLSX di field "file"
+// ============================================================
+
+// ImportAssetsRequest - column_mapping berupa JSON {"asset_name": "Nama Aset", ...},
+// field yang tidak di-mapping dicari dari header dengan nama yang sama
+type ImportAssetsRequest struct {
+	ColumnMapping *string `form:"column_mapping"`
+	Sheet         *string `form:"sheet"` // XLSX, default sheet pertama
+	DryRun        bool    `form:"dry_run"`
+	BatchSize     int     `form:"batch_size" binding:"omitempty,min=1,max=500"` // default 100
+}
+
+type AssetImportFilter struct {
+	Status *string `form:"status"`
+	Page   int     `form:"page" binding:"min=1"`
+	Limit  int     `form:"limit" binding:"min=1,max=100"`
+}
+
+type AssetImportRowError struct {
+	Row         int     `json:"row"` // nomor baris di file, header = 1
+	AssetNumber *string `json:"asset_number,omitempty"`
+	Field       *string `json:"field,omitempty"`
+	Message     string  `json:"message"`
+}
+
+type AssetImportRowResult struct {
+	Row            int    `json:"row"`
+	AssetID        uint   `json:"asset_id"`
+	AssetNumber    string `json:"asset_number"`
+	DocumentNumber string `json:"document_number"`
+}
+
+type AssetImportResponse struct {
+	ID            *uint                  `json:"id"` // nil untuk dry run
+	DryRun        bool                   `json:"dry_run"`
+	FileName      string                 `json:"file_name"`
+	FileType      string                 `json:"file_type"`
+	SheetName     *string                `json:"sheet_name,omitempty"`
+	ColumnMapping map[string]string      `json:"column_mapping"`
+	TotalRows     int                    `json:"total_rows"`
+	ValidRows     int                    `json:"valid_rows"`
+	ImportedRows  int                    `json:"imported_rows"`
+	FailedRows    int                    `json:"failed_rows"`
+	BatchSize     int                    `json:"batch_size"`
+	Status        string                 `json:"status"`
+	Errors        []AssetImportRowError  `json:"errors"`
+	Imported      []AssetImportRowResult `json:"imported,omitempty"`
+	CreatedBy     string                 `json:"created_by"`
+	StartedAt     time.Time              `json:"started_at"`
+	FinishedAt    *time.Time             `json:"finished_at,omitempty"`
+}
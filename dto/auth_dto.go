@@ -36,3 +36,14 @@ type UserResponse struct {
 	Status    string   `json:"status"`
 	Roles     []string `json:"roles"`
 }
+
+type RegisterFCMTokenRequest struct {
+	Token    string `json:"token" binding:"required"`
+	DeviceID string `json:"device_id"`
+	Platform string `json:"platform" binding:"required,oneof=android ios web"`
+}
+
+type UnregisterFCMTokenRequest struct {
+	Token    string `json:"token"`
+	DeviceID string `json:"device_id"`
+}
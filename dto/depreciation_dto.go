@@ -5,29 +5,37 @@ import "time"
 // --- Depreciation Settings ---
 
 type CreateDepreciationSettingRequest struct {
+	BookCode           string   `json:"book_code"` // kosong = COMMERCIAL
 	SettingType        string   `json:"setting_type" binding:"required,oneof=CATEGORY ASSET"`
 	ReferenceID        *uint    `json:"reference_id"`    // FIX: category_id atau asset_id tergantung setting_type
 	ReferenceValue     *string  `json:"reference_value"` // FIX: category_code atau asset_number
-	CalculationMethod  string   `json:"calculation_method" binding:"required,oneof=STRAIGHT_LINE DECLINING_BALANCE"`
+	CalculationMethod  string   `json:"calculation_method" binding:"required,oneof=STRAIGHT_LINE DECLINING_BALANCE SUM_OF_YEARS_DIGITS DOUBLE_DECLINING"`
 	DepreciationPeriod string   `json:"depreciation_period" binding:"required,oneof=MONTHLY DAILY"`
-	UsefulLifeMonths   int      `json:"useful_life_months" binding:"required,min=1"`
-	DepreciationRate   *float64 `json:"depreciation_rate" binding:"omitempty,min=0"` // FIX: nullable
-	StartDate          string   `json:"start_date" binding:"required"`               // FIX: tambah, format YYYY-MM-DD
-	EndDate            *string  `json:"end_date"`                                    // FIX: tambah, optional
+	UsefulLifeMonths   int      `json:"useful_life_months" binding:"omitempty,min=1"` // wajib kecuali tax_group diisi
+	DepreciationRate   *float64 `json:"depreciation_rate" binding:"omitempty,min=0"`  // FIX: nullable
+	ResidualType       *string  `json:"residual_type" binding:"omitempty,oneof=PERCENTAGE AMOUNT"`
+	ResidualValue      *float64 `json:"residual_value" binding:"omitempty,min=0"` // persen (0-100) atau nominal sesuai residual_type
+	TaxGroup           *string  `json:"tax_group"`                                // buku FISCAL: masa manfaat & tarif diambil dari kelompok harta
+	StartDate          string   `json:"start_date" binding:"required"`            // FIX: tambah, format YYYY-MM-DD
+	EndDate            *string  `json:"end_date"`                                 // FIX: tambah, optional
 	IsActive           bool     `json:"is_active"`
 }
 
 type UpdateDepreciationSettingRequest struct {
-	CalculationMethod  *string  `json:"calculation_method" binding:"omitempty,oneof=STRAIGHT_LINE DECLINING_BALANCE"`
+	CalculationMethod  *string  `json:"calculation_method" binding:"omitempty,oneof=STRAIGHT_LINE DECLINING_BALANCE SUM_OF_YEARS_DIGITS DOUBLE_DECLINING"`
 	DepreciationPeriod *string  `json:"depreciation_period" binding:"omitempty,oneof=MONTHLY DAILY"`
 	UsefulLifeMonths   *int     `json:"useful_life_months" binding:"omitempty,min=1"`
 	DepreciationRate   *float64 `json:"depreciation_rate" binding:"omitempty,min=0"`
+	ResidualType       *string  `json:"residual_type" binding:"omitempty,oneof=PERCENTAGE AMOUNT NONE"` // NONE = hapus nilai sisa
+	ResidualValue      *float64 `json:"residual_value" binding:"omitempty,min=0"`
+	TaxGroup           *string  `json:"tax_group"`
 	EndDate            *string  `json:"end_date"` // FIX: tambah
 	IsActive           *bool    `json:"is_active"`
 }
 
 type DepreciationSettingResponse struct {
 	ID                 uint       `json:"id"`
+	BookCode           string     `json:"book_code"`
 	SettingType        string     `json:"setting_type"`
 	ReferenceID        *uint      `json:"reference_id"`    // FIX: ganti dari CategoryID/AssetID
 	ReferenceValue     *string    `json:"reference_value"` // FIX: ganti dari CategoryName/AssetNumber
@@ -35,8 +43,11 @@ type DepreciationSettingResponse struct {
 	DepreciationPeriod string     `json:"depreciation_period"`
 	UsefulLifeMonths   int        `json:"useful_life_months"`
 	DepreciationRate   *float64   `json:"depreciation_rate"` // FIX: nullable
-	StartDate          time.Time  `json:"start_date"`        // FIX: tambah
-	EndDate            *time.Time `json:"end_date"`          // FIX: tambah
+	ResidualType       *string    `json:"residual_type"`
+	ResidualValue      *float64   `json:"residual_value"`
+	TaxGroup           *string    `json:"tax_group"`
+	StartDate          time.Time  `json:"start_date"` // FIX: tambah
+	EndDate            *time.Time `json:"end_date"`   // FIX: tambah
 	IsActive           bool       `json:"is_active"`
 	CreatedAt          time.Time  `json:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at"`
@@ -49,7 +60,8 @@ type MonthlyDepreciationResponse struct {
 	AssetID                          uint      `json:"asset_id"`
 	AssetNumber                      string    `json:"asset_number,omitempty"`
 	AssetName                        string    `json:"asset_name,omitempty"`
-	Period                           string    `json:"period"`           // FIX: "YYYY-MM", ganti dari CalculationMonth+CalculationYear
+	Period                           string    `json:"period"` // FIX: "YYYY-MM", ganti dari CalculationMonth+CalculationYear
+	BookCode                         string    `json:"book_code"`
 	CalculationDate                  time.Time `json:"calculation_date"` // FIX: tambah
 	BeginningBookValue               float64   `json:"beginning_book_value"`
 	DepreciationAmount               float64   `json:"depreciation_amount"`
@@ -64,5 +76,146 @@ type MonthlyDepreciationResponse struct {
 }
 
 type CalculateDepreciationRequest struct {
-	Period string `json:"period" binding:"required"` // FIX: format "YYYY-MM", ganti dari Month+Year terpisah
+	Period   string `json:"period" binding:"required"` // FIX: format "YYYY-MM", ganti dari Month+Year terpisah
+	BookCode string `json:"book_code"`                 // kosong = semua buku aktif
+}
+
+// --- Depreciation Books ---
+
+type DepreciationBookResponse struct {
+	ID               uint    `json:"id"`
+	BookCode         string  `json:"book_code"`
+	BookName         string  `json:"book_name"`
+	Description      *string `json:"description"`
+	DrivesAssetValue bool    `json:"drives_asset_value"`
+	IsActive         bool    `json:"is_active"`
+}
+
+// --- Rekonsiliasi Komersial vs Fiskal ---
+
+type DepreciationReconciliationItem struct {
+	AssetID                uint    `json:"asset_id"`
+	AssetNumber            string  `json:"asset_number"`
+	AssetName              string  `json:"asset_name"`
+	CommercialDepreciation float64 `json:"commercial_depreciation"`
+	FiscalDepreciation     float64 `json:"fiscal_depreciation"`
+	FiscalCorrection       float64 `json:"fiscal_correction"` // komersial - fiskal, positif = koreksi fiskal positif
+	CommercialBookValue    float64 `json:"commercial_book_value"`
+	FiscalBookValue        float64 `json:"fiscal_book_value"`
+	TemporaryDifference    float64 `json:"temporary_difference"` // fiskal - komersial, positif = dapat dikurangkan (aset pajak tangguhan)
+	DeferredTax            float64 `json:"deferred_tax"`
+	DeferredTaxType        string  `json:"deferred_tax_type"`      // ASSET / LIABILITY / NONE
+	MissingBook            *string `json:"missing_book,omitempty"` // buku yang belum dihitung untuk asset ini
+}
+
+type DepreciationReconciliationResponse struct {
+	Period                      string                           `json:"period"`
+	TaxRate                     float64                          `json:"tax_rate"`
+	TotalCommercialDepreciation float64                          `json:"total_commercial_depreciation"`
+	TotalFiscalDepreciation     float64                          `json:"total_fiscal_depreciation"`
+	TotalFiscalCorrection       float64                          `json:"total_fiscal_correction"`
+	TotalTemporaryDifference    float64                          `json:"total_temporary_difference"`
+	TotalDeferredTax            float64                          `json:"total_deferred_tax"`
+	Items                       []DepreciationReconciliationItem `json:"items"`
+}
+
+// --- Forecast Depreciation ---
+
+// DepreciationForecastRequest - parameter what-if; kosong = pakai setting yang berlaku
+type DepreciationForecastRequest struct {
+	BookCode          string   `form:"book_code"` // kosong = COMMERCIAL
+	CalculationMethod *string  `form:"calculation_method" binding:"omitempty,oneof=STRAIGHT_LINE DECLINING_BALANCE SUM_OF_YEARS_DIGITS DOUBLE_DECLINING"`
+	UsefulLifeMonths  *int     `form:"useful_life_months" binding:"omitempty,min=1"`
+	DepreciationRate  *float64 `form:"depreciation_rate" binding:"omitempty,min=0"`
+	ResidualType      *string  `form:"residual_type" binding:"omitempty,oneof=PERCENTAGE AMOUNT NONE"` // NONE = tanpa nilai sisa
+	ResidualValue     *float64 `form:"residual_value" binding:"omitempty,min=0"`
+}
+
+// IsWhatIf - true kalau ada parameter yang menimpa setting
+func (r DepreciationForecastRequest) IsWhatIf() bool {
+	return r.CalculationMethod != nil || r.UsefulLifeMonths != nil || r.DepreciationRate != nil || r.ResidualType != nil || r.ResidualValue != nil
+}
+
+type DepreciationSchedulePeriod struct {
+	Period                        string  `json:"period"`
+	Source                        string  `json:"source"` // ACTUAL (monthly_depreciation_calculations) / FORECAST
+	BeginningBookValue            float64 `json:"beginning_book_value"`
+	DepreciationAmount            float64 `json:"depreciation_amount"`
+	EndingAccumulatedDepreciation float64 `json:"ending_accumulated_depreciation"`
+	EndingBookValue               float64 `json:"ending_book_value"`
+}
+
+type AssetDepreciationForecastResponse struct {
+	AssetID                   uint                         `json:"asset_id"`
+	AssetNumber               string                       `json:"asset_number"`
+	AssetName                 string                       `json:"asset_name"`
+	BookCode                  string                       `json:"book_code"`
+	DepreciationSettingID     uint                         `json:"depreciation_setting_id"`
+	CalculationMethod         string                       `json:"calculation_method"`
+	DepreciationPeriod        string                       `json:"depreciation_period"`
+	UsefulLifeMonths          int                          `json:"useful_life_months"`
+	DepreciationRate          *float64                     `json:"depreciation_rate"`
+	ResidualValue             float64                      `json:"residual_value"` // nominal rupiah
+	IsWhatIf                  bool                         `json:"is_what_if"`
+	CurrentBookValue          float64                      `json:"current_book_value"`
+	TotalForecastDepreciation float64                      `json:"total_forecast_depreciation"`
+	FullyDepreciatedPeriod    *string                      `json:"fully_depreciated_period"` // periode book value mencapai nilai sisa
+	Schedule                  []DepreciationSchedulePeriod `json:"schedule"`
+}
+
+type DepreciationForecastSummaryRequest struct {
+	FiscalYear int     `form:"fiscal_year" binding:"required,min=2000,max=2100"`
+	BookCode   string  `form:"book_code"` // kosong = COMMERCIAL
+	CategoryID *uint   `form:"category_id"`
+	BranchCode *string `form:"branch_code"`
+}
+
+type DepreciationForecastSummaryMonth struct {
+	Period               string  `json:"period"`
+	ActualDepreciation   float64 `json:"actual_depreciation"`
+	ForecastDepreciation float64 `json:"forecast_depreciation"`
+	TotalDepreciation    float64 `json:"total_depreciation"`
+}
+
+type DepreciationForecastSummaryGroup struct {
+	CategoryID           *uint                              `json:"category_id"`
+	CategoryName         *string                            `json:"category_name"`
+	BranchCode           *string                            `json:"branch_code"`
+	BranchName           *string                            `json:"branch_name"`
+	AssetCount           int                                `json:"asset_count"`
+	ActualDepreciation   float64                            `json:"actual_depreciation"`
+	ForecastDepreciation float64                            `json:"forecast_depreciation"`
+	TotalDepreciation    float64                            `json:"total_depreciation"`
+	Months               []DepreciationForecastSummaryMonth `json:"months"`
+}
+
+type DepreciationForecastSummaryResponse struct {
+	FiscalYear           int                                `json:"fiscal_year"`
+	BookCode             string                             `json:"book_code"`
+	ActualDepreciation   float64                            `json:"actual_depreciation"`
+	ForecastDepreciation float64                            `json:"forecast_depreciation"`
+	TotalDepreciation    float64                            `json:"total_depreciation"`
+	Groups               []DepreciationForecastSummaryGroup `json:"groups"`
+}
+
+// --- Reversal Depreciation ---
+
+type ReverseDepreciationRequest struct {
+	Period   string `json:"period" binding:"required"` // "YYYY-MM"
+	BookCode string `json:"book_code"`                 // kosong = semua buku yang punya kalkulasi di periode ini
+	Reason   string `json:"reason" binding:"required"`
+}
+
+type DepreciationReversalResponse struct {
+	ID                      uint      `json:"id"`
+	Period                  string    `json:"period"`
+	BookCode                string    `json:"book_code"`
+	Reason                  string    `json:"reason"`
+	WasLocked               bool      `json:"was_locked"`
+	CalculationCount        int       `json:"calculation_count"`
+	RestoredAssetValueCount int       `json:"restored_asset_value_count"`
+	RebasedAssetValueCount  int       `json:"rebased_asset_value_count"`
+	RebasedAssets           []string  `json:"rebased_assets"`
+	ReversedBy              string    `json:"reversed_by"`
+	ReversedAt              time.Time `json:"reversed_at"`
 }
@@ -33,6 +33,7 @@ type MenuResponse struct {
 	OrderIndex  int            `json:"order_index"`
 	Status      string         `json:"status"`
 	Permissions []string       `json:"permissions,omitempty"` // dari role_menus, hanya ada di sidebar & role menus response
+	Badge       *int64         `json:"badge,omitempty"`       // jumlah notifikasi belum dibaca, hanya di sidebar menu notifikasi
 	Children    []MenuResponse `json:"children,omitempty"`
 }
 
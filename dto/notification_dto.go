@@ -0,0 +1,57 @@
+package dto
+
+import "time"
+
+// ============================================================================
+// NOTIFICATION INBOX DTOs
+// ============================================================================
+
+type NotificationFilter struct {
+	UnreadOnly bool   `form:"unread_only"`
+	Type       string `form:"type"`
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type NotificationResponse struct {
+	ID                    string     `json:"id"`
+	Type                  string     `json:"type"`
+	Title                 string     `json:"title"`
+	Body                  *string    `json:"body"`
+	TransactionNumber     *string    `json:"transaction_number"`
+	TransactionType       *string    `json:"transaction_type"`
+	Stage                 *string    `json:"stage"`
+	TransactionApprovalID *string    `json:"transaction_approval_id"`
+	IsRead                bool       `json:"is_read"`
+	ReadAt                *time.Time `json:"read_at"`
+	CreatedAt             time.Time  `json:"created_at"`
+}
+
+type UnreadNotificationCountResponse struct {
+	Unread int64 `json:"unread"`
+}
+
+// ============================================================================
+// EMAIL NOTIFICATION PREFERENCES
+// ============================================================================
+
+type EmailPreferenceItem struct {
+	EventType string `json:"event_type" binding:"required,oneof=approval_request transaction_rejected attachment_rejected"`
+	Enabled   *bool  `json:"enabled" binding:"required"`
+}
+
+type UpdateEmailPreferencesRequest struct {
+	Language    *string               `json:"language" binding:"omitempty,oneof=id en"`
+	Preferences []EmailPreferenceItem `json:"preferences" binding:"omitempty,dive"`
+}
+
+type EmailPreferenceResponse struct {
+	EventType string `json:"event_type"`
+	Enabled   bool   `json:"enabled"`
+}
+
+type EmailPreferencesResponse struct {
+	Email       string                    `json:"email"`
+	Language    string                    `json:"language"`
+	Preferences []EmailPreferenceResponse `json:"preferences"`
+}
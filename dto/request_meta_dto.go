@@ -0,0 +1,13 @@
+package dto
+
+// RequestMeta - metadata request HTTP untuk audit trail (approval signature & stage history).
+// Zero value = aksi sistem (scheduler / timeout), tidak ada request yang memicu
+type RequestMeta struct {
+	IPAddress string `json:"ip_address,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+func (m RequestMeta) IsZero() bool {
+	return m.IPAddress == "" && m.UserAgent == "" && m.RequestID == ""
+}
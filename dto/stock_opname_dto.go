@@ -1,6 +1,9 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type CreateStockOpnameRequest struct {
 	TransactionDate string                         `json:"transaction_date" binding:"required"`
@@ -18,8 +21,9 @@ type CreateStockOpnameItemRequest struct {
 }
 
 type StockOpnameResponse struct {
-	Transaction TransactionHeaderResponse `json:"transaction"`
-	Items       []StockOpnameItemResponse `json:"items"`
+	Transaction TransactionHeaderResponse  `json:"transaction"`
+	Items       []StockOpnameItemResponse  `json:"items"`
+	Stages      []TransactionStageResponse `json:"stages,omitempty"`
 }
 
 type StockOpnameItemResponse struct {
@@ -36,3 +40,290 @@ type StockOpnameItemResponse struct {
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
 }
+
+// ============================================================
+// STAGED FLOW
+// ============================================================
+
+type StockOpnameStageRequest struct {
+	Notes *string `json:"notes"`
+}
+
+// UpdateStockOpnameCountsRequest - hasil hitung fisik saat stage COUNTING,
+// hanya asset yang dikirim yang di-update
+type UpdateStockOpnameCountsRequest struct {
+	Items []StockOpnameCountItem `json:"items" binding:"required,min=1,dive"`
+}
+
+type StockOpnameCountItem struct {
+	AssetID        uint    `json:"asset_id" binding:"required"`
+	PhysicalStatus string  `json:"physical_status" binding:"required,oneof=EXISTS MISSING DAMAGED OBSOLETE"`
+	Condition      string  `json:"condition" binding:"required,oneof=GOOD FAIR POOR BROKEN"`
+	AssetStatus    *string `json:"asset_status" binding:"omitempty,oneof=ACTIVE INACTIVE MAINTENANCE RETIRED DISPOSED"`
+	Notes          *string `json:"notes"`
+
+	// Diisi kalau asset ditemukan di cabang / lokasi lain dari register
+	CountedBranchCode *string `json:"counted_branch_code"`
+	CountedLocation   *string `json:"counted_location"`
+}
+
+type RejectStockOpnameRequest struct {
+	Reason string `json:"reason" binding:"required,min=10"`
+}
+
+// ============================================================
+// OPNAME PER CABANG
+// ============================================================
+
+// StartBranchStockOpnameRequest - buat opname untuk semua asset aktif satu cabang,
+// worksheet langsung di-snapshot dan transaksi masuk stage COUNTING
+type StartBranchStockOpnameRequest struct {
+	BranchCode      string  `json:"branch_code" binding:"required"`
+	Period          string  `json:"period" binding:"required"`            // YYYY-MM
+	TransactionDate *string `json:"transaction_date" binding:"omitempty"` // YYYY-MM-DD, default hari ini
+	Notes           *string `json:"notes"`
+}
+
+type StockOpnameWorksheetResponse struct {
+	ID                uint                       `json:"id"`
+	TransactionID     *uint                      `json:"transaction_id"`
+	TransactionNumber *string                    `json:"transaction_number"`
+	DocumentNumber    string                     `json:"document_number"`
+	BranchCode        *string                    `json:"branch_code"`
+	Period            string                     `json:"period"`
+	OpnameDate        time.Time                  `json:"opname_date"`
+	Status            string                     `json:"status"`
+	TotalAssets       int                        `json:"total_assets"`
+	CountedAssets     int                        `json:"counted_assets"`
+	Lines             []StockOpnameWorksheetLine `json:"lines"`
+}
+
+// StockOpnameWorksheetLine - data sistem (snapshot) + hasil hitung fisik per asset
+type StockOpnameWorksheetLine struct {
+	AssetStockOpnameID   uint       `json:"asset_stock_opname_id"`
+	AssetID              uint       `json:"asset_id"`
+	AssetNumber          string     `json:"asset_number"`
+	AssetName            string     `json:"asset_name"`
+	CategoryID           *uint      `json:"category_id"`
+	BranchCode           string     `json:"branch_code"`
+	Location             string     `json:"location"`
+	Grouping             string     `json:"grouping"`
+	BookValue            float64    `json:"book_value"`
+	SystemCondition      string     `json:"system_condition"`
+	SystemPhysicalStatus string     `json:"system_physical_status"`
+	SystemAssetStatus    string     `json:"system_asset_status"`
+	PhysicalStatus       string     `json:"physical_status"`
+	Condition            string     `json:"condition"`
+	AssetStatus          string     `json:"asset_status"`
+	CountedBranchCode    *string    `json:"counted_branch_code"`
+	CountedLocation      *string    `json:"counted_location"`
+	Notes                *string    `json:"notes"`
+	Counted              bool       `json:"counted"`
+	CountedAt            *time.Time `json:"counted_at"`
+}
+
+// ============================================================
+// FOUND ITEMS
+// Barang di lokasi yang tidak terdaftar di register
+// ============================================================
+
+type CreateStockOpnameFoundItemRequest struct {
+	ItemName       string   `json:"item_name" binding:"required"`
+	Description    *string  `json:"description"`
+	Brand          *string  `json:"brand"`
+	SerialNumber   *string  `json:"serial_number"`
+	CategoryID     *uint    `json:"category_id"`
+	BranchCode     *string  `json:"branch_code"`
+	Location       *string  `json:"location"`
+	Condition      string   `json:"condition" binding:"required,oneof=GOOD FAIR POOR BROKEN"`
+	Quantity       *float64 `json:"quantity" binding:"omitempty,gt=0"`
+	EstimatedValue *float64 `json:"estimated_value" binding:"omitempty,gte=0"`
+	Notes          *string  `json:"notes"`
+}
+
+type StockOpnameFoundItemResponse struct {
+	ID             uint      `json:"id"`
+	ItemName       string    `json:"item_name"`
+	Description    *string   `json:"description"`
+	Brand          *string   `json:"brand"`
+	SerialNumber   *string   `json:"serial_number"`
+	CategoryID     *uint     `json:"category_id"`
+	CategoryName   *string   `json:"category_name,omitempty"`
+	BranchCode     *string   `json:"branch_code"`
+	Location       *string   `json:"location"`
+	Condition      string    `json:"condition"`
+	Quantity       float64   `json:"quantity"`
+	EstimatedValue *float64  `json:"estimated_value"`
+	Notes          *string   `json:"notes"`
+	FoundBy        string    `json:"found_by"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ============================================================
+// VARIANCE REPORT
+// ============================================================
+
+type StockOpnameVarianceResponse struct {
+	TransactionNumber string                         `json:"transaction_number"`
+	CurrentStage      string                         `json:"current_stage"`
+	BranchCode        *string                        `json:"branch_code"`
+	Period            string                         `json:"period"`
+	TotalAssets       int                            `json:"total_assets"`
+	Missing           []StockOpnameVarianceLine      `json:"missing"`
+	Relocated         []StockOpnameVarianceLine      `json:"relocated"`
+	Downgraded        []StockOpnameVarianceLine      `json:"downgraded"`
+	FoundItems        []StockOpnameFoundItemResponse `json:"found_items"`
+	ValueAtRisk       []StockOpnameCategoryValueRisk `json:"value_at_risk"`
+	TotalValueAtRisk  float64                        `json:"total_value_at_risk"`
+}
+
+// StockOpnameVarianceLine - register vs hasil hitung untuk satu asset
+type StockOpnameVarianceLine struct {
+	AssetID            uint    `json:"asset_id"`
+	AssetNumber        string  `json:"asset_number"`
+	AssetName          string  `json:"asset_name"`
+	CategoryID         *uint   `json:"category_id"`
+	CategoryName       *string `json:"category_name,omitempty"`
+	BookValue          float64 `json:"book_value"`
+	RegisterBranchCode *string `json:"register_branch_code"`
+	RegisterLocation   *string `json:"register_location"`
+	CountedBranchCode  *string `json:"counted_branch_code,omitempty"`
+	CountedLocation    *string `json:"counted_location,omitempty"`
+	SystemCondition    string  `json:"system_condition"`
+	CountedCondition   string  `json:"counted_condition"`
+	PhysicalStatus     string  `json:"physical_status"`
+	Notes              *string `json:"notes"`
+}
+
+// StockOpnameCategoryValueRisk - book value asset MISSING / turun kondisi per kategori
+type StockOpnameCategoryValueRisk struct {
+	CategoryID          *uint   `json:"category_id"`
+	CategoryName        *string `json:"category_name,omitempty"`
+	MissingCount        int     `json:"missing_count"`
+	MissingBookValue    float64 `json:"missing_book_value"`
+	DowngradedCount     int     `json:"downgraded_count"`
+	DowngradedBookValue float64 `json:"downgraded_book_value"`
+	TotalBookValue      float64 `json:"total_book_value"`
+}
+
+// ============================================================
+// FOLLOW UP
+// Draft mutasi untuk asset pindah cabang, draft disposal untuk asset
+// MISSING / BROKEN — lewat CreateMutationDraft & CreateDisposalDraft
+// ============================================================
+
+type CreateStockOpnameFollowUpRequest struct {
+	TransactionDate string  `json:"transaction_date" binding:"required"`
+	Mutations       bool    `json:"mutations"`
+	Disposals       bool    `json:"disposals"`
+	DisposalType    *string `json:"disposal_type" binding:"omitempty,oneof=DISPOSE SELL"` // default DISPOSE
+}
+
+type StockOpnameFollowUpResponse struct {
+	Mutations []StockOpnameFollowUpDraft   `json:"mutations"`
+	Disposals []StockOpnameFollowUpDraft   `json:"disposals"`
+	Skipped   []StockOpnameFollowUpSkipped `json:"skipped"`
+}
+
+type StockOpnameFollowUpDraft struct {
+	TransactionNumber string   `json:"transaction_number"`
+	ToBranchCode      *string  `json:"to_branch_code,omitempty"`
+	CategoryID        *uint    `json:"category_id,omitempty"`
+	AssetNumbers      []string `json:"asset_numbers"`
+}
+
+type StockOpnameFollowUpSkipped struct {
+	AssetNumber string `json:"asset_number"`
+	Action      string `json:"action"` // MUTATION / DISPOSAL
+	Reason      string `json:"reason"`
+}
+
+// ============================================================
+// OFFLINE SYNC (mobile scanner)
+// ============================================================
+
+type StockOpnameSyncWorksheetsResponse struct {
+	ServerTime time.Time                      `json:"server_time"` // acuan jam device
+	BranchCode string                         `json:"branch_code"`
+	Worksheets []StockOpnameWorksheetResponse `json:"worksheets"`
+}
+
+type SyncStockOpnameCountsRequest struct {
+	DeviceID string                     `json:"device_id" binding:"required,max=100"`
+	Lines    []SyncStockOpnameCountLine `json:"lines" binding:"required,min=1,max=500,dive"`
+}
+
+// SyncStockOpnameCountLine - hasil hitung lengkap satu asset dari device.
+// counted_branch_code / counted_location kosong = sesuai register
+type SyncStockOpnameCountLine struct {
+	ClientLineID      string    `json:"client_line_id" binding:"required,max=64"`
+	AssetID           uint      `json:"asset_id" binding:"required"`
+	ClientTimestamp   time.Time `json:"client_timestamp" binding:"required"` // RFC3339
+	PhysicalStatus    string    `json:"physical_status" binding:"required,oneof=EXISTS MISSING DAMAGED OBSOLETE"`
+	Condition         string    `json:"condition" binding:"required,oneof=GOOD FAIR POOR BROKEN"`
+	AssetStatus       *string   `json:"asset_status" binding:"omitempty,oneof=ACTIVE INACTIVE MAINTENANCE RETIRED DISPOSED"`
+	CountedBranchCode *string   `json:"counted_branch_code"`
+	CountedLocation   *string   `json:"counted_location"`
+	Notes             *string   `json:"notes"`
+}
+
+type SyncStockOpnameCountsResponse struct {
+	TransactionNumber string                      `json:"transaction_number"`
+	ServerTime        time.Time                   `json:"server_time"`
+	Applied           int                         `json:"applied"`
+	Superseded        int                         `json:"superseded"`
+	Duplicates        int                         `json:"duplicates"`
+	Rejected          int                         `json:"rejected"`
+	Conflicts         int                         `json:"conflicts"`
+	Lines             []SyncStockOpnameLineResult `json:"lines"`
+}
+
+type SyncStockOpnameLineResult struct {
+	ClientLineID string  `json:"client_line_id"`
+	AssetID      uint    `json:"asset_id"`
+	Result       string  `json:"result"` // APPLIED, SUPERSEDED, DUPLICATE, REJECTED
+	Message      *string `json:"message,omitempty"`
+	Conflict     bool    `json:"conflict"`
+}
+
+type StockOpnameSyncConflictResponse struct {
+	ID                 uint            `json:"id"`
+	AssetStockOpnameID *uint           `json:"asset_stock_opname_id"`
+	AssetID            uint            `json:"asset_id"`
+	AssetNumber        string          `json:"asset_number"`
+	Resolution         string          `json:"resolution"`
+	WinnerClientLineID *string         `json:"winner_client_line_id"`
+	WinnerDeviceID     *string         `json:"winner_device_id"`
+	WinnerUserID       *string         `json:"winner_user_id"`
+	WinnerTimestamp    time.Time       `json:"winner_timestamp"`
+	WinnerValues       json.RawMessage `json:"winner_values"`
+	LoserClientLineID  *string         `json:"loser_client_line_id"`
+	LoserDeviceID      *string         `json:"loser_device_id"`
+	LoserUserID        *string         `json:"loser_user_id"`
+	LoserTimestamp     time.Time       `json:"loser_timestamp"`
+	LoserValues        json.RawMessage `json:"loser_values"`
+	CreatedAt          time.Time       `json:"created_at"`
+}
+
+// UploadStockOpnameCountPhotoRequest - multipart form, file di field "file"
+type UploadStockOpnameCountPhotoRequest struct {
+	ClientPhotoID string     `form:"client_photo_id" binding:"required,max=64"`
+	AssetID       uint       `form:"asset_id" binding:"required"`
+	ClientLineID  *string    `form:"client_line_id" binding:"omitempty,max=64"`
+	DeviceID      *string    `form:"device_id" binding:"omitempty,max=100"`
+	TakenAt       *time.Time `form:"taken_at" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type StockOpnameCountPhotoResponse struct {
+	ID            uint       `json:"id"`
+	ClientPhotoID string     `json:"client_photo_id"`
+	AssetID       uint       `json:"asset_id"`
+	ClientLineID  *string    `json:"client_line_id"`
+	DeviceID      *string    `json:"device_id"`
+	FileName      string     `json:"file_name"`
+	FileSize      *int64     `json:"file_size"`
+	MimeType      *string    `json:"mime_type"`
+	TakenAt       *time.Time `json:"taken_at"`
+	UploadedBy    string     `json:"uploaded_by"`
+	UploadedAt    time.Time  `json:"uploaded_at"`
+}
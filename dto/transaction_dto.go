@@ -27,3 +27,33 @@ type TransactionListFilter struct {
 	Page            int     `form:"page" binding:"min=1"`
 	Limit           int     `form:"limit" binding:"min=1,max=100"`
 }
+
+// ============================================================
+// TRANSACTION DOCUMENT (PDF) & VERIFIKASI QR
+// ============================================================
+
+type TransactionDocumentVerifyRequest struct {
+	Number string `form:"number" binding:"required"`
+	Code   string `form:"code" binding:"required"`
+}
+
+type TransactionDocumentSignatureResponse struct {
+	Name           string    `json:"name"`
+	RoleName       *string   `json:"role_name"`
+	StepRole       string    `json:"step_role"`
+	OnBehalfOfName *string   `json:"on_behalf_of_name,omitempty"`
+	SignedAt       time.Time `json:"signed_at"`
+}
+
+// TransactionDocumentVerifyResponse - Valid=false kalau kode tidak cocok
+// (dokumen dipalsukan, atau ada tanda tangan baru setelah dokumen dicetak)
+type TransactionDocumentVerifyResponse struct {
+	Valid             bool                                   `json:"valid"`
+	Message           string                                 `json:"message"`
+	TransactionNumber string                                 `json:"transaction_number"`
+	TransactionType   string                                 `json:"transaction_type,omitempty"`
+	TransactionDate   *time.Time                             `json:"transaction_date,omitempty"`
+	Status            string                                 `json:"status,omitempty"`
+	CurrentStage      string                                 `json:"current_stage,omitempty"`
+	Signatures        []TransactionDocumentSignatureResponse `json:"signatures,omitempty"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// ============================================================================
+// USER SIGNATURE DTOs
+// ============================================================================
+
+// DrawUserSignatureRequest - tanda tangan yang digambar di aplikasi, dikirim sebagai PNG base64
+// Boleh dengan prefix data URL ("data:image/png;base64,...")
+type DrawUserSignatureRequest struct {
+	ImageBase64 string `json:"image_base64" binding:"required"`
+}
+
+type UserSignatureResponse struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Version   int       `json:"version"`
+	MimeType  string    `json:"mime_type"`
+	FileSize  int64     `json:"file_size"`
+	Source    string    `json:"source"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+}
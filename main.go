@@ -3,8 +3,10 @@ package main
 import (
 	"log"
 	"os"
+	"strings"
 
 	"backend-go/config"
+	"backend-go/middleware"
 	"backend-go/routes"
 	"backend-go/scheduler"
 
@@ -26,13 +28,29 @@ func main() {
 	// Setup Gin
 	r := gin.Default()
 
+	// Hanya percaya X-Forwarded-For dari proxy yang terdaftar (comma separated IP / CIDR),
+	// kosong = pakai remote address langsung untuk IP audit trail
+	var trustedProxies []string
+	if raw := os.Getenv("TRUSTED_PROXIES"); raw != "" {
+		for _, proxy := range strings.Split(raw, ",") {
+			if proxy = strings.TrimSpace(proxy); proxy != "" {
+				trustedProxies = append(trustedProxies, proxy)
+			}
+		}
+	}
+	if err := r.SetTrustedProxies(trustedProxies); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	r.Use(middleware.RequestIDMiddleware())
+
 	// Setup CORS
 	r.Use(cors.New(cors.Config{
 		// AllowOrigins:     []string{"http://localhost:3000", "http://localhost:5173"},
 		AllowAllOrigins:  true,
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", middleware.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", middleware.RequestIDHeader},
 		AllowCredentials: true,
 	}))
 
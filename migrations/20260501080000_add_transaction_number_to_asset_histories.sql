@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE asset_histories
+    ADD COLUMN transaction_number VARCHAR(100) NULL DEFAULT NULL COMMENT 'Transaction number from main transaction' AFTER transaction_id,
+    ADD KEY idx_asset_hist_transaction_number (transaction_number);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE asset_histories
+    DROP KEY idx_asset_hist_transaction_number,
+    DROP COLUMN transaction_number;
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE approval_flows
+    ADD COLUMN timeout_action ENUM('none','escalate','skip','reject') NOT NULL DEFAULT 'none'
+        COMMENT 'Aksi ketika step melewati timeout_hours'
+        AFTER approval_way,
+    ADD COLUMN escalation_role_id CHAR(36) NULL DEFAULT NULL
+        COMMENT 'Role fallback saat eskalasi'
+        AFTER timeout_action,
+    ADD COLUMN escalation_user_id CHAR(36) NULL DEFAULT NULL
+        COMMENT 'User fallback saat eskalasi (prioritas di atas role)'
+        AFTER escalation_role_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE transaction_approvals
+    ADD COLUMN escalated_at TIMESTAMP NULL DEFAULT NULL
+        COMMENT 'Waktu step di-eskalasi oleh scheduler timeout'
+        AFTER rejected_by;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- user_id NULL untuk signature yang dibuat oleh sistem (timeout / eskalasi)
+ALTER TABLE approval_signatures
+    MODIFY COLUMN user_id CHAR(36) NULL,
+    MODIFY COLUMN status ENUM('signed','rejected','escalated','skipped') DEFAULT 'signed';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM approval_signatures WHERE user_id IS NULL OR status IN ('escalated','skipped');
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE approval_signatures
+    MODIFY COLUMN status ENUM('signed','rejected') DEFAULT 'signed',
+    MODIFY COLUMN user_id CHAR(36) NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE transaction_approvals DROP COLUMN escalated_at;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE approval_flows
+    DROP COLUMN escalation_user_id,
+    DROP COLUMN escalation_role_id,
+    DROP COLUMN timeout_action;
+-- +goose StatementEnd
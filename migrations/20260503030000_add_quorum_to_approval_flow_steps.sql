@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Step dengan step_order yang sama = satu grup paralel, quorum berlaku per grup
+ALTER TABLE approval_flow_steps
+    ADD COLUMN quorum_type ENUM('all','any','n_of_m') NOT NULL DEFAULT 'all'
+        COMMENT 'Aturan quorum grup step_order: semua / salah satu / N dari M'
+        AFTER step_order,
+    ADD COLUMN quorum_count INT NULL DEFAULT NULL
+        COMMENT 'N untuk quorum_type n_of_m'
+        AFTER quorum_type;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE approval_flow_steps
+    DROP COLUMN quorum_count,
+    DROP COLUMN quorum_type;
+-- +goose StatementEnd
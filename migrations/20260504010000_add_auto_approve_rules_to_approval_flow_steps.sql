@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Syarat auto approve per step (format sama dengan conditions), NULL = auto approve tanpa syarat
+ALTER TABLE approval_flow_steps
+    ADD COLUMN auto_approve_rules JSON NULL DEFAULT NULL
+        COMMENT 'Syarat auto approve (JSON), hanya dipakai kalau auto_approve = true'
+        AFTER auto_approve;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE approval_flow_steps
+    DROP COLUMN auto_approve_rules;
+-- +goose StatementEnd
@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS approval_delegations (
+    id CHAR(36) PRIMARY KEY,
+    delegator_id CHAR(36) NOT NULL COMMENT 'User yang mendelegasikan wewenang approval',
+    delegate_id CHAR(36) NOT NULL COMMENT 'User pengganti',
+    start_date DATETIME NOT NULL,
+    end_date DATETIME NOT NULL,
+    flow_codes JSON NULL COMMENT 'Batasi ke flow_code tertentu (NULL = semua)',
+    transaction_types JSON NULL COMMENT 'Batasi ke transaction_type tertentu (NULL = semua)',
+    reason TEXT NULL,
+    is_active BOOLEAN DEFAULT TRUE,
+    revoked_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+
+    INDEX idx_delegator (delegator_id),
+    INDEX idx_delegate_period (delegate_id, start_date, end_date),
+    INDEX idx_deleted_at (deleted_at),
+
+    CONSTRAINT fk_delegation_delegator
+        FOREIGN KEY (delegator_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+
+    CONSTRAINT fk_delegation_delegate
+        FOREIGN KEY (delegate_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Signature dari delegate: user_id = delegate, on_behalf_of_user_id = approver asli
+ALTER TABLE approval_signatures
+    ADD COLUMN on_behalf_of_user_id CHAR(36) NULL DEFAULT NULL
+        COMMENT 'Approver asli kalau ditandatangani oleh delegate'
+        AFTER user_id,
+    ADD COLUMN delegation_id CHAR(36) NULL DEFAULT NULL
+        COMMENT 'Delegasi yang dipakai'
+        AFTER on_behalf_of_user_id,
+    ADD INDEX idx_on_behalf_of_user_id (on_behalf_of_user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE approval_signatures
+    DROP INDEX idx_on_behalf_of_user_id,
+    DROP COLUMN delegation_id,
+    DROP COLUMN on_behalf_of_user_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS approval_delegations;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS notifications (
+    id CHAR(36) PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    type VARCHAR(50) NOT NULL COMMENT 'approval_request, approval_closed, stage_changed, transaction_rejected, ...',
+    title VARCHAR(255) NOT NULL,
+    body TEXT NULL,
+    transaction_number VARCHAR(100) NULL COMMENT 'Deep link ke transaksi',
+    transaction_type VARCHAR(50) NULL,
+    stage VARCHAR(50) NULL COMMENT 'Stage transaksi saat notifikasi dibuat',
+    transaction_approval_id CHAR(36) NULL,
+    is_read BOOLEAN NOT NULL DEFAULT FALSE,
+    read_at TIMESTAMP NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP NULL,
+
+    INDEX idx_user_unread (user_id, is_read),
+    INDEX idx_user_created (user_id, created_at),
+    INDEX idx_transaction_number (transaction_number),
+    INDEX idx_deleted_at (deleted_at),
+
+    CONSTRAINT fk_notification_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notifications;
+-- +goose StatementEnd
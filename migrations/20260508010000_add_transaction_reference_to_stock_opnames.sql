@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE stock_opnames
+    ADD COLUMN transaction_id BIGINT UNSIGNED NULL AFTER id,
+    ADD COLUMN transaction_number VARCHAR(100) NULL AFTER transaction_id,
+    MODIFY COLUMN status VARCHAR(50) NOT NULL DEFAULT 'DRAFT' COMMENT 'DRAFT, LOCKED, APPROVED, REJECTED',
+    ADD KEY idx_stock_opnames_transaction_id (transaction_id),
+    ADD KEY idx_stock_opnames_transaction_number (transaction_number);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE stock_opnames
+    DROP KEY idx_stock_opnames_transaction_number,
+    DROP KEY idx_stock_opnames_transaction_id,
+    DROP COLUMN transaction_number,
+    DROP COLUMN transaction_id,
+    MODIFY COLUMN status VARCHAR(50) NOT NULL DEFAULT 'DRAFT' COMMENT 'DRAFT, LOCKED, APPROVED';
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE stock_opnames
+    ADD COLUMN branch_code VARCHAR(50) NULL AFTER transaction_number,
+    ADD KEY idx_stock_opnames_branch_period (branch_code, period);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE stock_opnames
+    DROP KEY idx_stock_opnames_branch_period,
+    DROP COLUMN branch_code;
+-- +goose StatementEnd
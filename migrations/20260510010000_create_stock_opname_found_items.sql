@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE transaction_stock_opnames
+    ADD COLUMN counted_branch_code VARCHAR(50) NULL COMMENT 'Cabang tempat asset ditemukan, NULL = sesuai register' AFTER asset_status,
+    ADD COLUMN counted_location VARCHAR(255) NULL COMMENT 'Lokasi tempat asset ditemukan, NULL = sesuai register' AFTER counted_branch_code;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS stock_opname_found_items (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    transaction_number VARCHAR(100) NOT NULL,
+    item_name VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    brand VARCHAR(100) NULL,
+    serial_number VARCHAR(100) NULL,
+    category_id BIGINT UNSIGNED NULL,
+    branch_code VARCHAR(50) NULL,
+    location VARCHAR(255) NULL,
+    `condition` VARCHAR(50) NOT NULL COMMENT 'GOOD, FAIR, POOR, BROKEN',
+    quantity DECIMAL(15,2) NOT NULL DEFAULT 1,
+    estimated_value DECIMAL(18,2) NULL,
+    notes TEXT NULL,
+    found_by CHAR(36) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    INDEX idx_found_items_transaction_id (transaction_id),
+    INDEX idx_found_items_transaction_number (transaction_number),
+    INDEX idx_found_items_category_id (category_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS stock_opname_found_items;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE transaction_stock_opnames
+    DROP COLUMN counted_location,
+    DROP COLUMN counted_branch_code;
+-- +goose StatementEnd
@@ -0,0 +1,105 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE transaction_stock_opnames
+    ADD COLUMN counted_at DATETIME(3) NULL COMMENT 'Timestamp hasil hitung yang berlaku (last writer wins)' AFTER counted_location,
+    ADD COLUMN counted_by CHAR(36) NULL AFTER counted_at,
+    ADD COLUMN counted_device_id VARCHAR(100) NULL COMMENT 'NULL = input dari web' AFTER counted_by,
+    ADD COLUMN client_line_id VARCHAR(64) NULL COMMENT 'Sync line yang terakhir diterapkan' AFTER counted_device_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS stock_opname_sync_lines (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    client_line_id VARCHAR(64) NOT NULL COMMENT 'Dibuat device, kunci idempotensi',
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    transaction_number VARCHAR(100) NOT NULL,
+    asset_stock_opname_id BIGINT UNSIGNED NULL,
+    asset_id BIGINT UNSIGNED NOT NULL,
+    device_id VARCHAR(100) NOT NULL,
+    user_id CHAR(36) NOT NULL,
+    client_timestamp DATETIME(3) NOT NULL,
+    physical_status VARCHAR(50) NOT NULL,
+    `condition` VARCHAR(50) NOT NULL,
+    asset_status VARCHAR(50) NULL,
+    counted_branch_code VARCHAR(50) NULL,
+    counted_location VARCHAR(255) NULL,
+    notes TEXT NULL,
+    result VARCHAR(20) NOT NULL COMMENT 'APPLIED, SUPERSEDED',
+    message VARCHAR(255) NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uq_sync_lines_client_line_id (client_line_id),
+    INDEX idx_sync_lines_transaction_asset (transaction_id, asset_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS stock_opname_sync_conflicts (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    transaction_number VARCHAR(100) NOT NULL,
+    asset_stock_opname_id BIGINT UNSIGNED NULL,
+    asset_id BIGINT UNSIGNED NOT NULL,
+    asset_number VARCHAR(100) NOT NULL,
+    resolution VARCHAR(50) NOT NULL DEFAULT 'LAST_WRITER_WINS',
+    winner_client_line_id VARCHAR(64) NULL COMMENT 'NULL = input dari web',
+    winner_device_id VARCHAR(100) NULL,
+    winner_user_id CHAR(36) NULL,
+    winner_timestamp DATETIME(3) NOT NULL,
+    winner_values JSON NOT NULL,
+    loser_client_line_id VARCHAR(64) NULL,
+    loser_device_id VARCHAR(100) NULL,
+    loser_user_id CHAR(36) NULL,
+    loser_timestamp DATETIME(3) NOT NULL,
+    loser_values JSON NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    INDEX idx_sync_conflicts_transaction_id (transaction_id),
+    INDEX idx_sync_conflicts_asset_id (asset_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS stock_opname_count_photos (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    client_photo_id VARCHAR(64) NOT NULL COMMENT 'Dibuat device, kunci idempotensi',
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    transaction_number VARCHAR(100) NOT NULL,
+    asset_stock_opname_id BIGINT UNSIGNED NULL,
+    asset_id BIGINT UNSIGNED NOT NULL,
+    client_line_id VARCHAR(64) NULL,
+    device_id VARCHAR(100) NULL,
+    file_name VARCHAR(255) NOT NULL,
+    file_path VARCHAR(500) NOT NULL,
+    file_size BIGINT NULL,
+    mime_type VARCHAR(100) NULL,
+    taken_at DATETIME(3) NULL,
+    uploaded_by CHAR(36) NOT NULL,
+    uploaded_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uq_count_photos_client_photo_id (client_photo_id),
+    INDEX idx_count_photos_transaction_asset (transaction_id, asset_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS stock_opname_count_photos;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS stock_opname_sync_conflicts;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS stock_opname_sync_lines;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE transaction_stock_opnames
+    DROP COLUMN client_line_id,
+    DROP COLUMN counted_device_id,
+    DROP COLUMN counted_by,
+    DROP COLUMN counted_at;
+-- +goose StatementEnd
@@ -0,0 +1,61 @@
+-- +goose Up
+
+-- +goose StatementBegin
+ALTER TABLE document_number_sequences
+    MODIFY COLUMN sequence_type ENUM('IO', 'ASSET', 'DN', 'MIG') NOT NULL
+        COMMENT 'IO = Investment Order, ASSET = Asset Number, DN = Document Number, MIG = Dokumen migrasi data lama';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS asset_imports (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    file_name VARCHAR(255) NOT NULL,
+    file_type VARCHAR(10) NOT NULL COMMENT 'CSV, XLSX',
+    sheet_name VARCHAR(100) NULL,
+    column_mapping JSON NULL COMMENT 'field → header di file',
+    total_rows INT NOT NULL DEFAULT 0,
+    imported_rows INT NOT NULL DEFAULT 0,
+    failed_rows INT NOT NULL DEFAULT 0,
+    batch_size INT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL COMMENT 'PROCESSING, COMPLETED, PARTIAL, FAILED',
+    errors JSON NULL COMMENT 'Error per baris',
+    created_by CHAR(36) NOT NULL,
+    started_at DATETIME NOT NULL,
+    finished_at DATETIME NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    INDEX idx_asset_imports_status (status),
+    INDEX idx_asset_imports_created_by (created_by)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE asset_acquisitions
+    ADD COLUMN asset_import_id BIGINT UNSIGNED NULL
+        COMMENT 'Diisi kalau asset berasal dari import data lama'
+        AFTER transaction_procurement_id,
+    ADD INDEX idx_asset_acq_import_id (asset_import_id),
+    ADD CONSTRAINT fk_asset_acq_import
+        FOREIGN KEY (asset_import_id) REFERENCES asset_imports(id)
+        ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+
+-- +goose StatementBegin
+ALTER TABLE asset_acquisitions
+    DROP FOREIGN KEY fk_asset_acq_import,
+    DROP INDEX idx_asset_acq_import_id,
+    DROP COLUMN asset_import_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS asset_imports;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE document_number_sequences
+    MODIFY COLUMN sequence_type ENUM('IO', 'ASSET', 'DN') NOT NULL
+        COMMENT 'IO = Investment Order, ASSET = Asset Number, DN = Document Number';
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Satu baris per versi tanda tangan; file lama tidak pernah dihapus karena
+-- approval_signatures.signature_path dokumen lama masih menunjuk ke sana
+CREATE TABLE IF NOT EXISTS user_signatures (
+    id CHAR(36) PRIMARY KEY,
+    user_id CHAR(36) NOT NULL,
+    version INT NOT NULL,
+    file_path VARCHAR(255) NOT NULL,
+    mime_type VARCHAR(50) NOT NULL,
+    file_size BIGINT NOT NULL DEFAULT 0,
+    source ENUM('upload','drawn') NOT NULL DEFAULT 'upload' COMMENT 'upload file atau digambar di aplikasi (PNG)',
+    is_active BOOLEAN NOT NULL DEFAULT TRUE COMMENT 'Hanya satu versi aktif per user',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+
+    UNIQUE KEY uq_user_signature_version (user_id, version),
+    INDEX idx_user_signature_active (user_id, is_active),
+
+    CONSTRAINT fk_user_signature_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE approval_flows
+    ADD COLUMN require_signature BOOLEAN NOT NULL DEFAULT FALSE
+        COMMENT 'Approver wajib punya tanda tangan aktif sebelum approve'
+        AFTER escalation_user_id;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE approval_flows DROP COLUMN require_signature;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_signatures;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE approval_signatures
+    ADD COLUMN request_id VARCHAR(100) NULL
+        COMMENT 'X-Request-ID request yang memicu approve / reject'
+        AFTER user_agent;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE approval_signatures DROP COLUMN request_id;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE depreciation_settings
+    ADD COLUMN residual_type VARCHAR(20) NULL DEFAULT NULL
+        COMMENT 'PERCENTAGE dari nilai perolehan atau AMOUNT nominal tetap'
+        AFTER depreciation_rate,
+    ADD COLUMN residual_value DECIMAL(18,2) NULL DEFAULT NULL
+        COMMENT 'Nilai sisa, NULL = tanpa nilai sisa'
+        AFTER residual_type,
+    MODIFY COLUMN calculation_method VARCHAR(50) NOT NULL
+        COMMENT 'STRAIGHT_LINE, DECLINING_BALANCE, SUM_OF_YEARS_DIGITS, DOUBLE_DECLINING';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE depreciation_settings
+    DROP COLUMN residual_value,
+    DROP COLUMN residual_type,
+    MODIFY COLUMN calculation_method VARCHAR(50) NOT NULL
+        COMMENT 'STRAIGHT_LINE, DECLINING_BALANCE, etc';
+-- +goose StatementEnd
@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS depreciation_books (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    book_code VARCHAR(20) NOT NULL,
+    book_name VARCHAR(100) NOT NULL,
+    description TEXT NULL,
+    drives_asset_value TINYINT(1) NOT NULL DEFAULT 0 COMMENT '1 = kalkulasi buku ini menggerakkan chain asset_values',
+    is_active TINYINT(1) NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_depreciation_book_code (book_code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Depreciation books (commercial / fiscal)';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO depreciation_books (book_code, book_name, description, drives_asset_value, is_active) VALUES
+    ('COMMERCIAL', 'Komersial (PSAK)', 'Buku akuntansi komersial, menjadi dasar nilai buku asset', 1, 1),
+    ('FISCAL', 'Fiskal (Pajak)', 'Penyusutan fiskal sesuai kelompok harta Pasal 11 UU PPh', 0, 1);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE depreciation_settings
+    ADD COLUMN book_code VARCHAR(20) NOT NULL DEFAULT 'COMMERCIAL' AFTER id,
+    ADD COLUMN tax_group VARCHAR(30) NULL DEFAULT NULL
+        COMMENT 'Kelompok harta fiskal: KELOMPOK_1-4, BANGUNAN_PERMANEN, BANGUNAN_TIDAK_PERMANEN'
+        AFTER residual_value,
+    DROP INDEX idx_dep_settings_reference,
+    ADD KEY idx_dep_settings_reference (book_code, setting_type, reference_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE monthly_depreciation_calculations
+    ADD COLUMN book_code VARCHAR(20) NOT NULL DEFAULT 'COMMERCIAL' AFTER period,
+    ADD UNIQUE KEY idx_monthly_dep_asset_period_book (asset_id, period, book_code),
+    DROP INDEX idx_monthly_dep_asset_period,
+    ADD KEY idx_monthly_dep_period_book (period, book_code);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM monthly_depreciation_calculations WHERE book_code <> 'COMMERCIAL';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE monthly_depreciation_calculations
+    ADD UNIQUE KEY idx_monthly_dep_asset_period (asset_id, period),
+    DROP INDEX idx_monthly_dep_asset_period_book,
+    DROP INDEX idx_monthly_dep_period_book,
+    DROP COLUMN book_code;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DELETE FROM depreciation_settings WHERE book_code <> 'COMMERCIAL';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE depreciation_settings
+    DROP INDEX idx_dep_settings_reference,
+    ADD KEY idx_dep_settings_reference (setting_type, reference_id),
+    DROP COLUMN tax_group,
+    DROP COLUMN book_code;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE IF EXISTS depreciation_books;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE monthly_depreciation_calculations
+    ADD COLUMN asset_value_id BIGINT UNSIGNED NULL DEFAULT NULL
+        COMMENT 'asset_values yang dibuat kalkulasi ini'
+        AFTER depreciation_setting_id,
+    ADD COLUMN previous_asset_value_id BIGINT UNSIGNED NULL DEFAULT NULL
+        COMMENT 'asset_values aktif sebelum kalkulasi, dipulihkan saat reversal'
+        AFTER asset_value_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS depreciation_period_reversals (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    period VARCHAR(7) NOT NULL,
+    book_code VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL,
+    was_locked TINYINT(1) NOT NULL DEFAULT 0,
+    calculation_count INT NOT NULL DEFAULT 0,
+    restored_asset_value_count INT NOT NULL DEFAULT 0,
+    rebased_asset_value_count INT NOT NULL DEFAULT 0,
+    rebased_assets JSON NULL COMMENT 'Asset yang AssetValue sesudahnya (disposal / stock opname) digeser, bukan dipulihkan',
+    calculations_snapshot JSON NULL COMMENT 'Baris monthly_depreciation_calculations yang dihapus',
+    reversed_by VARCHAR(100) NOT NULL,
+    metadata JSON NULL COMMENT 'ip_address / user_agent / request_id',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_dep_reversal_period_book (period, book_code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci COMMENT='Audit reversal depreciation period';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS depreciation_period_reversals;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE monthly_depreciation_calculations
+    DROP COLUMN previous_asset_value_id,
+    DROP COLUMN asset_value_id;
+-- +goose StatementEnd
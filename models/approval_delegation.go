@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ApprovalDelegation - wewenang approval user (delegator) dialihkan ke user lain
+// (delegate) selama periode tertentu, misal saat cuti.
+// FlowCodes / TransactionTypes berupa JSON array, NULL = berlaku untuk semua
+type ApprovalDelegation struct {
+	ID               string         `gorm:"type:char(36);primaryKey" json:"id"`
+	DelegatorID      string         `gorm:"type:char(36);not null;index" json:"delegator_id"`
+	DelegateID       string         `gorm:"type:char(36);not null;index" json:"delegate_id"`
+	StartDate        time.Time      `gorm:"not null" json:"start_date"`
+	EndDate          time.Time      `gorm:"not null" json:"end_date"`
+	FlowCodes        *string        `gorm:"type:json" json:"flow_codes"`
+	TransactionTypes *string        `gorm:"type:json" json:"transaction_types"`
+	Reason           *string        `gorm:"type:text" json:"reason"`
+	IsActive         bool           `gorm:"type:boolean;default:true" json:"is_active"`
+	RevokedAt        *time.Time     `json:"revoked_at"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+
+	// Relations
+	Delegator *User `gorm:"foreignKey:DelegatorID" json:"delegator,omitempty"`
+	Delegate  *User `gorm:"foreignKey:DelegateID" json:"delegate,omitempty"`
+}
+
+func (ad *ApprovalDelegation) BeforeCreate(tx *gorm.DB) error {
+	if ad.ID == "" {
+		ad.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (ApprovalDelegation) TableName() string {
+	return "approval_delegations"
+}
@@ -14,6 +14,14 @@ type ApprovalFlow struct {
 	FlowName    string `gorm:"type:varchar(100);not null" json:"flow_name"`
 	ApprovalWay string `gorm:"type:enum('sequential','parallel','conditional');default:'sequential'" json:"approval_way"`
 
+	// Timeout & Escalation — dipakai scheduler untuk step yang melewati timeout_hours
+	TimeoutAction    string  `gorm:"type:enum('none','escalate','skip','reject');default:'none'" json:"timeout_action"`
+	EscalationRoleID *string `gorm:"type:char(36)" json:"escalation_role_id"`
+	EscalationUserID *string `gorm:"type:char(36)" json:"escalation_user_id"`
+
+	// Approver wajib punya tanda tangan aktif (UserSignature) sebelum approve
+	RequireSignature bool `gorm:"type:boolean;not null;default:false" json:"require_signature"`
+
 	// Assignment System
 	AssignmentType string  `gorm:"type:enum('general','user_specific');default:'general'" json:"assignment_type"`
 	AssignedUserID *string `gorm:"type:char(36)" json:"assigned_user_id"`
@@ -59,3 +67,11 @@ func (af *ApprovalFlow) BeforeCreate(tx *gorm.DB) error {
 func (ApprovalFlow) TableName() string {
 	return "approval_flows"
 }
+
+// Timeout actions
+const (
+	TimeoutActionNone     = "none"
+	TimeoutActionEscalate = "escalate"
+	TimeoutActionSkip     = "skip"
+	TimeoutActionReject   = "reject"
+)
@@ -8,6 +8,7 @@ type AssetAcquisition struct {
 	TransactionID            *uint      `gorm:"index" json:"transaction_id"`
 	TransactionNumber        string     `gorm:"size:100;index" json:"transaction_number"`
 	TransactionProcurementID *uint      `gorm:"index" json:"transaction_procurement_id"` // ADD
+	AssetImportID            *uint      `gorm:"index" json:"asset_import_id"`
 	TransactionDate          time.Time  `gorm:"type:date;not null;index" json:"transaction_date"`
 	AssetID                  *uint      `gorm:"index" json:"asset_id"`
 	AssetNumber              string     `gorm:"size:100" json:"asset_number"`
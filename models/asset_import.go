@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// ============================================================
+// Constants
+// ============================================================
+
+const (
+	AssetImportStatusValidated  = "VALIDATED" // dry run, tidak disimpan
+	AssetImportStatusProcessing = "PROCESSING"
+	AssetImportStatusCompleted  = "COMPLETED"
+	AssetImportStatusPartial    = "PARTIAL"
+	AssetImportStatusFailed     = "FAILED"
+
+	// assets.record_type untuk asset hasil import data lama
+	AssetRecordTypeMigration = "MIGRATION"
+)
+
+// ============================================================
+// AssetImport — satu kali import register asset dari CSV / XLSX
+// Error per baris disimpan sebagai JSON supaya bisa dicek ulang admin
+// ============================================================
+
+type AssetImport struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	FileName      string     `gorm:"size:255;not null" json:"file_name"`
+	FileType      string     `gorm:"size:10;not null" json:"file_type"`
+	SheetName     *string    `gorm:"size:100" json:"sheet_name"`
+	ColumnMapping *string    `gorm:"type:json" json:"column_mapping"`
+	TotalRows     int        `gorm:"not null;default:0" json:"total_rows"`
+	ImportedRows  int        `gorm:"not null;default:0" json:"imported_rows"`
+	FailedRows    int        `gorm:"not null;default:0" json:"failed_rows"`
+	BatchSize     int        `gorm:"not null;default:0" json:"batch_size"`
+	Status        string     `gorm:"size:20;not null;index" json:"status"`
+	Errors        *string    `gorm:"type:json" json:"errors"`
+	CreatedBy     string     `gorm:"size:36;not null;index" json:"created_by"`
+	StartedAt     time.Time  `gorm:"not null" json:"started_at"`
+	FinishedAt    *time.Time `json:"finished_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+func (AssetImport) TableName() string { return "asset_imports" }
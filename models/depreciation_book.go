@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+// DepreciationBook - buku depresiasi (komersial PSAK / fiskal pajak).
+// Setiap buku punya DepreciationSetting & MonthlyDepreciationCalculation sendiri
+type DepreciationBook struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	BookCode         string    `gorm:"size:20;not null;uniqueIndex" json:"book_code"`
+	BookName         string    `gorm:"size:100;not null" json:"book_name"`
+	Description      *string   `gorm:"type:text" json:"description"`
+	DrivesAssetValue bool      `gorm:"not null;default:false" json:"drives_asset_value"` // hanya satu buku yang menggerakkan chain AssetValue
+	IsActive         bool      `gorm:"not null;default:true" json:"is_active"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+func (DepreciationBook) TableName() string { return "depreciation_books" }
+
+const (
+	DepreciationBookCommercial = "COMMERCIAL"
+	DepreciationBookFiscal     = "FISCAL"
+)
+
+// ============================================================
+// KELOMPOK HARTA FISKAL
+// Masa manfaat & tarif penyusutan sesuai Pasal 11 UU PPh.
+// Tarif dalam persen per tahun
+// ============================================================
+
+type FiscalTaxGroup struct {
+	Code             string   `json:"code"`
+	Name             string   `json:"name"`
+	UsefulLifeYears  int      `json:"useful_life_years"`
+	StraightLineRate float64  `json:"straight_line_rate"`
+	DecliningRate    *float64 `json:"declining_rate"` // NULL = bangunan, hanya garis lurus
+}
+
+const (
+	FiscalGroupKelompok1             = "KELOMPOK_1"
+	FiscalGroupKelompok2             = "KELOMPOK_2"
+	FiscalGroupKelompok3             = "KELOMPOK_3"
+	FiscalGroupKelompok4             = "KELOMPOK_4"
+	FiscalGroupBangunanPermanen      = "BANGUNAN_PERMANEN"
+	FiscalGroupBangunanTidakPermanen = "BANGUNAN_TIDAK_PERMANEN"
+)
+
+func fiscalRate(rate float64) *float64 { return &rate }
+
+var FiscalTaxGroups = []FiscalTaxGroup{
+	{Code: FiscalGroupKelompok1, Name: "Kelompok 1", UsefulLifeYears: 4, StraightLineRate: 25, DecliningRate: fiscalRate(50)},
+	{Code: FiscalGroupKelompok2, Name: "Kelompok 2", UsefulLifeYears: 8, StraightLineRate: 12.5, DecliningRate: fiscalRate(25)},
+	{Code: FiscalGroupKelompok3, Name: "Kelompok 3", UsefulLifeYears: 16, StraightLineRate: 6.25, DecliningRate: fiscalRate(12.5)},
+	{Code: FiscalGroupKelompok4, Name: "Kelompok 4", UsefulLifeYears: 20, StraightLineRate: 5, DecliningRate: fiscalRate(10)},
+	{Code: FiscalGroupBangunanPermanen, Name: "Bangunan Permanen", UsefulLifeYears: 20, StraightLineRate: 5},
+	{Code: FiscalGroupBangunanTidakPermanen, Name: "Bangunan Tidak Permanen", UsefulLifeYears: 10, StraightLineRate: 10},
+}
+
+func FindFiscalTaxGroup(code string) (FiscalTaxGroup, bool) {
+	for _, group := range FiscalTaxGroups {
+		if group.Code == code {
+			return group, true
+		}
+	}
+	return FiscalTaxGroup{}, false
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// DepreciationPeriodReversal - audit pembatalan kalkulasi depresiasi satu periode + buku.
+// Baris kalkulasi yang dihapus disimpan di CalculationsSnapshot.
+// RebasedAssets = asset yang sudah punya AssetValue sesudah kalkulasi (disposal / stock opname),
+// nilainya digeser sebesar depresiasi yang dibatalkan
+type DepreciationPeriodReversal struct {
+	ID                      uint      `gorm:"primaryKey" json:"id"`
+	Period                  string    `gorm:"size:7;not null;index:idx_dep_reversal_period_book" json:"period"`
+	BookCode                string    `gorm:"size:20;not null;index:idx_dep_reversal_period_book" json:"book_code"`
+	Reason                  string    `gorm:"type:text;not null" json:"reason"`
+	WasLocked               bool      `gorm:"not null;default:false" json:"was_locked"`
+	CalculationCount        int       `gorm:"not null;default:0" json:"calculation_count"`
+	RestoredAssetValueCount int       `gorm:"not null;default:0" json:"restored_asset_value_count"`
+	RebasedAssetValueCount  int       `gorm:"not null;default:0" json:"rebased_asset_value_count"`
+	RebasedAssets           *string   `gorm:"type:json" json:"rebased_assets"`
+	CalculationsSnapshot    *string   `gorm:"type:json" json:"calculations_snapshot"`
+	ReversedBy              string    `gorm:"size:100;not null" json:"reversed_by"`
+	Metadata                *string   `gorm:"type:json" json:"metadata"` // ip / user agent / request id
+	CreatedAt               time.Time `json:"created_at"`
+}
+
+func (DepreciationPeriodReversal) TableName() string { return "depreciation_period_reversals" }
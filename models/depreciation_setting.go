@@ -4,6 +4,7 @@ import "time"
 
 type DepreciationSetting struct {
 	ID                 uint       `gorm:"primaryKey" json:"id"`
+	BookCode           string     `gorm:"size:20;not null;default:'COMMERCIAL';index" json:"book_code"`
 	SettingType        string     `gorm:"size:50;not null;index" json:"setting_type"`
 	ReferenceID        *uint      `gorm:"column:reference_id;index" json:"reference_id"`          // FIX: ganti dari CategoryID/AssetID
 	ReferenceValue     *string    `gorm:"column:reference_value;size:255" json:"reference_value"` // FIX: tambah
@@ -11,6 +12,9 @@ type DepreciationSetting struct {
 	DepreciationPeriod string     `gorm:"size:50;not null" json:"depreciation_period"`
 	UsefulLifeMonths   int        `gorm:"not null" json:"useful_life_months"`
 	DepreciationRate   *float64   `gorm:"type:decimal(10,4)" json:"depreciation_rate"` // FIX: decimal(10,4) & nullable
+	ResidualType       *string    `gorm:"size:20" json:"residual_type"`                // PERCENTAGE dari nilai perolehan / AMOUNT nominal tetap
+	ResidualValue      *float64   `gorm:"type:decimal(18,2)" json:"residual_value"`    // NULL = tanpa nilai sisa
+	TaxGroup           *string    `gorm:"size:30" json:"tax_group"`                    // buku fiskal: kelompok harta (FiscalTaxGroups)
 	StartDate          time.Time  `gorm:"type:date;not null" json:"start_date"`        // FIX: tambah
 	EndDate            *time.Time `gorm:"type:date" json:"end_date"`                   // FIX: tambah
 	IsActive           bool       `gorm:"not null;default:true;index" json:"is_active"`
@@ -28,6 +32,13 @@ const (
 const (
 	CalculationMethodStraightLine     = "STRAIGHT_LINE"
 	CalculationMethodDecliningBalance = "DECLINING_BALANCE"
+	CalculationMethodSumOfYearsDigits = "SUM_OF_YEARS_DIGITS"
+	CalculationMethodDoubleDeclining  = "DOUBLE_DECLINING"
+)
+
+const (
+	ResidualTypePercentage = "PERCENTAGE"
+	ResidualTypeAmount     = "AMOUNT"
 )
 
 const (
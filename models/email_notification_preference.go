@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// EmailNotificationPreference - opt in / opt out email per event.
+// Tidak ada baris = enabled (default opt in)
+type EmailNotificationPreference struct {
+	ID        string    `gorm:"type:char(36);primaryKey" json:"id"`
+	UserID    string    `gorm:"type:char(36);not null;uniqueIndex:uq_user_event" json:"user_id"`
+	EventType string    `gorm:"type:varchar(50);not null;uniqueIndex:uq_user_event" json:"event_type"`
+	Enabled   bool      `gorm:"not null;default:true" json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (p *EmailNotificationPreference) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (EmailNotificationPreference) TableName() string {
+	return "email_notification_preferences"
+}
@@ -4,9 +4,10 @@ import "time"
 
 type MonthlyDepreciationCalculation struct {
 	ID                               uint      `gorm:"primaryKey" json:"id"`
-	AssetID                          uint      `gorm:"not null;index" json:"asset_id"`
-	Period                           string    `gorm:"size:7;not null;uniqueIndex:idx_asset_period" json:"period"` // FIX: "YYYY-MM"
-	CalculationDate                  time.Time `gorm:"type:date;not null" json:"calculation_date"`                 // FIX: tambah
+	AssetID                          uint      `gorm:"not null;index;uniqueIndex:idx_asset_period_book" json:"asset_id"`
+	Period                           string    `gorm:"size:7;not null;uniqueIndex:idx_asset_period_book" json:"period"` // FIX: "YYYY-MM"
+	BookCode                         string    `gorm:"size:20;not null;default:'COMMERCIAL';uniqueIndex:idx_asset_period_book" json:"book_code"`
+	CalculationDate                  time.Time `gorm:"type:date;not null" json:"calculation_date"` // FIX: tambah
 	BeginningBookValue               float64   `gorm:"type:decimal(18,2);not null;default:0" json:"beginning_book_value"`
 	DepreciationAmount               float64   `gorm:"type:decimal(18,2);not null;default:0" json:"depreciation_amount"`
 	BeginningAccumulatedDepreciation float64   `gorm:"type:decimal(18,2);not null;default:0" json:"beginning_accumulated_depreciation"` // FIX: tambah
@@ -14,6 +15,8 @@ type MonthlyDepreciationCalculation struct {
 	EndingBookValue                  float64   `gorm:"type:decimal(18,2);not null;default:0" json:"ending_book_value"`
 	CalculationMethod                *string   `gorm:"size:50" json:"calculation_method"`    // FIX: nullable sesuai migration
 	DepreciationSettingID            *uint     `gorm:"index" json:"depreciation_setting_id"` // FIX: tambah
+	AssetValueID                     *uint     `json:"asset_value_id"`                       // AssetValue yang dibuat kalkulasi ini (buku komersial)
+	PreviousAssetValueID             *uint     `json:"previous_asset_value_id"`              // AssetValue aktif sebelum kalkulasi, dipulihkan saat reversal
 	IsLocked                         bool      `gorm:"not null;default:false;index" json:"is_locked"`
 	CreatedAt                        time.Time `json:"created_at"`
 	UpdatedAt                        time.Time `json:"updated_at"`
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Notification - inbox notifikasi in-app per user.
+// TransactionNumber + Stage dipakai frontend untuk deep link ke transaksi
+type Notification struct {
+	ID                    string         `gorm:"type:char(36);primaryKey" json:"id"`
+	UserID                string         `gorm:"type:char(36);not null;index" json:"user_id"`
+	Type                  string         `gorm:"type:varchar(50);not null" json:"type"`
+	Title                 string         `gorm:"type:varchar(255);not null" json:"title"`
+	Body                  *string        `gorm:"type:text" json:"body"`
+	TransactionNumber     *string        `gorm:"type:varchar(100);index" json:"transaction_number"`
+	TransactionType       *string        `gorm:"type:varchar(50)" json:"transaction_type"`
+	Stage                 *string        `gorm:"type:varchar(50)" json:"stage"`
+	TransactionApprovalID *string        `gorm:"type:char(36)" json:"transaction_approval_id"`
+	IsRead                bool           `gorm:"type:boolean;default:false" json:"is_read"`
+	ReadAt                *time.Time     `json:"read_at"`
+	CreatedAt             time.Time      `json:"created_at"`
+	UpdatedAt             time.Time      `json:"updated_at"`
+	DeletedAt             gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`
+}
+
+func (n *Notification) BeforeCreate(tx *gorm.DB) error {
+	if n.ID == "" {
+		n.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
@@ -38,8 +38,9 @@ const (
 
 // Document Sequence Types
 const (
-	SeqTypeIO    = "IO"
-	SeqTypeAsset = "ASSET"
+	SeqTypeIO        = "IO"
+	SeqTypeAsset     = "ASSET"
+	SeqTypeMigration = "MIG" // dokumen acquisition hasil import data lama
 )
 
 // Asset Status tambahan
@@ -100,7 +101,7 @@ func (TransactionItemVerification) TableName() string { return "transaction_item
 // ============================================================
 type DocumentNumberSequence struct {
 	ID            uint      `gorm:"primaryKey" json:"id"`
-	SequenceType  string    `gorm:"type:enum('IO','ASSET');not null;uniqueIndex:uq_sequence" json:"sequence_type"`
+	SequenceType  string    `gorm:"type:enum('IO','ASSET','DN','MIG');not null;uniqueIndex:uq_sequence" json:"sequence_type"`
 	ReferenceCode string    `gorm:"size:50;not null;uniqueIndex:uq_sequence" json:"reference_code"` // branch_code untuk IO, category_code untuk ASSET
 	LastSequence  uint      `gorm:"not null;default:0" json:"last_sequence"`
 	CreatedAt     time.Time `json:"created_at"`
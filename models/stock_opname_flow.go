@@ -0,0 +1,31 @@
+package models
+
+// ============================================================
+// Constants — Stage
+// DRAFT → COUNTING → REVIEW → APPROVAL → RECONCILED
+// ============================================================
+
+const (
+	StageOpnameDraft      = "DRAFT"
+	StageOpnameCounting   = "COUNTING"   // tim lapangan isi hasil hitung fisik
+	StageOpnameReview     = "REVIEW"     // hasil hitung dikunci, dicek tim asset
+	StageOpnameApproval   = "APPROVAL"   // approval flow STOCK_OPNAME_APPROVAL
+	StageOpnameReconciled = "RECONCILED" // hasil hitung ditulis ke asset value
+	StageOpnameRejected   = "REJECTED"
+)
+
+// ============================================================
+// Constants — Stage Action (tambahan untuk stock opname)
+// ============================================================
+
+const (
+	ActionStartCounting = "START_COUNTING"
+	ActionSubmitCount   = "SUBMIT_COUNT"
+	ActionReconcile     = "RECONCILE"
+)
+
+// ============================================================
+// Constants — Approval Flow Code
+// ============================================================
+
+const FlowStockOpnameApproval = "STOCK_OPNAME_APPROVAL"
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// StockOpnameFoundItem - barang yang ditemukan di lokasi saat counting
+// tapi tidak terdaftar di register asset
+type StockOpnameFoundItem struct {
+	ID                uint      `gorm:"primaryKey" json:"id"`
+	TransactionID     uint      `gorm:"not null;index" json:"transaction_id"`
+	TransactionNumber string    `gorm:"size:100;not null;index" json:"transaction_number"`
+	ItemName          string    `gorm:"size:255;not null" json:"item_name"`
+	Description       *string   `gorm:"type:text" json:"description"`
+	Brand             *string   `gorm:"size:100" json:"brand"`
+	SerialNumber      *string   `gorm:"size:100" json:"serial_number"`
+	CategoryID        *uint     `gorm:"index" json:"category_id"`
+	BranchCode        *string   `gorm:"size:50" json:"branch_code"`
+	Location          *string   `gorm:"size:255" json:"location"`
+	Condition         string    `gorm:"column:condition;size:50;not null" json:"condition"`
+	Quantity          float64   `gorm:"type:decimal(15,2);not null;default:1" json:"quantity"`
+	EstimatedValue    *float64  `gorm:"type:decimal(18,2)" json:"estimated_value"`
+	Notes             *string   `gorm:"type:text" json:"notes"`
+	FoundBy           string    `gorm:"size:36;not null" json:"found_by"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+
+	Category *AssetCategory `gorm:"foreignKey:CategoryID" json:"category,omitempty"`
+}
+
+func (StockOpnameFoundItem) TableName() string { return "stock_opname_found_items" }
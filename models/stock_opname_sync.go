@@ -0,0 +1,98 @@
+package models
+
+import "time"
+
+// ============================================================
+// Constants
+// ============================================================
+
+const (
+	SyncLineApplied    = "APPLIED"
+	SyncLineSuperseded = "SUPERSEDED" // kalah last-writer-wins
+	// Hanya di response, tidak disimpan:
+	SyncLineRejected  = "REJECTED"  // tidak valid / stage bukan COUNTING, boleh dikirim ulang
+	SyncLineDuplicate = "DUPLICATE" // client_line_id sudah pernah diterima
+
+	SyncResolutionLastWriterWins = "LAST_WRITER_WINS"
+)
+
+// ============================================================
+// StockOpnameSyncLine — satu baris hasil hitung dari device
+// client_line_id unik → upload ulang tidak diproses dua kali
+// ============================================================
+
+type StockOpnameSyncLine struct {
+	ID                 uint      `gorm:"primaryKey" json:"id"`
+	ClientLineID       string    `gorm:"size:64;not null;uniqueIndex" json:"client_line_id"`
+	TransactionID      uint      `gorm:"not null;index" json:"transaction_id"`
+	TransactionNumber  string    `gorm:"size:100;not null" json:"transaction_number"`
+	AssetStockOpnameID *uint     `json:"asset_stock_opname_id"`
+	AssetID            uint      `gorm:"not null" json:"asset_id"`
+	DeviceID           string    `gorm:"size:100;not null" json:"device_id"`
+	UserID             string    `gorm:"size:36;not null" json:"user_id"`
+	ClientTimestamp    time.Time `gorm:"type:datetime(3);not null" json:"client_timestamp"`
+	PhysicalStatus     string    `gorm:"size:50;not null" json:"physical_status"`
+	Condition          string    `gorm:"column:condition;size:50;not null" json:"condition"`
+	AssetStatus        *string   `gorm:"size:50" json:"asset_status"`
+	CountedBranchCode  *string   `gorm:"size:50" json:"counted_branch_code"`
+	CountedLocation    *string   `gorm:"size:255" json:"counted_location"`
+	Notes              *string   `gorm:"type:text" json:"notes"`
+	Result             string    `gorm:"size:20;not null" json:"result"`
+	Message            *string   `gorm:"size:255" json:"message"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+func (StockOpnameSyncLine) TableName() string { return "stock_opname_sync_lines" }
+
+// ============================================================
+// StockOpnameSyncConflict — dua device / web mengisi asset yang sama
+// dengan hasil berbeda. Winner sudah diterapkan, loser hanya dicatat.
+// ============================================================
+
+type StockOpnameSyncConflict struct {
+	ID                 uint      `gorm:"primaryKey" json:"id"`
+	TransactionID      uint      `gorm:"not null;index" json:"transaction_id"`
+	TransactionNumber  string    `gorm:"size:100;not null" json:"transaction_number"`
+	AssetStockOpnameID *uint     `json:"asset_stock_opname_id"`
+	AssetID            uint      `gorm:"not null;index" json:"asset_id"`
+	AssetNumber        string    `gorm:"size:100;not null" json:"asset_number"`
+	Resolution         string    `gorm:"size:50;not null;default:LAST_WRITER_WINS" json:"resolution"`
+	WinnerClientLineID *string   `gorm:"size:64" json:"winner_client_line_id"` // nil = input dari web
+	WinnerDeviceID     *string   `gorm:"size:100" json:"winner_device_id"`
+	WinnerUserID       *string   `gorm:"size:36" json:"winner_user_id"`
+	WinnerTimestamp    time.Time `gorm:"type:datetime(3);not null" json:"winner_timestamp"`
+	WinnerValues       string    `gorm:"type:json;not null" json:"winner_values"`
+	LoserClientLineID  *string   `gorm:"size:64" json:"loser_client_line_id"`
+	LoserDeviceID      *string   `gorm:"size:100" json:"loser_device_id"`
+	LoserUserID        *string   `gorm:"size:36" json:"loser_user_id"`
+	LoserTimestamp     time.Time `gorm:"type:datetime(3);not null" json:"loser_timestamp"`
+	LoserValues        string    `gorm:"type:json;not null" json:"loser_values"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+func (StockOpnameSyncConflict) TableName() string { return "stock_opname_sync_conflicts" }
+
+// ============================================================
+// StockOpnameCountPhoto — foto bukti hitung, file di storage attachment
+// ============================================================
+
+type StockOpnameCountPhoto struct {
+	ID                 uint       `gorm:"primaryKey" json:"id"`
+	ClientPhotoID      string     `gorm:"size:64;not null;uniqueIndex" json:"client_photo_id"`
+	TransactionID      uint       `gorm:"not null;index" json:"transaction_id"`
+	TransactionNumber  string     `gorm:"size:100;not null" json:"transaction_number"`
+	AssetStockOpnameID *uint      `json:"asset_stock_opname_id"`
+	AssetID            uint       `gorm:"not null" json:"asset_id"`
+	ClientLineID       *string    `gorm:"size:64" json:"client_line_id"`
+	DeviceID           *string    `gorm:"size:100" json:"device_id"`
+	FileName           string     `gorm:"size:255;not null" json:"file_name"`
+	FilePath           string     `gorm:"size:500;not null" json:"file_path"`
+	FileSize           *int64     `json:"file_size"`
+	MimeType           *string    `gorm:"size:100" json:"mime_type"`
+	TakenAt            *time.Time `gorm:"type:datetime(3)" json:"taken_at"`
+	UploadedBy         string     `gorm:"size:36;not null" json:"uploaded_by"`
+	UploadedAt         time.Time  `gorm:"not null" json:"uploaded_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+func (StockOpnameCountPhoto) TableName() string { return "stock_opname_count_photos" }
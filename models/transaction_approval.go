@@ -23,6 +23,7 @@ type TransactionApproval struct {
 	ApprovedBy        *string        `gorm:"type:char(36)" json:"approved_by"` // user yang actually melakukan approve
 	RejectedAt        *time.Time     `json:"rejected_at"`
 	RejectedBy        *string        `gorm:"type:char(36)" json:"rejected_by"`
+	EscalatedAt       *time.Time     `json:"escalated_at"`              // diisi scheduler saat step di-eskalasi
 	Notes             *string        `gorm:"type:text" json:"notes"`    // catatan dari approver
 	Metadata          *string        `gorm:"type:json" json:"metadata"` // data tambahan (JSON)
 	CreatedAt         time.Time      `json:"created_at"`
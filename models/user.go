@@ -15,6 +15,7 @@ type User struct {
 	Password  string         `gorm:"type:varchar(255);not null" json:"-"`
 	NIK       *string        `gorm:"type:varchar(20);uniqueIndex" json:"nik"` // ← Jadi pointer
 	MPNNumber *string        `gorm:"type:varchar(50)" json:"mpn_number"`      // ← Jadi pointer
+	Language  string         `gorm:"type:varchar(5);not null;default:'id'" json:"language"`
 	Status    string         `gorm:"type:enum('active','inactive');default:'active'" json:"status"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
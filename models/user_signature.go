@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserSignature - versi tanda tangan user. Upload baru menonaktifkan versi lama,
+// tapi file lama tetap disimpan karena masih dipakai ApprovalSignature.SignaturePath
+type UserSignature struct {
+	ID        string    `gorm:"type:char(36);primaryKey" json:"id"`
+	UserID    string    `gorm:"type:char(36);not null;uniqueIndex:uq_user_signature_version" json:"user_id"`
+	Version   int       `gorm:"not null;uniqueIndex:uq_user_signature_version" json:"version"`
+	FilePath  string    `gorm:"type:varchar(255);not null" json:"file_path"`
+	MimeType  string    `gorm:"type:varchar(50);not null" json:"mime_type"`
+	FileSize  int64     `gorm:"not null;default:0" json:"file_size"`
+	Source    string    `gorm:"type:enum('upload','drawn');not null;default:'upload'" json:"source"`
+	IsActive  bool      `gorm:"type:boolean;not null;default:true" json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (us *UserSignature) BeforeCreate(tx *gorm.DB) error {
+	if us.ID == "" {
+		us.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (UserSignature) TableName() string {
+	return "user_signatures"
+}
+
+const (
+	SignatureSourceUpload = "upload"
+	SignatureSourceDrawn  = "drawn"
+)
@@ -58,4 +58,16 @@ func SetupApprovalRoutes(rg *gin.RouterGroup) {
 		// Get current user's pending approvals
 		transactions.GET("/pending", controllers.GetUserPendingApprovals)
 	}
+
+	// ========================================================================
+	// APPROVAL DELEGATION ROUTES
+	// User mengelola delegasi approval miliknya sendiri (cuti / out of office)
+	// ========================================================================
+	delegations := approvalRoutes.Group("/approval-delegations")
+	{
+		delegations.GET("", controllers.GetApprovalDelegations)
+		delegations.POST("", controllers.CreateApprovalDelegation)
+		delegations.PUT("/:id", controllers.UpdateApprovalDelegation)
+		delegations.DELETE("/:id", controllers.RevokeApprovalDelegation)
+	}
 }
@@ -14,5 +14,31 @@ func SetupAssetMasterRoutes(rg *gin.RouterGroup) {
 		assets.GET("", controllers.GetAllAssets)
 		assets.GET("/:number", controllers.GetAssetByNumber)
 		assets.GET("/:number/value-history", controllers.GetAssetValueHistory)
+
+		// ============================================================
+		// LABEL
+		// GET  /assets/:number/label?format=QR|CODE128 → PNG
+		// POST /assets/labels/sheet                    → PDF (asset_numbers / transaction_number procurement)
+		// GET  /assets/labels/lookup?payload=          → asset dari hasil scan
+		// GET  /assets/scan/:number                    → URL di QR label lama (sebelum payload ASSET:)
+		// ============================================================
+		assets.GET("/:number/label", controllers.GetAssetLabel)
+		assets.POST("/labels/sheet", controllers.GetAssetLabelSheet)
+		assets.GET("/labels/lookup", controllers.LookupAssetByLabel)
+		assets.GET("/scan/:number", controllers.ScanAssetLabel)
+
+		// ============================================================
+		// IMPORT DATA LAMA (admin)
+		// POST /assets/imports      → multipart file CSV / XLSX, dry_run=true untuk validasi saja
+		// GET  /assets/imports      → riwayat import
+		// GET  /assets/imports/:id  → detail + error per baris
+		// ============================================================
+		imports := assets.Group("/imports")
+		imports.Use(middleware.RequireRole("admin"))
+		{
+			imports.POST("", controllers.ImportAssets)
+			imports.GET("", controllers.GetAssetImports)
+			imports.GET("/:id", controllers.GetAssetImportByID)
+		}
 	}
 }
@@ -22,6 +22,18 @@ func SetupAuthRoutes(rg *gin.RouterGroup) {
 			authenticated.GET("/me", controllers.GetProfile)
 			authenticated.POST("/logout", controllers.Logout)
 			authenticated.POST("/logout-all", controllers.LogoutAllDevices)
+
+			// Push notification token per device
+			authenticated.POST("/fcm-token", controllers.RegisterFCMToken)
+			authenticated.DELETE("/fcm-token", controllers.UnregisterFCMToken)
+
+			// Tanda tangan untuk approval, versi lama tetap tersimpan
+			authenticated.GET("/me/signatures", controllers.GetMySignatures)
+			authenticated.POST("/me/signatures", controllers.UploadMySignature)
+			authenticated.POST("/me/signatures/draw", controllers.DrawMySignature)
+			authenticated.GET("/me/signatures/:id/image", controllers.GetMySignatureImage)
+			authenticated.PUT("/me/signatures/:id/activate", controllers.ActivateMySignature)
+			authenticated.PUT("/me/signatures/:id/deactivate", controllers.DeactivateMySignature)
 		}
 	}
 }
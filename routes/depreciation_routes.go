@@ -26,16 +26,28 @@ func SetupDepreciationRoutes(rg *gin.RouterGroup) {
 		}
 	}
 
+	// Depreciation Books (komersial / fiskal)
+	books := routes.Group("/depreciation-books")
+	{
+		books.GET("", controllers.GetDepreciationBooks)
+		books.GET("/tax-groups", controllers.GetFiscalTaxGroups)
+	}
+
 	// Monthly Depreciation Calculations
 	depreciation := routes.Group("/depreciation")
 	{
 		depreciation.GET("/monthly", controllers.GetMonthlyDepreciationCalculations)
+		depreciation.GET("/reconciliation", controllers.GetDepreciationReconciliation)
+		depreciation.GET("/forecast/summary", controllers.GetDepreciationForecastSummary)
+		depreciation.GET("/forecast/assets/:id", controllers.ForecastAssetDepreciation)
 
 		adminDepr := depreciation.Group("")
 		adminDepr.Use(middleware.RequireRole("admin"))
 		{
 			adminDepr.POST("/calculate", controllers.CalculateMonthlyDepreciation)
 			adminDepr.POST("/calculations/lock", controllers.LockMonthlyDepreciation)
+			adminDepr.POST("/calculations/reverse", controllers.ReverseMonthlyDepreciation)
+			adminDepr.GET("/reversals", controllers.GetDepreciationReversals)
 		}
 	}
 }
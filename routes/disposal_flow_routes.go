@@ -14,7 +14,7 @@ func SetupDisposalFlowRoutes(rg *gin.RouterGroup) {
 		// ============================================================
 		// DRAFT MANAGEMENT
 		// POST   /transactions/disposal                             → create draft
-		// GET    /transactions/disposal                             → list semua disposal
+		// GET    /transactions/disposal                             → list semua disposal (?export=csv|xlsx|pdf)
 		// GET    /transactions/disposal/detail?transaction_number   → detail + assets + stages
 		// ============================================================
 
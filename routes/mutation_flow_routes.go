@@ -17,6 +17,7 @@ func SetupMutationFlowRoutes(rg *gin.RouterGroup) {
 
 		// POST   /transactions/mutation                          → create draft
 		// GET    /transactions/mutation                          → list semua mutasi user
+		// GET    /transactions/mutation/drafts                   → list mutasi flow (?export=csv|xlsx|pdf)
 		// GET    /transactions/mutation/detail?transaction_number → detail + assets + stages
 		mutation.POST("",
 			middleware.RequirePermission("create_transaction"),
@@ -24,6 +25,8 @@ func SetupMutationFlowRoutes(rg *gin.RouterGroup) {
 
 		mutation.GET("", controllers.GetAllMutations)
 
+		mutation.GET("/drafts", controllers.GetAllMutationsFlow)
+
 		mutation.GET("/detail", controllers.GetMutationDetail)
 
 		mutationDraft := mutation.Group("/draft")
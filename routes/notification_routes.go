@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"backend-go/controllers"
+	"backend-go/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+func SetupNotificationRoutes(rg *gin.RouterGroup) {
+	notifications := rg.Group("/notifications")
+	notifications.Use(middleware.AuthMiddleware())
+	{
+		notifications.GET("", controllers.GetNotifications)
+		notifications.GET("/unread-count", controllers.GetUnreadNotificationCount)
+		notifications.PUT("/read-all", controllers.MarkAllNotificationsRead)
+		notifications.GET("/email-preferences", controllers.GetEmailPreferences)
+		notifications.PUT("/email-preferences", controllers.UpdateEmailPreferences)
+		notifications.PUT("/:id/read", controllers.MarkNotificationRead)
+	}
+}
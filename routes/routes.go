@@ -1,12 +1,14 @@
 package routes
 
 import (
+	"backend-go/utils"
+
 	"github.com/gin-gonic/gin"
 )
 
 func SetupRoutes(r *gin.Engine) {
 	// API v1 group
-	v1 := r.Group("/api/v1")
+	v1 := r.Group(utils.APIPrefix)
 	{
 		// Setup all route modules
 		SetupAuthRoutes(v1)
@@ -30,6 +32,7 @@ func SetupRoutes(r *gin.Engine) {
 		SetupAttachmentRoutes(v1)
 		SetupMutationFlowRoutes(v1)
 		SetupDisposalFlowRoutes(v1)
+		SetupNotificationRoutes(v1)
 	}
 
 	// Health check endpoint (no auth required)
@@ -19,5 +19,128 @@ func SetupStockOpnameRoutes(rg *gin.RouterGroup) {
 		stockOpname.GET("/:number", controllers.GetStockOpnameByNumber)
 		stockOpname.PUT("/:number", controllers.UpdateStockOpname)
 		stockOpname.DELETE("/:number", controllers.DeleteStockOpname)
+
+		// ============================================================
+		// COUNTING
+		// POST /transactions/stock-opname/counting/start?transaction_number   → DRAFT → COUNTING
+		// POST /transactions/stock-opname/counting/branch                     → opname semua asset cabang, langsung COUNTING
+		// GET  /transactions/stock-opname/counting/worksheet?transaction_number
+		// PUT  /transactions/stock-opname/counting/counts?transaction_number  → isi hasil hitung
+		// POST   /transactions/stock-opname/counting/found-items?transaction_number     → barang tidak terdaftar
+		// DELETE /transactions/stock-opname/counting/found-items/:id?transaction_number
+		// POST /transactions/stock-opname/counting/submit?transaction_number  → COUNTING → REVIEW
+		// ============================================================
+
+		counting := stockOpname.Group("/counting")
+		{
+			counting.POST("/start",
+				middleware.RequirePermission("create_transaction"),
+				controllers.StartStockOpnameCounting)
+
+			counting.POST("/branch",
+				middleware.RequirePermission("create_transaction"),
+				controllers.StartBranchStockOpname)
+
+			counting.GET("/worksheet", controllers.GetStockOpnameWorksheet)
+
+			counting.PUT("/counts",
+				middleware.RequirePermission("count_stock_opname"),
+				controllers.UpdateStockOpnameCounts)
+
+			counting.POST("/found-items",
+				middleware.RequirePermission("count_stock_opname"),
+				controllers.AddStockOpnameFoundItem)
+
+			counting.DELETE("/found-items/:id",
+				middleware.RequirePermission("count_stock_opname"),
+				controllers.RemoveStockOpnameFoundItem)
+
+			counting.POST("/submit",
+				middleware.RequirePermission("count_stock_opname"),
+				controllers.SubmitStockOpnameCount)
+		}
+
+		// ============================================================
+		// REVIEW
+		// POST /transactions/stock-opname/review/confirm?transaction_number → REVIEW → APPROVAL
+		// POST /transactions/stock-opname/review/return?transaction_number  → REVIEW → COUNTING
+		// ============================================================
+
+		review := stockOpname.Group("/review")
+		{
+			review.POST("/confirm",
+				middleware.RequirePermission("review_stock_opname"),
+				controllers.ConfirmStockOpnameReview)
+
+			review.POST("/return",
+				middleware.RequirePermission("review_stock_opname"),
+				controllers.ReturnStockOpnameToCounting)
+		}
+
+		// ============================================================
+		// OFFLINE SYNC (mobile scanner)
+		// GET  /transactions/stock-opname/sync/worksheets?branch_code          → opname COUNTING cabang (default homebase)
+		// POST /transactions/stock-opname/sync/counts?transaction_number       → upload hitung bulk, idempotent per client_line_id
+		// GET  /transactions/stock-opname/sync/conflicts?transaction_number
+		// POST /transactions/stock-opname/sync/photos?transaction_number       → foto bukti (multipart)
+		// GET  /transactions/stock-opname/sync/photos?transaction_number&asset_id
+		// ============================================================
+
+		sync := stockOpname.Group("/sync")
+		{
+			sync.GET("/worksheets", controllers.GetStockOpnameSyncWorksheets)
+
+			sync.POST("/counts",
+				middleware.RequirePermission("count_stock_opname"),
+				controllers.SyncStockOpnameCounts)
+
+			sync.GET("/conflicts", controllers.GetStockOpnameSyncConflicts)
+
+			sync.POST("/photos",
+				middleware.RequirePermission("count_stock_opname"),
+				controllers.UploadStockOpnameCountPhoto)
+
+			sync.GET("/photos", controllers.GetStockOpnameCountPhotos)
+		}
+
+		// ============================================================
+		// VARIANCE
+		// GET  /transactions/stock-opname/variance?transaction_number           → missing, relocated, downgraded, found items
+		// POST /transactions/stock-opname/variance/follow-up?transaction_number → draft mutasi / disposal
+		// ============================================================
+
+		variance := stockOpname.Group("/variance")
+		{
+			variance.GET("", controllers.GetStockOpnameVariance)
+
+			variance.POST("/follow-up",
+				middleware.RequirePermission("create_transaction"),
+				controllers.CreateStockOpnameFollowUp)
+		}
+
+		// ============================================================
+		// APPROVAL — RECONCILED otomatis setelah semua step approved
+		// POST /transactions/stock-opname/approval/initiate?transaction_number
+		// GET  /transactions/stock-opname/approval/status?transaction_number
+		// ============================================================
+
+		approval := stockOpname.Group("/approval")
+		{
+			approval.POST("/initiate",
+				middleware.RequirePermission("manage_approval"),
+				controllers.InitiateStockOpnameApproval)
+
+			approval.GET("/status",
+				controllers.GetStockOpnameApprovalStatus)
+		}
+
+		// ============================================================
+		// REJECT
+		// POST /transactions/stock-opname/reject?transaction_number
+		// ============================================================
+
+		stockOpname.POST("/reject",
+			middleware.RequirePermission("reject_transaction"),
+			controllers.RejectStockOpname)
 	}
 }
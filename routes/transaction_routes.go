@@ -8,6 +8,9 @@ import (
 )
 
 func SetupTransactionHeaderRoutes(rg *gin.RouterGroup) {
+	// Public: target QR di dokumen PDF, dibuka dari kamera HP tanpa login
+	rg.GET("/transactions/verify", controllers.VerifyTransactionDocument)
+
 	routes := rg.Group("/transactions")
 	routes.Use(middleware.AuthMiddleware())
 	{
@@ -19,5 +22,8 @@ func SetupTransactionHeaderRoutes(rg *gin.RouterGroup) {
 
 		// Get specific transaction by number
 		routes.GET("/detail", controllers.GetTransactionByNumber)
+
+		// Dokumen resmi (PDF) dengan blok tanda tangan & QR verifikasi
+		routes.GET("/document", controllers.GetTransactionDocument)
 	}
 }
@@ -20,8 +20,12 @@ func StartScheduler() {
 	// Format: second minute hour day month weekday
 	schedulerInstance.AddFunc("0 1 0 1 * *", runMonthlyDepreciation)
 
+	// Cek approval yang melewati timeout_hours tiap 15 menit
+	schedulerInstance.AddFunc("0 */15 * * * *", runApprovalTimeouts)
+
 	schedulerInstance.Start()
 	fmt.Println("[Scheduler] Started - Monthly depreciation will run on the 1st of each month at 00:01")
+	fmt.Println("[Scheduler] Started - Approval timeouts will be checked every 15 minutes")
 }
 
 // StopScheduler menghentikan scheduler — dipanggil saat shutdown
@@ -54,11 +58,25 @@ func runMonthlyDepreciation() {
 
 	fmt.Printf("[Scheduler] Monthly depreciation completed for period: %s\n", period)
 
-	// Auto lock setelah calculate berhasil
-	if err := services.LockMonthlyDepreciation(period); err != nil {
+	// Auto lock semua buku setelah calculate berhasil
+	if err := services.LockMonthlyDepreciation(period, ""); err != nil {
 		fmt.Printf("[Scheduler] WARNING: Failed to lock depreciation for %s: %v\n", period, err)
 		return
 	}
 
 	fmt.Printf("[Scheduler] Monthly depreciation locked for period: %s\n", period)
 }
+
+// runApprovalTimeouts menjalankan eskalasi / skip / reject untuk pending approval
+// yang melewati timeout_hours di step-nya
+func runApprovalTimeouts() {
+	processed, err := services.ProcessApprovalTimeouts()
+	if err != nil {
+		fmt.Printf("[Scheduler] ERROR: Approval timeout check failed: %v\n", err)
+		return
+	}
+
+	if processed > 0 {
+		fmt.Printf("[Scheduler] Approval timeouts processed: %d\n", processed)
+	}
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/models"
+	"fmt"
+	"time"
+)
+
+// ============================================================================
+// AUTO APPROVE
+// Step dengan auto_approve = true di-approve sistem begitu grupnya terbuka
+// dan auto_approve_rules terpenuhi (rules kosong = selalu auto approve).
+// Dicatat sebagai ApprovalSignature tanpa user dengan alasan di notes.
+// Dijalankan setelah initiate dan setiap ada step selesai, berulang sampai
+// tidak ada lagi step yang bisa di-approve (step berikutnya bisa ikut terbuka).
+// ============================================================================
+
+// processAutoApprovals returns jumlah step yang di-approve otomatis
+func processAutoApprovals(transactionNumber, transactionType string) (int, error) {
+	var ctx *ApprovalConditionContext
+	total := 0
+
+	for {
+		var approvals []models.TransactionApproval
+		if err := config.DB.
+			Preload("ApprovalFlow").
+			Preload("ApprovalFlowStep").
+			Where("transaction_number = ? AND transaction_type = ?", transactionNumber, transactionType).
+			Find(&approvals).Error; err != nil {
+			return total, err
+		}
+
+		candidates := autoApproveCandidates(approvals)
+		if len(candidates) == 0 {
+			return total, nil
+		}
+
+		if ctx == nil {
+			built, err := buildApprovalConditionContext(transactionNumber, transactionType)
+			if err != nil {
+				return total, err
+			}
+			ctx = &built
+		}
+
+		approved := 0
+		for _, approval := range candidates {
+			ok, reason, err := evaluateAutoApprove(approval, approvals, *ctx)
+			if err != nil {
+				return total, err
+			}
+			if !ok {
+				continue
+			}
+
+			done, err := autoApproveStep(approval, reason)
+			if err != nil {
+				return total, err
+			}
+			if done {
+				approved++
+			}
+		}
+
+		if approved == 0 {
+			return total, nil
+		}
+		total += approved
+	}
+}
+
+// autoApproveCandidates - step auto_approve yang pending dan grupnya sudah terbuka
+func autoApproveCandidates(approvals []models.TransactionApproval) []models.TransactionApproval {
+	byFlow := map[string][]models.TransactionApproval{}
+	for _, a := range approvals {
+		byFlow[a.FlowID] = append(byFlow[a.FlowID], a)
+	}
+
+	var candidates []models.TransactionApproval
+	for _, flowApprovals := range byFlow {
+		groups := groupApprovals(flowApprovals)
+		for _, a := range flowApprovals {
+			if a.Status != "pending" || a.ApprovalFlowStep == nil || !a.ApprovalFlowStep.AutoApprove {
+				continue
+			}
+			if enforcesGroupOrder(a.ApprovalFlow.ApprovalWay) && !isApprovalGroupOpen(groups, a.ApprovalFlowStep.StepOrder) {
+				continue
+			}
+			candidates = append(candidates, a)
+		}
+	}
+
+	return candidates
+}
+
+// evaluateAutoApprove returns (lolos, alasan untuk signature / kondisi yang tidak terpenuhi).
+// Error saat cek data → step tetap pending, tidak pernah dianggap lolos
+func evaluateAutoApprove(approval models.TransactionApproval, approvals []models.TransactionApproval, ctx ApprovalConditionContext) (bool, string, error) {
+	step := approval.ApprovalFlowStep
+
+	rules, err := ParseAutoApproveRules(step.AutoApproveRules)
+	if err != nil {
+		return false, "", fmt.Errorf("step %s: %w", step.StepName, err)
+	}
+	if rules == nil {
+		return true, fmt.Sprintf("Auto-approved: step %q is configured to approve automatically", step.StepName), nil
+	}
+
+	if ctx.ApproverAlreadySigned, err = approverAlreadySigned(approval, approvals); err != nil {
+		return false, "", fmt.Errorf("step %s: %w", step.StepName, err)
+	}
+	if ctx.CreatorHasStepRole, err = userHasApprovalRole(ctx.CreatedBy, approval.ApproverRoleID); err != nil {
+		return false, "", fmt.Errorf("step %s: %w", step.StepName, err)
+	}
+
+	ok, unmet := rules.Evaluate(ctx)
+	if !ok {
+		return false, unmet, nil
+	}
+
+	return true, fmt.Sprintf("Auto-approved: step %q rules met (%s)", step.StepName, *step.AutoApproveRules), nil
+}
+
+// approverAlreadySigned - approver step ini sudah approve step lain di transaksi yang sama
+func approverAlreadySigned(approval models.TransactionApproval, approvals []models.TransactionApproval) (bool, error) {
+	for _, other := range approvals {
+		if other.ID == approval.ID || other.Status != "approved" || other.ApprovedBy == nil {
+			continue
+		}
+
+		if approval.ApproverUserID != nil {
+			if *other.ApprovedBy == *approval.ApproverUserID {
+				return true, nil
+			}
+			continue
+		}
+
+		hasRole, err := userHasApprovalRole(*other.ApprovedBy, approval.ApproverRoleID)
+		if err != nil {
+			return false, err
+		}
+		if hasRole {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func userHasApprovalRole(userID string, roleID *string) (bool, error) {
+	if userID == "" || roleID == nil {
+		return false, nil
+	}
+
+	var count int64
+	if err := config.DB.Model(&models.UserRole{}).
+		Where("user_id = ? AND role_id = ?", userID, *roleID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// autoApproveStep - approve step atas nama sistem, dengan lock yang sama seperti ApproveTransaction
+func autoApproveStep(approval models.TransactionApproval, reason string) (bool, error) {
+	now := time.Now()
+
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := lockFlowApprovals(tx, approval); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	result := tx.Model(&models.TransactionApproval{}).
+		Where("id = ? AND status = ?", approval.ID, "pending").
+		Updates(map[string]interface{}{
+			"status":      "approved",
+			"approved_at": now,
+			"notes":       reason,
+		})
+	if result.Error != nil {
+		tx.Rollback()
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return false, nil // sudah diproses approver lain
+	}
+
+	if err := createSystemSignature(tx, approval, "signed", approval.ApproverRoleID, reason, now); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := closeApprovalGroupIfQuorumMet(tx, approval); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+
+	notifyApprovalClosed(approval, "approved", SystemActorID, &reason)
+
+	return true, nil
+}
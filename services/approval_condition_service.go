@@ -0,0 +1,489 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// APPROVAL STEP CONDITIONS
+// approval_flow_steps.conditions dievaluasi saat InitiateTransactionApproval.
+// Step yang kondisinya tidak terpenuhi dibuat dengan status "skipped".
+// Step tanpa conditions (NULL / "") selalu dibuat "pending".
+//
+// Format:
+//
+//	{
+//	  "match": "all",            // "all" (default) = semua rule harus lolos, "any" = minimal satu
+//	  "rules": [
+//	    {"field": "total_value",   "op": "gt", "value": 50000000},
+//	    {"field": "disposal_type", "op": "eq", "value": "SELL"}
+//	  ]
+//	}
+//
+// Field:
+//   - total_value   (angka)  procurement: total harga item; mutasi/disposal: total book value asset aktif
+//   - item_count    (angka)  procurement: total quantity; mutasi/disposal: jumlah asset
+//   - category      (teks)   category_code asset / item (satu transaksi bisa punya banyak kategori)
+//   - branch_type   (teks)   branch_type branch transaksi (procurement: branch item; mutasi: branch asal;
+//                            disposal / opname: branch asset), misal "HO"
+//   - disposal_type (teks)   SELL / DISPOSE — hanya untuk disposal
+//
+// Operator:
+//   - eq, neq                 → angka atau teks (teks case-insensitive)
+//   - gt, gte, lt, lte        → hanya angka
+//   - in, not_in              → value berupa array
+//
+// Untuk field "category": eq / in lolos kalau salah satu kategori transaksi cocok,
+// neq / not_in lolos kalau tidak ada kategori yang cocok.
+//
+// Contoh:
+//   - Direktur hanya tanda tangan di atas 50 juta:
+//     {"rules":[{"field":"total_value","op":"gt","value":50000000}]}
+//   - Step pajak hanya untuk penjualan:
+//     {"rules":[{"field":"disposal_type","op":"eq","value":"SELL"}]}
+//
+// approval_flow_steps.auto_approve_rules memakai format yang sama, ditambah
+// field boolean (op eq / neq, value true / false) yang dihitung per step:
+//   - approver_already_signed  approver step ini sudah approve step sebelumnya
+//     (user yang sama, atau user yang sudah approve memegang role step ini)
+//   - creator_has_step_role    creator transaksi memegang role step ini
+//
+// Contoh auto approve di bawah 5 juta kalau approver sudah tanda tangan sebelumnya:
+//     {"rules":[{"field":"total_value","op":"lt","value":5000000},
+//               {"field":"approver_already_signed","op":"eq","value":true}]}
+// ============================================================================
+
+const (
+	ConditionFieldTotalValue   = "total_value"
+	ConditionFieldItemCount    = "item_count"
+	ConditionFieldCategory     = "category"
+	ConditionFieldBranchType   = "branch_type"
+	ConditionFieldDisposalType = "disposal_type"
+
+	// Hanya untuk auto_approve_rules
+	ConditionFieldApproverAlreadySigned = "approver_already_signed"
+	ConditionFieldCreatorHasStepRole    = "creator_has_step_role"
+)
+
+var numericConditionFields = map[string]bool{
+	ConditionFieldTotalValue: true,
+	ConditionFieldItemCount:  true,
+}
+
+var textConditionFields = map[string]bool{
+	ConditionFieldCategory:     true,
+	ConditionFieldBranchType:   true,
+	ConditionFieldDisposalType: true,
+}
+
+var booleanConditionFields = map[string]bool{
+	ConditionFieldApproverAlreadySigned: true,
+	ConditionFieldCreatorHasStepRole:    true,
+}
+
+var numericConditionOps = map[string]bool{"eq": true, "neq": true, "gt": true, "gte": true, "lt": true, "lte": true, "in": true, "not_in": true}
+var textConditionOps = map[string]bool{"eq": true, "neq": true, "in": true, "not_in": true}
+var booleanConditionOps = map[string]bool{"eq": true, "neq": true}
+
+type ApprovalConditionRule struct {
+	Field string          `json:"field"`
+	Op    string          `json:"op"`
+	Value json.RawMessage `json:"value"`
+}
+
+type ApprovalConditions struct {
+	Match string                  `json:"match"`
+	Rules []ApprovalConditionRule `json:"rules"`
+}
+
+// ApprovalConditionContext - data transaksi yang dipakai untuk evaluasi conditions
+type ApprovalConditionContext struct {
+	TotalValue   float64
+	ItemCount    int
+	Categories   []string
+	BranchType   string
+	DisposalType string
+	CreatedBy    string // user id creator transaksi
+
+	// Per step — hanya diisi saat evaluasi auto_approve_rules
+	ApproverAlreadySigned bool
+	CreatorHasStepRole    bool
+}
+
+// ParseApprovalConditions - parse + validasi JSON conditions.
+// Return nil kalau conditions kosong (step tanpa kondisi)
+func ParseApprovalConditions(raw *string) (*ApprovalConditions, error) {
+	return parseConditions(raw, false)
+}
+
+// ParseAutoApproveRules - parse + validasi auto_approve_rules (boleh pakai field boolean per step).
+// Return nil kalau rules kosong (step auto approve tanpa syarat)
+func ParseAutoApproveRules(raw *string) (*ApprovalConditions, error) {
+	conditions, err := parseConditions(raw, true)
+	if err != nil {
+		return nil, fmt.Errorf("auto_approve_rules: %w", err)
+	}
+	return conditions, nil
+}
+
+func parseConditions(raw *string, allowStepFields bool) (*ApprovalConditions, error) {
+	if raw == nil || strings.TrimSpace(*raw) == "" || strings.TrimSpace(*raw) == "null" {
+		return nil, nil
+	}
+
+	var conditions ApprovalConditions
+	if err := json.Unmarshal([]byte(*raw), &conditions); err != nil {
+		return nil, fmt.Errorf("invalid conditions JSON: %w", err)
+	}
+
+	if conditions.Match == "" {
+		conditions.Match = "all"
+	}
+	if conditions.Match != "all" && conditions.Match != "any" {
+		return nil, fmt.Errorf("invalid conditions match %q, use all or any", conditions.Match)
+	}
+
+	if len(conditions.Rules) == 0 {
+		return nil, errors.New("conditions must have at least one rule")
+	}
+
+	for i, rule := range conditions.Rules {
+		if err := validateConditionRule(rule, allowStepFields); err != nil {
+			return nil, fmt.Errorf("conditions rule %d: %w", i+1, err)
+		}
+	}
+
+	return &conditions, nil
+}
+
+func validateConditionRule(rule ApprovalConditionRule, allowStepFields bool) error {
+	switch {
+	case booleanConditionFields[rule.Field]:
+		if !allowStepFields {
+			return fmt.Errorf("field %s can only be used in auto_approve_rules", rule.Field)
+		}
+		if !booleanConditionOps[rule.Op] {
+			return fmt.Errorf("operator %q is not supported for field %s", rule.Op, rule.Field)
+		}
+		var value bool
+		if err := json.Unmarshal(rule.Value, &value); err != nil {
+			return fmt.Errorf("value for %s must be true or false", rule.Field)
+		}
+
+	case numericConditionFields[rule.Field]:
+		if !numericConditionOps[rule.Op] {
+			return fmt.Errorf("operator %q is not supported for field %s", rule.Op, rule.Field)
+		}
+		if rule.Op == "in" || rule.Op == "not_in" {
+			var values []float64
+			if err := json.Unmarshal(rule.Value, &values); err != nil {
+				return fmt.Errorf("value for %s %s must be an array of numbers", rule.Field, rule.Op)
+			}
+			return nil
+		}
+		var value float64
+		if err := json.Unmarshal(rule.Value, &value); err != nil {
+			return fmt.Errorf("value for %s must be a number", rule.Field)
+		}
+
+	case textConditionFields[rule.Field]:
+		if !textConditionOps[rule.Op] {
+			return fmt.Errorf("operator %q is not supported for field %s", rule.Op, rule.Field)
+		}
+		if rule.Op == "in" || rule.Op == "not_in" {
+			var values []string
+			if err := json.Unmarshal(rule.Value, &values); err != nil {
+				return fmt.Errorf("value for %s %s must be an array of strings", rule.Field, rule.Op)
+			}
+			return nil
+		}
+		var value string
+		if err := json.Unmarshal(rule.Value, &value); err != nil {
+			return fmt.Errorf("value for %s must be a string", rule.Field)
+		}
+
+	default:
+		return fmt.Errorf("unknown field %q", rule.Field)
+	}
+
+	return nil
+}
+
+// Evaluate returns (lolos, alasan kalau tidak lolos)
+func (c *ApprovalConditions) Evaluate(ctx ApprovalConditionContext) (bool, string) {
+	var failed []string
+
+	for _, rule := range c.Rules {
+		if evaluateConditionRule(rule, ctx) {
+			if c.Match == "any" {
+				return true, ""
+			}
+			continue
+		}
+		failed = append(failed, fmt.Sprintf("%s %s %s", rule.Field, rule.Op, string(rule.Value)))
+	}
+
+	if c.Match == "any" {
+		return false, "none of the conditions met: " + strings.Join(failed, ", ")
+	}
+	if len(failed) > 0 {
+		return false, "conditions not met: " + strings.Join(failed, ", ")
+	}
+	return true, ""
+}
+
+func evaluateConditionRule(rule ApprovalConditionRule, ctx ApprovalConditionContext) bool {
+	switch rule.Field {
+	case ConditionFieldTotalValue:
+		return compareNumber(ctx.TotalValue, rule)
+	case ConditionFieldItemCount:
+		return compareNumber(float64(ctx.ItemCount), rule)
+	case ConditionFieldCategory:
+		return compareTextList(ctx.Categories, rule)
+	case ConditionFieldBranchType:
+		return compareTextList([]string{ctx.BranchType}, rule)
+	case ConditionFieldDisposalType:
+		return compareTextList([]string{ctx.DisposalType}, rule)
+	case ConditionFieldApproverAlreadySigned:
+		return compareBool(ctx.ApproverAlreadySigned, rule)
+	case ConditionFieldCreatorHasStepRole:
+		return compareBool(ctx.CreatorHasStepRole, rule)
+	}
+	return false
+}
+
+func compareBool(actual bool, rule ApprovalConditionRule) bool {
+	var value bool
+	if err := json.Unmarshal(rule.Value, &value); err != nil {
+		return false
+	}
+	if rule.Op == "neq" {
+		return actual != value
+	}
+	return actual == value
+}
+
+func compareNumber(actual float64, rule ApprovalConditionRule) bool {
+	if rule.Op == "in" || rule.Op == "not_in" {
+		var values []float64
+		json.Unmarshal(rule.Value, &values)
+		found := false
+		for _, v := range values {
+			if v == actual {
+				found = true
+				break
+			}
+		}
+		return found == (rule.Op == "in")
+	}
+
+	var value float64
+	if err := json.Unmarshal(rule.Value, &value); err != nil {
+		return false
+	}
+
+	switch rule.Op {
+	case "eq":
+		return actual == value
+	case "neq":
+		return actual != value
+	case "gt":
+		return actual > value
+	case "gte":
+		return actual >= value
+	case "lt":
+		return actual < value
+	case "lte":
+		return actual <= value
+	}
+	return false
+}
+
+// compareTextList - actual bisa lebih dari satu (category), cocok kalau salah satu match
+func compareTextList(actual []string, rule ApprovalConditionRule) bool {
+	var values []string
+	if rule.Op == "in" || rule.Op == "not_in" {
+		if err := json.Unmarshal(rule.Value, &values); err != nil {
+			return false
+		}
+	} else {
+		var value string
+		if err := json.Unmarshal(rule.Value, &value); err != nil {
+			return false
+		}
+		values = []string{value}
+	}
+
+	matched := false
+	for _, a := range actual {
+		for _, v := range values {
+			if a != "" && strings.EqualFold(a, v) {
+				matched = true
+			}
+		}
+	}
+
+	if rule.Op == "eq" || rule.Op == "in" {
+		return matched
+	}
+	return !matched
+}
+
+// buildApprovalConditionContext - kumpulkan data transaksi untuk evaluasi conditions
+func buildApprovalConditionContext(transactionNumber, transactionType string) (ApprovalConditionContext, error) {
+	var ctx ApprovalConditionContext
+
+	var transaction models.Transaction
+	if err := config.DB.
+		Where("transaction_number = ? AND transaction_type = ?", transactionNumber, transactionType).
+		First(&transaction).Error; err != nil {
+		return ctx, fmt.Errorf("transaction %s not found for condition evaluation", transactionNumber)
+	}
+
+	ctx.CreatedBy = transaction.CreatedBy
+
+	// branch_type diambil dari branch transaksi (bukan homebase creator saat ini)
+	// supaya routing transaksi yang sama tidak berubah kalau creator pindah homebase
+	var branchCode string
+	setBranch := func(code *string) {
+		if branchCode == "" && code != nil {
+			branchCode = *code
+		}
+	}
+
+	categorySet := map[string]bool{}
+	addCategory := func(category *models.AssetCategory) {
+		if category != nil && !categorySet[category.CategoryCode] {
+			categorySet[category.CategoryCode] = true
+			ctx.Categories = append(ctx.Categories, category.CategoryCode)
+		}
+	}
+
+	switch transactionType {
+	case TxProcurement:
+		var items []models.TransactionProcurement
+		if err := config.DB.Preload("Category").
+			Where("transaction_id = ?", transaction.ID).
+			Find(&items).Error; err != nil {
+			return ctx, err
+		}
+		for _, item := range items {
+			total := item.TotalPrice
+			if total == 0 {
+				total = item.UnitPrice * float64(item.Quantity)
+			}
+			ctx.TotalValue += total
+			ctx.ItemCount += item.Quantity
+			addCategory(item.Category)
+			setBranch(&item.BranchCode)
+		}
+
+	case TxMutationFlow:
+		var mutationAssets []models.TransactionMutationAsset
+		if err := config.DB.Preload("Asset.Category").
+			Where("transaction_id = ? AND status <> ?", transaction.ID, models.MutationAssetStatusCancelled).
+			Find(&mutationAssets).Error; err != nil {
+			return ctx, err
+		}
+		assetIDs := make([]uint, 0, len(mutationAssets))
+		for _, ma := range mutationAssets {
+			assetIDs = append(assetIDs, ma.AssetID)
+			setBranch(&ma.FromBranchCode)
+			if ma.Asset != nil {
+				addCategory(ma.Asset.Category)
+			}
+		}
+		ctx.ItemCount = len(mutationAssets)
+		totalValue, err := sumActiveBookValue(assetIDs)
+		if err != nil {
+			return ctx, err
+		}
+		ctx.TotalValue = totalValue
+
+	case TxDisposalFlow:
+		if transaction.DisposalType != nil {
+			ctx.DisposalType = *transaction.DisposalType
+		}
+		var disposalAssets []models.TransactionDisposalAsset
+		if err := config.DB.Preload("Asset.Category").
+			Where("transaction_id = ? AND status <> ?", transaction.ID, models.DisposalAssetStatusCancelled).
+			Find(&disposalAssets).Error; err != nil {
+			return ctx, err
+		}
+		assetIDs := make([]uint, 0, len(disposalAssets))
+		for _, da := range disposalAssets {
+			assetIDs = append(assetIDs, da.AssetID)
+			if da.Asset != nil {
+				addCategory(da.Asset.Category)
+				setBranch(da.Asset.BranchCode)
+			}
+			if ctx.DisposalType == "" {
+				ctx.DisposalType = da.DisposalType
+			}
+		}
+		ctx.ItemCount = len(disposalAssets)
+		totalValue, err := sumActiveBookValue(assetIDs)
+		if err != nil {
+			return ctx, err
+		}
+		ctx.TotalValue = totalValue
+
+	case TxStockOpname:
+		var items []models.TransactionStockOpname
+		if err := config.DB.Preload("Asset.Category").
+			Where("transaction_id = ?", transaction.ID).
+			Find(&items).Error; err != nil {
+			return ctx, err
+		}
+		assetIDs := make([]uint, 0, len(items))
+		for _, item := range items {
+			assetIDs = append(assetIDs, item.AssetID)
+			if item.Asset != nil {
+				addCategory(item.Asset.Category)
+				setBranch(item.Asset.BranchCode)
+			}
+		}
+		ctx.ItemCount = len(items)
+		totalValue, err := sumActiveBookValue(assetIDs)
+		if err != nil {
+			return ctx, err
+		}
+		ctx.TotalValue = totalValue
+	}
+
+	if branchCode != "" {
+		var branch models.Branch
+		err := config.DB.Where("branch_code = ?", branchCode).First(&branch).Error
+		if err == nil {
+			ctx.BranchType = branch.BranchType
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return ctx, err
+		}
+	}
+
+	return ctx, nil
+}
+
+// sumActiveBookValue - total nilai buku aktif; error DB dikembalikan supaya step berbasis nilai
+// tidak ter-skip diam-diam
+func sumActiveBookValue(assetIDs []uint) (float64, error) {
+	if len(assetIDs) == 0 {
+		return 0, nil
+	}
+
+	var total float64
+	if err := config.DB.Model(&models.AssetValue{}).
+		Where("asset_id IN ? AND is_active = ?", assetIDs, true).
+		Select("COALESCE(SUM(book_value), 0)").
+		Scan(&total).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
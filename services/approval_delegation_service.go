@@ -0,0 +1,364 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// APPROVAL DELEGATION
+// Delegator mengalihkan wewenang approval ke delegate selama periode tertentu
+// (misal cuti). Bisa dibatasi ke flow_code / transaction_type tertentu.
+// Delegate bisa approve / reject step yang seharusnya milik delegator —
+// signature mencatat delegate (user_id) dan approver asli (on_behalf_of_user_id).
+// ============================================================================
+
+// CreateApprovalDelegation - delegator = user yang login
+func CreateApprovalDelegation(userID string, req dto.CreateApprovalDelegationRequest) (*dto.ApprovalDelegationResponse, error) {
+	if req.DelegateID == userID {
+		return nil, errors.New("you cannot delegate approval to yourself")
+	}
+
+	var delegate models.User
+	if err := config.DB.First(&delegate, "id = ?", req.DelegateID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("delegate user not found")
+		}
+		return nil, err
+	}
+
+	startDate, endDate, err := parseDelegationPeriod(req.StartDate, req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateDelegationOverlap(userID, req.DelegateID, startDate, endDate, ""); err != nil {
+		return nil, err
+	}
+
+	delegation := models.ApprovalDelegation{
+		DelegatorID:      userID,
+		DelegateID:       req.DelegateID,
+		StartDate:        startDate,
+		EndDate:          endDate,
+		FlowCodes:        marshalDelegationScope(req.FlowCodes),
+		TransactionTypes: marshalDelegationScope(req.TransactionTypes),
+		Reason:           req.Reason,
+		IsActive:         true,
+	}
+
+	if err := config.DB.Create(&delegation).Error; err != nil {
+		return nil, err
+	}
+
+	return GetApprovalDelegationByID(delegation.ID)
+}
+
+// GetApprovalDelegations - delegasi yang diberikan dan / atau diterima user
+func GetApprovalDelegations(userID string, filter dto.ApprovalDelegationFilter) ([]dto.ApprovalDelegationResponse, error) {
+	query := config.DB.
+		Preload("Delegator").
+		Preload("Delegate")
+
+	switch filter.Role {
+	case "delegator":
+		query = query.Where("delegator_id = ?", userID)
+	case "delegate":
+		query = query.Where("delegate_id = ?", userID)
+	default:
+		query = query.Where("delegator_id = ? OR delegate_id = ?", userID, userID)
+	}
+
+	if filter.ActiveOnly {
+		now := time.Now()
+		query = query.Where("is_active = ? AND start_date <= ? AND end_date >= ?", true, now, now)
+	}
+
+	var delegations []models.ApprovalDelegation
+	if err := query.Order("start_date DESC").Find(&delegations).Error; err != nil {
+		return nil, err
+	}
+
+	responses := make([]dto.ApprovalDelegationResponse, 0, len(delegations))
+	for _, d := range delegations {
+		responses = append(responses, mapApprovalDelegationToResponse(d))
+	}
+	return responses, nil
+}
+
+func GetApprovalDelegationByID(id string) (*dto.ApprovalDelegationResponse, error) {
+	var delegation models.ApprovalDelegation
+	if err := config.DB.
+		Preload("Delegator").
+		Preload("Delegate").
+		First(&delegation, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("approval delegation not found")
+		}
+		return nil, err
+	}
+
+	response := mapApprovalDelegationToResponse(delegation)
+	return &response, nil
+}
+
+// UpdateApprovalDelegation - hanya delegator yang bisa ubah periode / scope
+func UpdateApprovalDelegation(userID, id string, req dto.UpdateApprovalDelegationRequest) (*dto.ApprovalDelegationResponse, error) {
+	delegation, err := getOwnedDelegation(userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !delegation.IsActive {
+		return nil, errors.New("approval delegation has been revoked")
+	}
+
+	startRaw := delegation.StartDate.Format("2006-01-02")
+	endRaw := delegation.EndDate.Format("2006-01-02")
+	if req.StartDate != nil {
+		startRaw = *req.StartDate
+	}
+	if req.EndDate != nil {
+		endRaw = *req.EndDate
+	}
+
+	startDate, endDate, err := parseDelegationPeriod(startRaw, endRaw)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateDelegationOverlap(userID, delegation.DelegateID, startDate, endDate, delegation.ID); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{
+		"start_date": startDate,
+		"end_date":   endDate,
+	}
+	if req.FlowCodes != nil {
+		updates["flow_codes"] = marshalDelegationScope(req.FlowCodes)
+	}
+	if req.TransactionTypes != nil {
+		updates["transaction_types"] = marshalDelegationScope(req.TransactionTypes)
+	}
+	if req.Reason != nil {
+		updates["reason"] = req.Reason
+	}
+
+	if err := config.DB.Model(delegation).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+
+	return GetApprovalDelegationByID(delegation.ID)
+}
+
+// RevokeApprovalDelegation - hentikan delegasi sebelum end_date
+func RevokeApprovalDelegation(userID, id string) error {
+	delegation, err := getOwnedDelegation(userID, id)
+	if err != nil {
+		return err
+	}
+
+	if !delegation.IsActive {
+		return errors.New("approval delegation already revoked")
+	}
+
+	now := time.Now()
+	return config.DB.Model(delegation).Updates(map[string]interface{}{
+		"is_active":  false,
+		"revoked_at": now,
+	}).Error
+}
+
+// ============================================================================
+// HELPERS - dipakai ApproveTransaction / RejectTransaction / GetUserPendingApprovals
+// ============================================================================
+
+// resolveApprovalActor - cek user boleh approve / reject step ini, langsung atau lewat delegasi.
+// Return delegasi yang dipakai (nil kalau user adalah approver langsung)
+func resolveApprovalActor(userID string, approval models.TransactionApproval, action string) (*models.ApprovalDelegation, error) {
+	directErr := validateDirectApprover(userID, approval, action)
+	if directErr == nil {
+		return nil, nil
+	}
+
+	delegations, err := getCurrentDelegationsFor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range delegations {
+		d := delegations[i]
+		if !delegationCovers(d, approval.ApprovalFlow.FlowCode, approval.TransactionType) {
+			continue
+		}
+		if validateDirectApprover(d.DelegatorID, approval, action) == nil {
+			return &d, nil
+		}
+	}
+
+	return nil, directErr
+}
+
+// validateDirectApprover - user approver yang ditunjuk, punya role step, dan punya akses ke branch transaksi
+func validateDirectApprover(userID string, approval models.TransactionApproval, action string) error {
+	if approval.ApproverUserID != nil && *approval.ApproverUserID != userID {
+		return fmt.Errorf("you are not authorized to %s this transaction", action)
+	}
+
+	if approval.ApproverRoleID != nil {
+		var userRole models.UserRole
+		if err := config.DB.
+			Where("user_id = ? AND role_id = ?", userID, *approval.ApproverRoleID).
+			First(&userRole).Error; err != nil {
+			return fmt.Errorf("you do not have the required role to %s this transaction", action)
+		}
+	}
+
+	// Validasi branch approver harus sama dengan branch creator transaksi
+	return validateApproverBranch(userID, approval.TransactionNumber, approval.TransactionType)
+}
+
+// getCurrentDelegationsFor - delegasi aktif yang sedang berlaku untuk delegate
+func getCurrentDelegationsFor(delegateID string) ([]models.ApprovalDelegation, error) {
+	now := time.Now()
+
+	var delegations []models.ApprovalDelegation
+	if err := config.DB.
+		Where("delegate_id = ? AND is_active = ? AND start_date <= ? AND end_date >= ?", delegateID, true, now, now).
+		Find(&delegations).Error; err != nil {
+		return nil, err
+	}
+	return delegations, nil
+}
+
+// delegationCovers - flow_code & transaction_type masuk scope delegasi
+func delegationCovers(delegation models.ApprovalDelegation, flowCode, transactionType string) bool {
+	if codes := unmarshalDelegationScope(delegation.FlowCodes); len(codes) > 0 && !containsString(codes, flowCode) {
+		return false
+	}
+	if types := unmarshalDelegationScope(delegation.TransactionTypes); len(types) > 0 && !containsString(types, transactionType) {
+		return false
+	}
+	return true
+}
+
+func getOwnedDelegation(userID, id string) (*models.ApprovalDelegation, error) {
+	var delegation models.ApprovalDelegation
+	if err := config.DB.First(&delegation, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("approval delegation not found")
+		}
+		return nil, err
+	}
+
+	if delegation.DelegatorID != userID {
+		return nil, errors.New("you can only manage your own approval delegations")
+	}
+	return &delegation, nil
+}
+
+// parseDelegationPeriod - end_date inklusif sampai akhir hari
+func parseDelegationPeriod(startRaw, endRaw string) (time.Time, time.Time, error) {
+	startDate, err := time.Parse("2006-01-02", startRaw)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid start_date format, use YYYY-MM-DD")
+	}
+
+	endDate, err := time.Parse("2006-01-02", endRaw)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid end_date format, use YYYY-MM-DD")
+	}
+
+	if endDate.Before(startDate) {
+		return time.Time{}, time.Time{}, errors.New("end_date must be on or after start_date")
+	}
+
+	endDate = endDate.Add(24*time.Hour - time.Second)
+	if endDate.Before(time.Now()) {
+		return time.Time{}, time.Time{}, errors.New("end_date must not be in the past")
+	}
+
+	return startDate, endDate, nil
+}
+
+// validateDelegationOverlap - satu delegator tidak boleh punya dua delegasi aktif ke delegate yang sama di periode yang beririsan
+func validateDelegationOverlap(delegatorID, delegateID string, startDate, endDate time.Time, excludeID string) error {
+	query := config.DB.Model(&models.ApprovalDelegation{}).
+		Where("delegator_id = ? AND delegate_id = ? AND is_active = ?", delegatorID, delegateID, true).
+		Where("start_date <= ? AND end_date >= ?", endDate, startDate)
+	if excludeID != "" {
+		query = query.Where("id <> ?", excludeID)
+	}
+
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("an active delegation to this user already exists in the given period")
+	}
+	return nil
+}
+
+func marshalDelegationScope(values []string) *string {
+	if len(values) == 0 {
+		return nil
+	}
+	raw, _ := json.Marshal(values)
+	scope := string(raw)
+	return &scope
+}
+
+func unmarshalDelegationScope(raw *string) []string {
+	if raw == nil || *raw == "" {
+		return nil
+	}
+	var values []string
+	json.Unmarshal([]byte(*raw), &values)
+	return values
+}
+
+func containsString(values []string, target string) bool {
+	for _, v := range values {
+		if v == target {
+			return true
+		}
+	}
+	return false
+}
+
+func mapApprovalDelegationToResponse(d models.ApprovalDelegation) dto.ApprovalDelegationResponse {
+	now := time.Now()
+	response := dto.ApprovalDelegationResponse{
+		ID:               d.ID,
+		DelegatorID:      d.DelegatorID,
+		DelegateID:       d.DelegateID,
+		StartDate:        d.StartDate,
+		EndDate:          d.EndDate,
+		FlowCodes:        unmarshalDelegationScope(d.FlowCodes),
+		TransactionTypes: unmarshalDelegationScope(d.TransactionTypes),
+		Reason:           d.Reason,
+		IsActive:         d.IsActive,
+		IsCurrent:        d.IsActive && !now.Before(d.StartDate) && !now.After(d.EndDate),
+		RevokedAt:        d.RevokedAt,
+		CreatedAt:        d.CreatedAt,
+		UpdatedAt:        d.UpdatedAt,
+	}
+
+	if d.Delegator != nil {
+		response.DelegatorName = &d.Delegator.Username
+	}
+	if d.Delegate != nil {
+		response.DelegateName = &d.Delegate.Username
+	}
+
+	return response
+}
@@ -0,0 +1,437 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ============================================================================
+// APPROVAL GROUP (PARALLEL + QUORUM)
+// Step dengan step_order yang sama dalam satu flow = satu grup.
+// Quorum grup (quorum_type di step):
+//   - all    → semua step di grup harus approved
+//   - any    → cukup satu step approved
+//   - n_of_m → minimal quorum_count step approved
+// Step yang sudah skipped (conditions / timeout) tidak dihitung sebagai M.
+// Begitu quorum terpenuhi, sisa step pending di grup otomatis skipped.
+// Grup berikutnya baru terbuka setelah grup sebelumnya selesai
+// (step yang is_required = false tidak menahan grup berikutnya).
+// ============================================================================
+
+type approvalGroup struct {
+	FlowID      string
+	StepOrder   int
+	QuorumType  string
+	QuorumCount int
+	Approvals   []models.TransactionApproval
+}
+
+// counts returns jumlah approved, pending, rejected (skipped tidak dihitung)
+func (g approvalGroup) counts() (approved, pending, rejected int) {
+	for _, a := range g.Approvals {
+		switch a.Status {
+		case "approved":
+			approved++
+		case "pending":
+			pending++
+		case "rejected":
+			rejected++
+		}
+	}
+	return
+}
+
+// required - jumlah approval yang dibutuhkan supaya quorum terpenuhi
+func (g approvalGroup) required() int {
+	approved, pending, rejected := g.counts()
+	eligible := approved + pending + rejected
+
+	switch g.QuorumType {
+	case models.QuorumAny:
+		if eligible == 0 {
+			return 0
+		}
+		return 1
+	case models.QuorumNOfM:
+		if g.QuorumCount > eligible {
+			return eligible
+		}
+		return g.QuorumCount
+	}
+	return eligible
+}
+
+// isMet - quorum terpenuhi (grup tanpa step eligible / semua skipped juga dianggap selesai)
+func (g approvalGroup) isMet() bool {
+	approved, _, rejected := g.counts()
+	if g.QuorumType == models.QuorumAll || g.QuorumType == "" {
+		return rejected == 0 && approved >= g.required()
+	}
+	return approved >= g.required()
+}
+
+// isFailed - quorum sudah tidak mungkin tercapai
+func (g approvalGroup) isFailed() bool {
+	approved, pending, rejected := g.counts()
+	if g.QuorumType == models.QuorumAll || g.QuorumType == "" {
+		return rejected > 0
+	}
+	return approved+pending < g.required()
+}
+
+// isBlocking - grup ini menahan grup berikutnya: quorum belum terpenuhi dan
+// masih ada step wajib yang pending (atau quorum sudah gagal)
+func (g approvalGroup) isBlocking() bool {
+	if g.isMet() {
+		return false
+	}
+	if g.isFailed() {
+		return true
+	}
+	for _, a := range g.Approvals {
+		if a.Status == "pending" && (a.ApprovalFlowStep == nil || a.ApprovalFlowStep.IsRequired) {
+			return true
+		}
+	}
+	return false
+}
+
+// pendingRequiredSteps - nama step wajib yang masih pending di grup ini
+func (g approvalGroup) pendingRequiredSteps() []string {
+	var names []string
+	for _, a := range g.Approvals {
+		if a.Status != "pending" || a.ApprovalFlowStep == nil || !a.ApprovalFlowStep.IsRequired {
+			continue
+		}
+		names = append(names, fmt.Sprintf("%q", a.ApprovalFlowStep.StepName))
+	}
+	return names
+}
+
+// completedAt - waktu terakhir ada aksi di grup (dipakai untuk hitung timeout grup berikutnya)
+func (g approvalGroup) completedAt() time.Time {
+	var latest time.Time
+	for _, a := range g.Approvals {
+		if a.ApprovedAt != nil && a.ApprovedAt.After(latest) {
+			latest = *a.ApprovedAt
+		}
+		if a.RejectedAt != nil && a.RejectedAt.After(latest) {
+			latest = *a.RejectedAt
+		}
+		if a.Status == "skipped" && a.UpdatedAt.After(latest) {
+			latest = a.UpdatedAt
+		}
+	}
+	return latest
+}
+
+// loadApprovalGroups - ambil semua approval satu flow di transaksi, dikelompokkan per step_order
+func loadApprovalGroups(db *gorm.DB, transactionNumber, transactionType, flowID string) ([]approvalGroup, error) {
+	var approvals []models.TransactionApproval
+	if err := db.
+		Preload("ApprovalFlowStep").
+		Where("transaction_number = ? AND transaction_type = ? AND flow_id = ?",
+			transactionNumber, transactionType, flowID).
+		Find(&approvals).Error; err != nil {
+		return nil, err
+	}
+
+	return groupApprovals(approvals), nil
+}
+
+// groupApprovals - kelompokkan approval per flow + step_order.
+// Urutan: flow yang di-initiate lebih dulu (misal disposal REQUEST sebelum AGREEMENT), lalu step_order
+func groupApprovals(approvals []models.TransactionApproval) []approvalGroup {
+	index := map[string]int{}
+	flowStartedAt := map[string]time.Time{}
+	var groups []approvalGroup
+
+	for _, a := range approvals {
+		if started, ok := flowStartedAt[a.FlowID]; !ok || a.CreatedAt.Before(started) {
+			flowStartedAt[a.FlowID] = a.CreatedAt
+		}
+	}
+
+	for _, a := range approvals {
+		order := 0
+		quorumType := models.QuorumAll
+		quorumCount := 0
+		if a.ApprovalFlowStep != nil {
+			order = a.ApprovalFlowStep.StepOrder
+			if a.ApprovalFlowStep.QuorumType != "" {
+				quorumType = a.ApprovalFlowStep.QuorumType
+			}
+			if a.ApprovalFlowStep.QuorumCount != nil {
+				quorumCount = *a.ApprovalFlowStep.QuorumCount
+			}
+		}
+
+		key := fmt.Sprintf("%s#%d", a.FlowID, order)
+		i, ok := index[key]
+		if !ok {
+			groups = append(groups, approvalGroup{
+				FlowID:      a.FlowID,
+				StepOrder:   order,
+				QuorumType:  quorumType,
+				QuorumCount: quorumCount,
+			})
+			i = len(groups) - 1
+			index[key] = i
+		}
+		groups[i].Approvals = append(groups[i].Approvals, a)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].FlowID != groups[j].FlowID {
+			return flowStartedAt[groups[i].FlowID].Before(flowStartedAt[groups[j].FlowID])
+		}
+		return groups[i].StepOrder < groups[j].StepOrder
+	})
+
+	return groups
+}
+
+// currentApprovalGroup - grup pertama yang belum selesai (nil kalau semua selesai)
+func currentApprovalGroup(groups []approvalGroup) *approvalGroup {
+	for i := range groups {
+		if !groups[i].isMet() {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+
+// findApprovalGroup - grup tempat step_order ini berada
+func findApprovalGroup(groups []approvalGroup, stepOrder int) *approvalGroup {
+	for i := range groups {
+		if groups[i].StepOrder == stepOrder {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+
+// isApprovalGroupOpen - semua grup sebelum step_order ini (dalam flow yang sama) sudah selesai.
+// groups harus berasal dari satu flow (loadApprovalGroups)
+func isApprovalGroupOpen(groups []approvalGroup, stepOrder int) bool {
+	return blockingApprovalGroup(groups, stepOrder) == nil
+}
+
+// blockingApprovalGroup - grup pertama sebelum step_order ini yang masih menahan
+func blockingApprovalGroup(groups []approvalGroup, stepOrder int) *approvalGroup {
+	for i := range groups {
+		if groups[i].StepOrder >= stepOrder {
+			break
+		}
+		if groups[i].isBlocking() {
+			return &groups[i]
+		}
+	}
+	return nil
+}
+
+// approvalGroupOpenedAt - kapan grup step_order ini terbuka (grup sebelumnya terakhir selesai)
+func approvalGroupOpenedAt(groups []approvalGroup, stepOrder int, createdAt time.Time) time.Time {
+	openedAt := createdAt
+	for _, g := range groups {
+		if g.StepOrder >= stepOrder {
+			break
+		}
+		if completed := g.completedAt(); completed.After(openedAt) {
+			openedAt = completed
+		}
+	}
+	return openedAt
+}
+
+// enforcesGroupOrder - flow yang step_order-nya harus diproses berurutan.
+// sequential & conditional: satu step per order; parallel: satu grup per order
+func enforcesGroupOrder(approvalWay string) bool {
+	switch approvalWay {
+	case "", "sequential", "conditional", "parallel":
+		return true
+	}
+	return false
+}
+
+// closeApprovalGroupIfQuorumMet - skip sisa step pending kalau quorum grup sudah terpenuhi
+func closeApprovalGroupIfQuorumMet(db *gorm.DB, approval models.TransactionApproval) error {
+	if approval.ApprovalFlowStep == nil {
+		return nil
+	}
+
+	groups, err := loadApprovalGroups(db, approval.TransactionNumber, approval.TransactionType, approval.FlowID)
+	if err != nil {
+		return err
+	}
+
+	group := findApprovalGroup(groups, approval.ApprovalFlowStep.StepOrder)
+	if group == nil || !group.isMet() {
+		return nil
+	}
+
+	var pendingIDs []string
+	for _, a := range group.Approvals {
+		if a.Status == "pending" {
+			pendingIDs = append(pendingIDs, a.ID)
+		}
+	}
+	if len(pendingIDs) == 0 {
+		return nil
+	}
+
+	return db.Model(&models.TransactionApproval{}).
+		Where("id IN ? AND status = ?", pendingIDs, "pending").
+		Updates(map[string]interface{}{
+			"status": "skipped",
+			"notes":  "Quorum reached",
+		}).Error
+}
+
+// ============================================================================
+// HELPERS - quorum config per grup step (approval_flow_steps)
+// ============================================================================
+
+// getStepGroupQuorum - quorum grup yang sudah ada di flow + step_order ini
+func getStepGroupQuorum(flowID string, stepOrder int, excludeStepID string) (string, *int, bool) {
+	var step models.ApprovalFlowStep
+	query := config.DB.Where("flow_id = ? AND step_order = ?", flowID, stepOrder)
+	if excludeStepID != "" {
+		query = query.Where("id <> ?", excludeStepID)
+	}
+	if err := query.First(&step).Error; err != nil {
+		return "", nil, false
+	}
+	return step.QuorumType, step.QuorumCount, true
+}
+
+func validateStepQuorum(quorumType string, quorumCount *int) error {
+	if quorumType == models.QuorumNOfM && (quorumCount == nil || *quorumCount < 1) {
+		return errors.New("quorum_count is required when quorum_type is n_of_m")
+	}
+	return nil
+}
+
+// syncStepGroupQuorum - samakan quorum semua step di grup yang sama
+func syncStepGroupQuorum(flowID string, stepOrder int, quorumType string, quorumCount *int) error {
+	return config.DB.Model(&models.ApprovalFlowStep{}).
+		Where("flow_id = ? AND step_order = ?", flowID, stepOrder).
+		Updates(map[string]interface{}{
+			"quorum_type":  quorumType,
+			"quorum_count": quorumCount,
+		}).Error
+}
+
+// ============================================================================
+// HELPERS - lanjutan setelah step diproses (approve / skip / reject)
+// ============================================================================
+
+// onApprovalStepCompleted - dipanggil setelah step approved / skipped:
+// tutup grup kalau quorum terpenuhi, lalu trigger auto-complete transaksi
+func onApprovalStepCompleted(userID string, approval models.TransactionApproval, meta dto.RequestMeta) {
+	if err := closeApprovalGroupIfQuorumMet(config.DB, approval); err != nil {
+		fmt.Printf("close approval group warning: %v\n", err)
+	}
+
+	runApprovalCompletionHooks(userID, approval.TransactionNumber, approval.TransactionType, meta)
+}
+
+// onApprovalStepRejected - dipanggil setelah step rejected.
+// Transaksi hanya di-reject kalau quorum grupnya sudah tidak mungkin tercapai
+func onApprovalStepRejected(userID string, approval models.TransactionApproval, notes string, meta dto.RequestMeta) error {
+	if approval.ApprovalFlowStep != nil {
+		groups, err := loadApprovalGroups(config.DB, approval.TransactionNumber, approval.TransactionType, approval.FlowID)
+		if err != nil {
+			return err
+		}
+
+		group := findApprovalGroup(groups, approval.ApprovalFlowStep.StepOrder)
+		if group != nil && !group.isFailed() {
+			// Quorum masih bisa tercapai oleh approver lain di grup ini
+			if group.isMet() {
+				onApprovalStepCompleted(userID, approval, meta)
+			}
+			return nil
+		}
+	}
+
+	return autoRejectTransaction(userID, approval.TransactionNumber, approval.TransactionType, notes, meta)
+}
+
+// validateApprovalGroupOpen - tolak approve / reject step yang grupnya belum terbuka.
+// db bisa berupa tx yang sudah lock approval flow ini (lockFlowApprovals)
+func validateApprovalGroupOpen(db *gorm.DB, approval models.TransactionApproval) error {
+	if approval.ApprovalFlowStep == nil || !enforcesGroupOrder(approval.ApprovalFlow.ApprovalWay) {
+		return nil
+	}
+
+	groups, err := loadApprovalGroups(db, approval.TransactionNumber, approval.TransactionType, approval.FlowID)
+	if err != nil {
+		return err
+	}
+
+	blocking := blockingApprovalGroup(groups, approval.ApprovalFlowStep.StepOrder)
+	if blocking == nil {
+		return nil
+	}
+
+	if pending := blocking.pendingRequiredSteps(); len(pending) > 0 {
+		return fmt.Errorf("step %q (order %d) cannot be processed yet: earlier required step %s (order %d) is still pending",
+			approval.ApprovalFlowStep.StepName, approval.ApprovalFlowStep.StepOrder,
+			strings.Join(pending, ", "), blocking.StepOrder)
+	}
+	return fmt.Errorf("step %q (order %d) cannot be processed: step order %d did not reach its quorum",
+		approval.ApprovalFlowStep.StepName, approval.ApprovalFlowStep.StepOrder, blocking.StepOrder)
+}
+
+// lockFlowApprovals - SELECT ... FOR UPDATE semua approval satu flow di transaksi.
+// Approver yang bertindak bersamaan diproses bergantian, sehingga cek urutan,
+// cek status pending, dan quorum selalu membaca data terbaru
+func lockFlowApprovals(tx *gorm.DB, approval models.TransactionApproval) error {
+	var locked []models.TransactionApproval
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("transaction_number = ? AND transaction_type = ? AND flow_id = ?",
+			approval.TransactionNumber, approval.TransactionType, approval.FlowID).
+		Order("id").
+		Find(&locked).Error
+}
+
+// filterActionableApprovals - buang pending approval yang grupnya belum terbuka
+func filterActionableApprovals(approvals []models.TransactionApproval) ([]models.TransactionApproval, error) {
+	groupCache := map[string][]approvalGroup{}
+	result := make([]models.TransactionApproval, 0, len(approvals))
+
+	for _, approval := range approvals {
+		if approval.ApprovalFlowStep == nil || !enforcesGroupOrder(approval.ApprovalFlow.ApprovalWay) {
+			result = append(result, approval)
+			continue
+		}
+
+		key := approval.TransactionNumber + "#" + approval.TransactionType + "#" + approval.FlowID
+		groups, ok := groupCache[key]
+		if !ok {
+			var err error
+			groups, err = loadApprovalGroups(config.DB, approval.TransactionNumber, approval.TransactionType, approval.FlowID)
+			if err != nil {
+				return nil, err
+			}
+			groupCache[key] = groups
+		}
+
+		if isApprovalGroupOpen(groups, approval.ApprovalFlowStep.StepOrder) {
+			result = append(result, approval)
+		}
+	}
+
+	return result, nil
+}
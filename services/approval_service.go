@@ -7,7 +7,6 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"sort"
 	"time"
 
 	"gorm.io/gorm"
@@ -113,6 +112,14 @@ func CreateApprovalFlow(req dto.CreateApprovalFlowRequest) (*dto.ApprovalFlowRes
 		return nil, errors.New("assigned_user_id is required when assignment_type is user_specific")
 	}
 
+	timeoutAction := req.TimeoutAction
+	if timeoutAction == "" {
+		timeoutAction = models.TimeoutActionNone
+	}
+	if err := validateEscalationTarget(timeoutAction, req.EscalationRoleID, req.EscalationUserID); err != nil {
+		return nil, err
+	}
+
 	// Convert allowed_creator_roles to JSON string
 	var allowedRolesJSON *string
 	if len(req.AllowedCreatorRoles) > 0 {
@@ -128,6 +135,10 @@ func CreateApprovalFlow(req dto.CreateApprovalFlowRequest) (*dto.ApprovalFlowRes
 		FlowCode:            req.FlowCode,
 		FlowName:            req.FlowName,
 		ApprovalWay:         req.ApprovalWay,
+		TimeoutAction:       timeoutAction,
+		EscalationRoleID:    req.EscalationRoleID,
+		EscalationUserID:    req.EscalationUserID,
+		RequireSignature:    req.RequireSignature,
 		AssignmentType:      req.AssignmentType,
 		AssignedUserID:      req.AssignedUserID,
 		IsCustomizable:      req.IsCustomizable,
@@ -166,6 +177,18 @@ func UpdateApprovalFlow(id string, req dto.UpdateApprovalFlowRequest) (*dto.Appr
 	if req.ApprovalWay != "" {
 		updates["approval_way"] = req.ApprovalWay
 	}
+	if req.TimeoutAction != "" {
+		updates["timeout_action"] = req.TimeoutAction
+	}
+	if req.EscalationRoleID != nil {
+		updates["escalation_role_id"] = req.EscalationRoleID
+	}
+	if req.EscalationUserID != nil {
+		updates["escalation_user_id"] = req.EscalationUserID
+	}
+	if req.RequireSignature != nil {
+		updates["require_signature"] = *req.RequireSignature
+	}
 	if req.AssignmentType != "" {
 		updates["assignment_type"] = req.AssignmentType
 	}
@@ -186,6 +209,22 @@ func UpdateApprovalFlow(id string, req dto.UpdateApprovalFlowRequest) (*dto.Appr
 		updates["is_active"] = *req.IsActive
 	}
 
+	// Validasi target eskalasi dari nilai akhir flow (gabungan data lama + update)
+	timeoutAction := flow.TimeoutAction
+	if req.TimeoutAction != "" {
+		timeoutAction = req.TimeoutAction
+	}
+	escalationRoleID, escalationUserID := flow.EscalationRoleID, flow.EscalationUserID
+	if req.EscalationRoleID != nil {
+		escalationRoleID = req.EscalationRoleID
+	}
+	if req.EscalationUserID != nil {
+		escalationUserID = req.EscalationUserID
+	}
+	if err := validateEscalationTarget(timeoutAction, escalationRoleID, escalationUserID); err != nil {
+		return nil, err
+	}
+
 	if err := config.DB.Model(&flow).Updates(updates).Error; err != nil {
 		return nil, err
 	}
@@ -193,6 +232,36 @@ func UpdateApprovalFlow(id string, req dto.UpdateApprovalFlowRequest) (*dto.Appr
 	return GetApprovalFlowByID(id)
 }
 
+// validateEscalationTarget - timeout_action escalate wajib punya role / user tujuan yang benar-benar ada
+func validateEscalationTarget(timeoutAction string, roleID, userID *string) error {
+	hasRole := roleID != nil && *roleID != ""
+	hasUser := userID != nil && *userID != ""
+
+	if timeoutAction == models.TimeoutActionEscalate && !hasRole && !hasUser {
+		return errors.New("escalation_role_id or escalation_user_id is required when timeout_action is escalate")
+	}
+
+	if hasRole {
+		var count int64
+		if err := config.DB.Model(&models.Role{}).Where("id = ?", *roleID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.New("escalation role not found")
+		}
+	}
+	if hasUser {
+		var count int64
+		if err := config.DB.Model(&models.User{}).Where("id = ?", *userID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return errors.New("escalation user not found")
+		}
+	}
+	return nil
+}
+
 func DeleteApprovalFlow(id string) error {
 	var flow models.ApprovalFlow
 	if err := config.DB.First(&flow, "id = ?", id).Error; err != nil {
@@ -241,6 +310,28 @@ func CreateApprovalFlowStep(req dto.CreateApprovalFlowStepRequest) (*dto.Approva
 		}
 	}
 
+	// Validate conditions format
+	if _, err := ParseApprovalConditions(req.Conditions); err != nil {
+		return nil, err
+	}
+	if _, err := ParseAutoApproveRules(req.AutoApproveRules); err != nil {
+		return nil, err
+	}
+
+	// Quorum grup paralel — kalau tidak diisi, ikut grup step_order yang sudah ada
+	quorumType := req.QuorumType
+	quorumCount := req.QuorumCount
+	if quorumType == "" {
+		if groupType, groupCount, ok := getStepGroupQuorum(req.FlowID, req.StepOrder, ""); ok {
+			quorumType, quorumCount = groupType, groupCount
+		} else {
+			quorumType = models.QuorumAll
+		}
+	}
+	if err := validateStepQuorum(quorumType, quorumCount); err != nil {
+		return nil, err
+	}
+
 	// Set defaults if not provided
 	stepType := "all"
 	stepCategory := "all"
@@ -257,28 +348,38 @@ func CreateApprovalFlowStep(req dto.CreateApprovalFlowStepRequest) (*dto.Approva
 	}
 
 	step := models.ApprovalFlowStep{
-		FlowID:       req.FlowID,
-		StepOrder:    req.StepOrder,
-		StepName:     req.StepName,
-		StepRole:     req.StepRole,
-		RoleID:       req.RoleID,
-		BranchID:     req.BranchID,
-		Structure:    req.Structure,
-		IsRequired:   req.IsRequired,
-		CanSkip:      req.CanSkip,
-		IsVisible:    req.IsVisible,
-		Type:         stepType,
-		Category:     stepCategory,
-		ApprovalWay:  stepApprovalWay,
-		AutoApprove:  req.AutoApprove,
-		TimeoutHours: req.TimeoutHours,
-		Conditions:   req.Conditions,
+		FlowID:           req.FlowID,
+		StepOrder:        req.StepOrder,
+		QuorumType:       quorumType,
+		QuorumCount:      quorumCount,
+		StepName:         req.StepName,
+		StepRole:         req.StepRole,
+		RoleID:           req.RoleID,
+		BranchID:         req.BranchID,
+		Structure:        req.Structure,
+		IsRequired:       req.IsRequired,
+		CanSkip:          req.CanSkip,
+		IsVisible:        req.IsVisible,
+		Type:             stepType,
+		Category:         stepCategory,
+		ApprovalWay:      stepApprovalWay,
+		AutoApprove:      req.AutoApprove,
+		AutoApproveRules: req.AutoApproveRules,
+		TimeoutHours:     req.TimeoutHours,
+		Conditions:       req.Conditions,
 	}
 
 	if err := config.DB.Create(&step).Error; err != nil {
 		return nil, err
 	}
 
+	// Quorum diisi eksplisit → berlaku untuk semua step di grup yang sama
+	if req.QuorumType != "" {
+		if err := syncStepGroupQuorum(step.FlowID, step.StepOrder, quorumType, quorumCount); err != nil {
+			return nil, err
+		}
+	}
+
 	return GetApprovalFlowStepByID(step.ID)
 }
 
@@ -310,8 +411,34 @@ func UpdateApprovalFlowStep(id string, req dto.UpdateApprovalFlowStepRequest) (*
 
 	updates := make(map[string]interface{})
 
+	stepOrder := step.StepOrder
 	if req.StepOrder != nil {
 		updates["step_order"] = *req.StepOrder
+		stepOrder = *req.StepOrder
+	}
+
+	syncQuorum := false
+	quorumType := step.QuorumType
+	quorumCount := step.QuorumCount
+	if req.QuorumType != nil || req.QuorumCount != nil {
+		if req.QuorumType != nil {
+			quorumType = *req.QuorumType
+		}
+		if req.QuorumCount != nil {
+			quorumCount = req.QuorumCount
+		}
+		if err := validateStepQuorum(quorumType, quorumCount); err != nil {
+			return nil, err
+		}
+		updates["quorum_type"] = quorumType
+		updates["quorum_count"] = quorumCount
+		syncQuorum = true
+	} else if stepOrder != step.StepOrder {
+		// Pindah grup → ikut quorum grup tujuan
+		if groupType, groupCount, ok := getStepGroupQuorum(step.FlowID, stepOrder, step.ID); ok {
+			updates["quorum_type"] = groupType
+			updates["quorum_count"] = groupCount
+		}
 	}
 	if req.StepName != "" {
 		updates["step_name"] = req.StepName
@@ -349,17 +476,40 @@ func UpdateApprovalFlowStep(id string, req dto.UpdateApprovalFlowStepRequest) (*
 	if req.AutoApprove != nil {
 		updates["auto_approve"] = *req.AutoApprove
 	}
+	if req.AutoApproveRules != nil {
+		if _, err := ParseAutoApproveRules(req.AutoApproveRules); err != nil {
+			return nil, err
+		}
+		if *req.AutoApproveRules == "" {
+			updates["auto_approve_rules"] = nil // kosongkan rules
+		} else {
+			updates["auto_approve_rules"] = req.AutoApproveRules
+		}
+	}
 	if req.TimeoutHours != nil {
 		updates["timeout_hours"] = req.TimeoutHours
 	}
 	if req.Conditions != nil {
-		updates["conditions"] = req.Conditions
+		if _, err := ParseApprovalConditions(req.Conditions); err != nil {
+			return nil, err
+		}
+		if *req.Conditions == "" {
+			updates["conditions"] = nil // kosongkan conditions
+		} else {
+			updates["conditions"] = req.Conditions
+		}
 	}
 
 	if err := config.DB.Model(&step).Updates(updates).Error; err != nil {
 		return nil, err
 	}
 
+	if syncQuorum {
+		if err := syncStepGroupQuorum(step.FlowID, stepOrder, quorumType, quorumCount); err != nil {
+			return nil, err
+		}
+	}
+
 	return GetApprovalFlowStepByID(id)
 }
 
@@ -426,9 +576,10 @@ func InitiateTransactionApproval(req dto.CreateTransactionApprovalRequest) error
 	}
 
 	// Check if approval already exists for this transaction
+	// (per flow — disposal punya 2 flow: APPROVAL_REQUEST & APPROVAL_AGREEMENT)
 	var existingCount int64
 	config.DB.Model(&models.TransactionApproval{}).
-		Where("transaction_number = ? AND transaction_type = ?", req.TransactionNumber, req.TransactionType).
+		Where("transaction_number = ? AND transaction_type = ? AND flow_id = ?", req.TransactionNumber, req.TransactionType, req.FlowID).
 		Count(&existingCount)
 
 	if existingCount > 0 {
@@ -442,41 +593,89 @@ func InitiateTransactionApproval(req dto.CreateTransactionApprovalRequest) error
 		// return err
 	}
 
-	// Create approval records for each step
-	for _, step := range flow.FlowSteps {
-		approval := models.TransactionApproval{
-			FlowID:            req.FlowID,
-			FlowStepID:        step.ID,
-			TransactionNumber: req.TransactionNumber,
-			TransactionType:   req.TransactionType,
-			Status:            "pending",
-			StatusView:        "visible",
-			Metadata:          req.Metadata,
-		}
+	openBefore := snapshotOpenApprovals(req.TransactionNumber, req.TransactionType)
+
+	// Data transaksi untuk evaluasi step conditions — di-load sekali saja
+	var conditionCtx *ApprovalConditionContext
+	skippedCount := 0
+
+	// Create approval records for each step — satu transaksi, error di tengah tidak meninggalkan
+	// sebagian step
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		for _, step := range flow.FlowSteps {
+			approval := models.TransactionApproval{
+				FlowID:            req.FlowID,
+				FlowStepID:        step.ID,
+				TransactionNumber: req.TransactionNumber,
+				TransactionType:   req.TransactionType,
+				Status:            "pending",
+				StatusView:        "visible",
+				Metadata:          req.Metadata,
+			}
 
-		// Assign approver based on step configuration
-		if step.RoleID != nil {
-			approval.ApproverRoleID = step.RoleID
-		}
+			// Conditional step — kalau kondisi tidak terpenuhi, step langsung skipped
+			conditions, err := ParseApprovalConditions(step.Conditions)
+			if err != nil {
+				return fmt.Errorf("step %s: %w", step.StepName, err)
+			}
+			if conditions != nil {
+				if conditionCtx == nil {
+					ctx, err := buildApprovalConditionContext(req.TransactionNumber, req.TransactionType)
+					if err != nil {
+						return err
+					}
+					conditionCtx = &ctx
+				}
+
+				if ok, reason := conditions.Evaluate(*conditionCtx); !ok {
+					approval.Status = "skipped"
+					approval.Notes = &reason
+					skippedCount++
+				}
+			}
 
-		// Set status_view based on step configuration
-		if !step.IsVisible {
-			approval.StatusView = "hidden"
-		}
+			// Assign approver based on step configuration
+			if step.RoleID != nil {
+				approval.ApproverRoleID = step.RoleID
+			}
 
-		if err := config.DB.Create(&approval).Error; err != nil {
-			return err
+			// Set status_view based on step configuration
+			if !step.IsVisible {
+				approval.StatusView = "hidden"
+			}
+
+			if err := tx.Create(&approval).Error; err != nil {
+				return err
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Step auto_approve di grup pertama langsung di-approve sistem
+	autoApproved, err := processAutoApprovals(req.TransactionNumber, req.TransactionType)
+	if err != nil {
+		fmt.Printf("auto approve warning: %v\n", err)
+	}
+
+	// Kalau semua step ter-skip / auto approve, transaksi langsung lanjut ke stage berikutnya
+	if skippedCount > 0 || autoApproved > 0 {
+		runApprovalCompletionHooks(SystemActorID, req.TransactionNumber, req.TransactionType, dto.RequestMeta{})
 	}
 
+	notifyOpenedApprovals(req.TransactionNumber, req.TransactionType, openBefore)
+
 	return nil
 }
 
 // ApproveTransaction approves a specific approval step
-func ApproveTransaction(userID string, req dto.ApproveTransactionRequest) error {
+func ApproveTransaction(userID string, req dto.ApproveTransactionRequest, meta dto.RequestMeta) error {
 	var approval models.TransactionApproval
 
 	if err := config.DB.
+		Preload("ApprovalFlow").
 		Preload("ApprovalFlowStep").
 		First(&approval, "id = ?", req.TransactionApprovalID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -490,25 +689,27 @@ func ApproveTransaction(userID string, req dto.ApproveTransactionRequest) error
 		return errors.New("approval already processed")
 	}
 
-	// Check if user has permission to approve
-	if approval.ApproverUserID != nil && *approval.ApproverUserID != userID {
-		return errors.New("you are not authorized to approve this transaction")
+	// Step hanya bisa diproses setelah step / grup sebelumnya selesai
+	if err := validateApprovalGroupOpen(config.DB, approval); err != nil {
+		return err
 	}
 
-	// If approval is by role, check if user has the role
-	if approval.ApproverRoleID != nil {
-		var userRole models.UserRole
-		if err := config.DB.
-			Where("user_id = ? AND role_id = ?", userID, *approval.ApproverRoleID).
-			First(&userRole).Error; err != nil {
-			return errors.New("you do not have the required role to approve this transaction")
-		}
+	// Cek user approver langsung (user / role / branch), atau delegate dari approver yang sedang cuti
+	delegation, err := resolveApprovalActor(userID, approval, "approve")
+	if err != nil {
+		return err
 	}
 
-	// Validasi branch approver harus sama dengan branch creator transaksi
-	if err := validateApproverBranch(userID, approval.TransactionNumber, approval.TransactionType); err != nil {
+	// Tanda tangan aktif milik user yang approve (delegate pakai tanda tangannya sendiri)
+	userSignature, err := getActiveUserSignature(config.DB, userID)
+	if err != nil {
 		return err
 	}
+	if userSignature == nil && approval.ApprovalFlow.RequireSignature {
+		return errors.New("you must upload a signature before approving this transaction")
+	}
+
+	openBefore := snapshotOpenApprovals(approval.TransactionNumber, approval.TransactionType)
 
 	// Update approval
 	now := time.Now()
@@ -519,15 +720,41 @@ func ApproveTransaction(userID string, req dto.ApproveTransactionRequest) error
 		"notes":       req.Notes,
 	}
 
-	if err := config.DB.Model(&approval).Updates(updates).Error; err != nil {
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock approval flow ini, lalu cek ulang status & urutan dengan data terbaru
+	if err := lockFlowApprovals(tx, approval); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := validateApprovalGroupOpen(tx, approval); err != nil {
+		tx.Rollback()
 		return err
 	}
 
+	result := tx.Model(&models.TransactionApproval{}).
+		Where("id = ? AND status = ?", approval.ID, "pending").
+		Updates(updates)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return errors.New("approval already processed")
+	}
+
 	// Create signature record
 	signature := models.ApprovalSignature{
 		TransactionNumber: approval.TransactionNumber,
 		TransactionType:   approval.TransactionType,
-		UserID:            userID,
+		UserID:            &userID,
 		RoleID:            approval.ApproverRoleID,
 		StepRole:          approval.ApprovalFlowStep.StepRole,
 		SignedAt:          now,
@@ -536,28 +763,52 @@ func ApproveTransaction(userID string, req dto.ApproveTransactionRequest) error
 		IsRecent:          true,
 	}
 
-	if err := config.DB.Create(&signature).Error; err != nil {
+	// Audit trail request yang memicu approve / reject
+	setSignatureRequestMeta(&signature, meta)
+
+	if delegation != nil {
+		signature.OnBehalfOfUserID = &delegation.DelegatorID
+		signature.DelegationID = &delegation.ID
+	}
+
+	// Path versi yang aktif saat ini — versi baru nanti tidak mengubah dokumen ini
+	if userSignature != nil {
+		signature.SignaturePath = &userSignature.FilePath
+	}
+
+	if err := tx.Create(&signature).Error; err != nil {
+		tx.Rollback()
 		return err
 	}
 
-	// Auto-trigger complete approval jika semua step sudah approved
-	if err := autoCompleteProcurementApproval(userID, approval.TransactionNumber, approval.TransactionType); err != nil {
-		fmt.Printf("auto complete procurement approval warning: %v\n", err)
+	// Tutup grup kalau quorum terpenuhi — masih di dalam lock supaya approver lain
+	// di grup yang sama tidak ikut approve setelah quorum tercapai
+	if err := closeApprovalGroupIfQuorumMet(tx, approval); err != nil {
+		tx.Rollback()
+		return err
 	}
 
-	// Auto-trigger untuk mutasi
-	if err := autoCompleteMutationApproval(userID, approval.TransactionNumber, approval.TransactionType); err != nil {
-		fmt.Printf("auto complete mutation approval warning: %v\n", err)
+	if err := tx.Commit().Error; err != nil {
+		return err
 	}
 
+	notifyApprovalClosed(approval, "approved", userID, req.Notes)
+
+	// Auto-complete transaksi jika semua step selesai
+	runApprovalCompletionHooks(userID, approval.TransactionNumber, approval.TransactionType, meta)
+
+	// Notifikasi approver step berikutnya yang baru terbuka
+	notifyOpenedApprovals(approval.TransactionNumber, approval.TransactionType, openBefore)
+
 	return nil
 }
 
 // RejectTransaction rejects a specific approval step
-func RejectTransaction(userID string, req dto.RejectTransactionRequest) error {
+func RejectTransaction(userID string, req dto.RejectTransactionRequest, meta dto.RequestMeta) error {
 	var approval models.TransactionApproval
 
 	if err := config.DB.
+		Preload("ApprovalFlow").
 		Preload("ApprovalFlowStep").
 		First(&approval, "id = ?", req.TransactionApprovalID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -571,26 +822,19 @@ func RejectTransaction(userID string, req dto.RejectTransactionRequest) error {
 		return errors.New("approval already processed")
 	}
 
-	// Check if user has permission to reject
-	if approval.ApproverUserID != nil && *approval.ApproverUserID != userID {
-		return errors.New("you are not authorized to reject this transaction")
-	}
-
-	// If approval is by role, check if user has the role
-	if approval.ApproverRoleID != nil {
-		var userRole models.UserRole
-		if err := config.DB.
-			Where("user_id = ? AND role_id = ?", userID, *approval.ApproverRoleID).
-			First(&userRole).Error; err != nil {
-			return errors.New("you do not have the required role to reject this transaction")
-		}
+	// Step hanya bisa diproses setelah step / grup sebelumnya selesai
+	if err := validateApprovalGroupOpen(config.DB, approval); err != nil {
+		return err
 	}
 
-	// Validasi branch approver harus sama dengan branch creator transaksi
-	if err := validateApproverBranch(userID, approval.TransactionNumber, approval.TransactionType); err != nil {
+	// Cek user approver langsung (user / role / branch), atau delegate dari approver yang sedang cuti
+	delegation, err := resolveApprovalActor(userID, approval, "reject")
+	if err != nil {
 		return err
 	}
 
+	openBefore := snapshotOpenApprovals(approval.TransactionNumber, approval.TransactionType)
+
 	// Update approval
 	now := time.Now()
 	updates := map[string]interface{}{
@@ -600,15 +844,41 @@ func RejectTransaction(userID string, req dto.RejectTransactionRequest) error {
 		"notes":       req.Notes,
 	}
 
-	if err := config.DB.Model(&approval).Updates(updates).Error; err != nil {
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock approval flow ini, lalu cek ulang status & urutan dengan data terbaru
+	if err := lockFlowApprovals(tx, approval); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := validateApprovalGroupOpen(tx, approval); err != nil {
+		tx.Rollback()
 		return err
 	}
 
+	result := tx.Model(&models.TransactionApproval{}).
+		Where("id = ? AND status = ?", approval.ID, "pending").
+		Updates(updates)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return errors.New("approval already processed")
+	}
+
 	// Create signature record
 	signature := models.ApprovalSignature{
 		TransactionNumber: approval.TransactionNumber,
 		TransactionType:   approval.TransactionType,
-		UserID:            userID,
+		UserID:            &userID,
 		RoleID:            approval.ApproverRoleID,
 		StepRole:          approval.ApprovalFlowStep.StepRole,
 		SignedAt:          now,
@@ -617,18 +887,53 @@ func RejectTransaction(userID string, req dto.RejectTransactionRequest) error {
 		IsRecent:          true,
 	}
 
-	if err := config.DB.Create(&signature).Error; err != nil {
+	// Audit trail request yang memicu approve / reject
+	setSignatureRequestMeta(&signature, meta)
+
+	if delegation != nil {
+		signature.OnBehalfOfUserID = &delegation.DelegatorID
+		signature.DelegationID = &delegation.ID
+	}
+
+	if err := tx.Create(&signature).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
 		return err
 	}
 
-	// Auto-reject transaksi jika approval di-reject
-	if err := autoRejectTransaction(userID, approval.TransactionNumber, approval.TransactionType, *req.Notes); err != nil {
+	notifyApprovalClosed(approval, "rejected", userID, req.Notes)
+
+	// Auto-reject transaksi jika approval di-reject (dan quorum grup tidak mungkin tercapai)
+	notes := ""
+	if req.Notes != nil {
+		notes = *req.Notes
+	}
+	if err := onApprovalStepRejected(userID, approval, notes, meta); err != nil {
 		fmt.Printf("auto reject transaction warning: %v\n", err)
 	}
 
+	// Quorum grup bisa tetap tercapai walau ada yang reject → step berikutnya terbuka
+	notifyOpenedApprovals(approval.TransactionNumber, approval.TransactionType, openBefore)
+
 	return nil
 }
 
+// setSignatureRequestMeta - isi IP, user agent & request ID; kosong untuk aksi sistem
+func setSignatureRequestMeta(signature *models.ApprovalSignature, meta dto.RequestMeta) {
+	if meta.IPAddress != "" {
+		signature.IPAddress = &meta.IPAddress
+	}
+	if meta.UserAgent != "" {
+		signature.UserAgent = &meta.UserAgent
+	}
+	if meta.RequestID != "" {
+		signature.RequestID = &meta.RequestID
+	}
+}
+
 // GetTransactionApprovalStatus gets full approval status for a transaction
 func GetTransactionApprovalStatus(transactionNumber, transactionType string) (*dto.TransactionApprovalSummary, error) {
 	var approvals []models.TransactionApproval
@@ -648,45 +953,60 @@ func GetTransactionApprovalStatus(transactionNumber, transactionType string) (*d
 		return nil, errors.New("no approval found for this transaction")
 	}
 
-	// Sort by step_order setelah fetch karena ORDER BY tidak bisa langsung
-	// ke kolom di table lain tanpa explicit JOIN
-	sort.Slice(approvals, func(i, j int) bool {
-		stepI, stepJ := 0, 0
-		if approvals[i].ApprovalFlowStep != nil {
-			stepI = approvals[i].ApprovalFlowStep.StepOrder
-		}
-		if approvals[j].ApprovalFlowStep != nil {
-			stepJ = approvals[j].ApprovalFlowStep.StepOrder
-		}
-		return stepI < stepJ
-	})
+	// Kelompokkan per flow + step_order (grup paralel), urut step_order
+	groups := groupApprovals(approvals)
+
+	ordered := make([]models.TransactionApproval, 0, len(approvals))
+	for _, g := range groups {
+		ordered = append(ordered, g.Approvals...)
+	}
+	approvals = ordered
 
 	// Calculate summary
 	totalSteps := len(approvals)
 	completedSteps := 0
-	var currentStep *dto.ApprovalFlowStepResponse
 	overallStatus := "pending"
 
-	for i, approval := range approvals {
-		if approval.Status == "approved" {
+	for _, approval := range approvals {
+		// skipped (conditions / timeout / quorum) dihitung selesai
+		if approval.Status == "approved" || approval.Status == "skipped" {
 			completedSteps++
-		} else if approval.Status == "rejected" {
+		}
+	}
+
+	// Grup aktif = grup pertama yang belum memenuhi quorum.
+	// Hanya step pending di grup ini yang bisa diproses sekarang
+	var currentStep *dto.ApprovalFlowStepResponse
+	var currentStepOrder *int
+	currentSteps := []dto.TransactionApprovalResponse{}
+
+	for _, g := range groups {
+		if g.isFailed() {
 			overallStatus = "rejected"
 			break
-		} else if approval.Status == "pending" && currentStep == nil {
-			if approval.ApprovalFlowStep != nil {
-				step := mapApprovalFlowStepToResponse(*approval.ApprovalFlowStep)
-				currentStep = &step
-			}
 		}
+	}
 
-		// Check if this is the last approval and it's approved
-		if i == len(approvals)-1 && approval.Status == "approved" {
+	if overallStatus != "rejected" {
+		if current := currentApprovalGroup(groups); current != nil {
+			order := current.StepOrder
+			currentStepOrder = &order
+			for _, approval := range current.Approvals {
+				if approval.Status != "pending" {
+					continue
+				}
+				if currentStep == nil && approval.ApprovalFlowStep != nil {
+					step := mapApprovalFlowStepToResponse(*approval.ApprovalFlowStep)
+					currentStep = &step
+				}
+				currentSteps = append(currentSteps, mapTransactionApprovalToResponse(approval))
+			}
+		} else {
 			overallStatus = "approved"
 		}
 	}
 
-	if completedSteps > 0 && completedSteps < totalSteps && overallStatus != "rejected" {
+	if overallStatus == "pending" && completedSteps > 0 {
 		overallStatus = "in_progress"
 	}
 
@@ -696,6 +1016,8 @@ func GetTransactionApprovalStatus(transactionNumber, transactionType string) (*d
 		TotalSteps:        totalSteps,
 		CompletedSteps:    completedSteps,
 		CurrentStep:       currentStep,
+		CurrentStepOrder:  currentStepOrder,
+		CurrentSteps:      currentSteps,
 		Status:            overallStatus,
 		Approvals:         mapTransactionApprovalsToResponse(approvals),
 		CreatedAt:         approvals[0].CreatedAt,
@@ -706,6 +1028,56 @@ func GetTransactionApprovalStatus(transactionNumber, transactionType string) (*d
 
 // GetUserPendingApprovals gets all pending approvals for a user
 func GetUserPendingApprovals(userID string) ([]dto.TransactionApprovalResponse, error) {
+	approvals, err := findPendingApprovalsFor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Tambahkan approval milik user yang sedang mendelegasikan ke user ini
+	delegations, err := getCurrentDelegationsFor(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, a := range approvals {
+		seen[a.ID] = true
+	}
+
+	onBehalfOf := map[string]string{}
+	for _, d := range delegations {
+		delegated, err := findPendingApprovalsFor(d.DelegatorID)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range delegated {
+			if seen[a.ID] || !delegationCovers(d, a.ApprovalFlow.FlowCode, a.TransactionType) {
+				continue
+			}
+			seen[a.ID] = true
+			onBehalfOf[a.ID] = d.DelegatorID
+			approvals = append(approvals, a)
+		}
+	}
+
+	// Hanya step yang grupnya sudah terbuka (bisa diproses sekarang)
+	approvals, err = filterActionableApprovals(approvals)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := mapTransactionApprovalsToResponse(approvals)
+	for i := range responses {
+		if delegatorID, ok := onBehalfOf[responses[i].ID]; ok {
+			responses[i].OnBehalfOfUserID = &delegatorID
+		}
+	}
+
+	return responses, nil
+}
+
+// findPendingApprovalsFor - pending approval yang ditujukan ke user / role user
+func findPendingApprovalsFor(userID string) ([]models.TransactionApproval, error) {
 	// Get user roles
 	var userRoles []models.UserRole
 	if err := config.DB.Where("user_id = ?", userID).Find(&userRoles).Error; err != nil {
@@ -721,6 +1093,7 @@ func GetUserPendingApprovals(userID string) ([]dto.TransactionApprovalResponse,
 	var approvals []models.TransactionApproval
 
 	query := config.DB.
+		Preload("ApprovalFlow").
 		Preload("ApprovalFlowStep").
 		Preload("ApproverUser").
 		Preload("ApproverRole").
@@ -737,7 +1110,7 @@ func GetUserPendingApprovals(userID string) ([]dto.TransactionApprovalResponse,
 		return nil, err
 	}
 
-	return mapTransactionApprovalsToResponse(approvals), nil
+	return approvals, nil
 }
 
 // ============================================================================
@@ -751,6 +1124,10 @@ func mapApprovalFlowToResponse(flow models.ApprovalFlow) dto.ApprovalFlowRespons
 		BranchCode:          flow.BranchCode,
 		FlowName:            flow.FlowName,
 		ApprovalWay:         flow.ApprovalWay,
+		TimeoutAction:       flow.TimeoutAction,
+		EscalationRoleID:    flow.EscalationRoleID,
+		EscalationUserID:    flow.EscalationUserID,
+		RequireSignature:    flow.RequireSignature,
 		AssignmentType:      flow.AssignmentType,
 		AssignedUserID:      flow.AssignedUserID,
 		IsCustomizable:      flow.IsCustomizable,
@@ -802,25 +1179,28 @@ func mapApprovalFlowsToResponse(flows []models.ApprovalFlow) []dto.ApprovalFlowR
 
 func mapApprovalFlowStepToResponse(step models.ApprovalFlowStep) dto.ApprovalFlowStepResponse {
 	response := dto.ApprovalFlowStepResponse{
-		ID:           step.ID,
-		FlowID:       step.FlowID,
-		StepOrder:    step.StepOrder,
-		StepName:     step.StepName,
-		StepRole:     step.StepRole,
-		RoleID:       step.RoleID,
-		BranchID:     step.BranchID,
-		Structure:    step.Structure,
-		IsRequired:   step.IsRequired,
-		CanSkip:      step.CanSkip,
-		IsVisible:    step.IsVisible,
-		Type:         step.Type,
-		Category:     step.Category,
-		ApprovalWay:  step.ApprovalWay,
-		AutoApprove:  step.AutoApprove,
-		TimeoutHours: step.TimeoutHours,
-		Conditions:   step.Conditions,
-		CreatedAt:    step.CreatedAt,
-		UpdatedAt:    step.UpdatedAt,
+		ID:               step.ID,
+		FlowID:           step.FlowID,
+		StepOrder:        step.StepOrder,
+		QuorumType:       step.QuorumType,
+		QuorumCount:      step.QuorumCount,
+		StepName:         step.StepName,
+		StepRole:         step.StepRole,
+		RoleID:           step.RoleID,
+		BranchID:         step.BranchID,
+		Structure:        step.Structure,
+		IsRequired:       step.IsRequired,
+		CanSkip:          step.CanSkip,
+		IsVisible:        step.IsVisible,
+		Type:             step.Type,
+		Category:         step.Category,
+		ApprovalWay:      step.ApprovalWay,
+		AutoApprove:      step.AutoApprove,
+		AutoApproveRules: step.AutoApproveRules,
+		TimeoutHours:     step.TimeoutHours,
+		Conditions:       step.Conditions,
+		CreatedAt:        step.CreatedAt,
+		UpdatedAt:        step.UpdatedAt,
 	}
 
 	if step.Role != nil {
@@ -857,6 +1237,7 @@ func mapTransactionApprovalToResponse(approval models.TransactionApproval) dto.T
 		ApprovedBy:        approval.ApprovedBy,
 		RejectedAt:        approval.RejectedAt,
 		RejectedBy:        approval.RejectedBy,
+		EscalatedAt:       approval.EscalatedAt,
 		Notes:             approval.Notes,
 		Metadata:          approval.Metadata,
 		CreatedAt:         approval.CreatedAt,
@@ -966,23 +1347,14 @@ func validateApproverBranch(approverUserID, transactionNumber, transactionType s
 
 // autoCompleteProcurementApproval auto-trigger complete approval
 // jika semua step sudah approved
-func autoCompleteProcurementApproval(userID, transactionNumber, transactionType string) error {
+func autoCompleteProcurementApproval(userID, transactionNumber, transactionType string, meta dto.RequestMeta) error {
 	// Hanya untuk procurement
 	if transactionType != "procurement" {
 		return nil
 	}
 
 	// Cek status semua approval step
-	var total, approved int64
-	config.DB.Model(&models.TransactionApproval{}).
-		Where("transaction_number = ? AND transaction_type = ?", transactionNumber, transactionType).
-		Count(&total)
-
-	config.DB.Model(&models.TransactionApproval{}).
-		Where("transaction_number = ? AND transaction_type = ? AND status = ?", transactionNumber, transactionType, "approved").
-		Count(&approved)
-
-	if total == 0 || approved < total {
+	if !isApprovalFlowCompleted(transactionNumber, transactionType, "") {
 		return nil // belum semua approved
 	}
 
@@ -1011,7 +1383,7 @@ func autoCompleteProcurementApproval(userID, transactionNumber, transactionType
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, models.StageProcessBudget,
-		models.ActionApprove, userID, nil, nil); err != nil {
+		models.ActionApprove, userID, nil, nil, meta); err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -1019,9 +1391,72 @@ func autoCompleteProcurementApproval(userID, transactionNumber, transactionType
 	return tx.Commit().Error
 }
 
+// isApprovalFlowCompleted - true kalau semua grup step sudah memenuhi quorum
+// (step approved / skipped). flowCode kosong = semua flow di transaksi ini
+func isApprovalFlowCompleted(transactionNumber, transactionType, flowCode string) bool {
+	query := config.DB.
+		Preload("ApprovalFlowStep").
+		Joins("JOIN approval_flows ON approval_flows.id = transaction_approvals.flow_id").
+		Where("transaction_approvals.transaction_number = ? AND transaction_approvals.transaction_type = ?",
+			transactionNumber, transactionType)
+	if flowCode != "" {
+		query = query.Where("approval_flows.flow_code = ?", flowCode)
+	}
+
+	var approvals []models.TransactionApproval
+	if err := query.Find(&approvals).Error; err != nil || len(approvals) == 0 {
+		return false
+	}
+
+	return currentApprovalGroup(groupApprovals(approvals)) == nil
+}
+
+// runApprovalCompletionHooks - trigger auto-complete per jenis transaksi
+// setelah ada step yang approved / skipped. Step auto_approve yang jadi
+// terbuka diproses dulu supaya cascade tetap jalan
+func runApprovalCompletionHooks(userID, transactionNumber, transactionType string, meta dto.RequestMeta) {
+	if _, err := processAutoApprovals(transactionNumber, transactionType); err != nil {
+		fmt.Printf("auto approve warning: %v\n", err)
+	}
+
+	if err := autoCompleteProcurementApproval(userID, transactionNumber, transactionType, meta); err != nil {
+		fmt.Printf("auto complete procurement approval warning: %v\n", err)
+	}
+
+	if err := autoCompleteMutationApproval(userID, transactionNumber, transactionType, meta); err != nil {
+		fmt.Printf("auto complete mutation approval warning: %v\n", err)
+	}
+
+	if err := autoCompleteDisposalApproval(userID, transactionNumber, transactionType, meta); err != nil {
+		fmt.Printf("auto complete disposal approval warning: %v\n", err)
+	}
+
+	if err := autoCompleteStockOpnameApproval(userID, transactionNumber, transactionType, meta); err != nil {
+		fmt.Printf("auto complete stock opname approval warning: %v\n", err)
+	}
+}
+
 // autoRejectTransaction auto-reject transaksi ketika salah satu approval step di-reject
-func autoRejectTransaction(userID, transactionNumber, transactionType, notes string) error {
-	// Hanya untuk procurement
+func autoRejectTransaction(userID, transactionNumber, transactionType, notes string, meta dto.RequestMeta) error {
+	reason := "Rejected by approver"
+	if notes != "" {
+		reason = notes
+	}
+
+	// Mutasi, disposal & stock opname punya logic reject sendiri
+	switch transactionType {
+	case TxMutationFlow:
+		_, err := RejectMutation(userID, transactionNumber, dto.RejectMutationRequest{Reason: reason}, meta)
+		return err
+	case TxDisposalFlow:
+		_, err := RejectDisposal(userID, transactionNumber, dto.RejectDisposalRequest{Reason: reason}, meta)
+		return err
+	case TxStockOpname:
+		_, err := RejectStockOpname(userID, transactionNumber, dto.RejectStockOpnameRequest{Reason: reason}, meta)
+		return err
+	}
+
+	// Sisanya hanya untuk procurement
 	if transactionType != "procurement" {
 		return nil
 	}
@@ -1050,19 +1485,19 @@ func autoRejectTransaction(userID, transactionNumber, transactionType, notes str
 		return err
 	}
 
-	reason := "Rejected by approver"
-	if notes != "" {
-		reason = notes
-	}
-
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, models.StageRejected,
-		models.ActionReject, userID, nil, &reason); err != nil {
+		models.ActionReject, userID, nil, &reason, meta); err != nil {
 		tx.Rollback()
 		return err
 	}
 
 	MarkTransactionAsExpired(transactionNumber)
 
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	notifyTransactionStage(transactionNumber, models.StageRejected, &reason)
+	return nil
 }
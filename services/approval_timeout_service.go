@@ -0,0 +1,267 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SystemActorID - actor untuk aksi yang dijalankan scheduler (bukan user)
+const SystemActorID = "system"
+
+// ============================================================================
+// APPROVAL TIMEOUT
+// Dipanggil scheduler: cari pending approval yang sudah melewati
+// approval_flow_steps.timeout_hours, lalu jalankan approval_flows.timeout_action
+//   - escalate → pindahkan approver ke escalation_user_id / escalation_role_id
+//   - skip     → step di-skip (hanya kalau can_skip = true, selain itu eskalasi)
+//   - reject   → step di-reject + autoRejectTransaction
+// Step yang sudah di-eskalasi dan timeout lagi di-reject (target eskalasi juga tidak bertindak).
+// Setiap aksi dicatat sebagai ApprovalSignature (user_id NULL) dengan alasan.
+// ============================================================================
+
+// ProcessApprovalTimeouts returns jumlah approval yang benar-benar berubah
+func ProcessApprovalTimeouts() (int, error) {
+	var approvals []models.TransactionApproval
+	if err := config.DB.
+		Preload("ApprovalFlow").
+		Preload("ApprovalFlowStep").
+		Joins("JOIN approval_flow_steps ON approval_flow_steps.id = transaction_approvals.flow_step_id").
+		Joins("JOIN approval_flows ON approval_flows.id = transaction_approvals.flow_id").
+		Where("transaction_approvals.status = ?", "pending").
+		Where("approval_flow_steps.timeout_hours > 0").
+		Where("approval_flows.timeout_action <> ?", models.TimeoutActionNone).
+		Find(&approvals).Error; err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	processed := 0
+
+	for _, approval := range approvals {
+		if approval.ApprovalFlowStep == nil || approval.ApprovalFlowStep.TimeoutHours == nil {
+			continue
+		}
+
+		activatedAt, active, err := getApprovalActivatedAt(approval)
+		if err != nil {
+			fmt.Printf("[ApprovalTimeout] failed to check approval %s: %v\n", approval.ID, err)
+			continue
+		}
+		if !active {
+			continue
+		}
+
+		deadline := activatedAt.Add(time.Duration(*approval.ApprovalFlowStep.TimeoutHours) * time.Hour)
+		if now.Before(deadline) {
+			continue
+		}
+
+		openBefore := snapshotOpenApprovals(approval.TransactionNumber, approval.TransactionType)
+
+		acted, err := handleApprovalTimeout(approval, now)
+		if err != nil {
+			fmt.Printf("[ApprovalTimeout] failed to process approval %s (%s): %v\n",
+				approval.ID, approval.TransactionNumber, err)
+			continue
+		}
+		if !acted {
+			continue // sudah diproses user di saat yang sama
+		}
+		processed++
+
+		// Eskalasi → approver baru, skip → step berikutnya terbuka
+		notifyOpenedApprovals(approval.TransactionNumber, approval.TransactionType, openBefore)
+	}
+
+	return processed, nil
+}
+
+// getApprovalActivatedAt - kapan step ini mulai bisa diproses approver:
+// setelah semua grup dengan step_order lebih kecil memenuhi quorum.
+// Sudah pernah di-eskalasi: dihitung ulang dari escalated_at.
+func getApprovalActivatedAt(approval models.TransactionApproval) (time.Time, bool, error) {
+	groups, err := loadApprovalGroups(config.DB, approval.TransactionNumber, approval.TransactionType, approval.FlowID)
+	if err != nil {
+		return approval.CreatedAt, false, err
+	}
+
+	stepOrder := approval.ApprovalFlowStep.StepOrder
+	if !isApprovalGroupOpen(groups, stepOrder) {
+		return approval.CreatedAt, false, nil // masih ada grup sebelumnya yang belum selesai
+	}
+
+	activatedAt := approvalGroupOpenedAt(groups, stepOrder, approval.CreatedAt)
+
+	if approval.EscalatedAt != nil && approval.EscalatedAt.After(activatedAt) {
+		activatedAt = *approval.EscalatedAt
+	}
+
+	return activatedAt, true, nil
+}
+
+// handleApprovalTimeout - true kalau step benar-benar berubah (eskalasi / skip / reject).
+// Tidak ada aksi yang bisa dijalankan → error, supaya tidak tercatat sebagai diproses
+func handleApprovalTimeout(approval models.TransactionApproval, now time.Time) (bool, error) {
+	flow := approval.ApprovalFlow
+	step := approval.ApprovalFlowStep
+
+	// Target eskalasi juga tidak bertindak sampai timeout → tidak ada tujuan berikutnya, reject
+	if approval.EscalatedAt != nil {
+		reason := fmt.Sprintf("Rejected automatically: escalated step %q had no action for %d hours",
+			step.StepName, *step.TimeoutHours)
+		return rejectApprovalByTimeout(approval, reason, now)
+	}
+
+	switch flow.TimeoutAction {
+	case models.TimeoutActionEscalate:
+		return escalateApproval(approval, now)
+
+	case models.TimeoutActionSkip:
+		if !step.CanSkip {
+			// Step wajib — tidak bisa di-skip, fallback ke eskalasi kalau dikonfigurasi
+			if flow.EscalationUserID != nil || flow.EscalationRoleID != nil {
+				return escalateApproval(approval, now)
+			}
+			return false, fmt.Errorf("step %q cannot be skipped and flow %s has no escalation target",
+				step.StepName, flow.FlowCode)
+		}
+		return skipApproval(approval, now)
+
+	case models.TimeoutActionReject:
+		reason := fmt.Sprintf("Rejected automatically: step %q had no action for %d hours",
+			step.StepName, *step.TimeoutHours)
+		return rejectApprovalByTimeout(approval, reason, now)
+	}
+
+	return false, nil
+}
+
+func escalateApproval(approval models.TransactionApproval, now time.Time) (bool, error) {
+	flow := approval.ApprovalFlow
+	step := approval.ApprovalFlowStep
+
+	if flow.EscalationUserID == nil && flow.EscalationRoleID == nil {
+		return false, fmt.Errorf("flow %s has no escalation target", flow.FlowCode)
+	}
+
+	// User fallback prioritas di atas role — role dikosongkan supaya
+	// ApproveTransaction tidak mensyaratkan role lama
+	updates := map[string]interface{}{
+		"escalated_at":     now,
+		"approver_user_id": flow.EscalationUserID,
+		"approver_role_id": flow.EscalationRoleID,
+	}
+	if flow.EscalationUserID != nil {
+		updates["approver_role_id"] = nil
+	}
+
+	target := ""
+	if flow.EscalationUserID != nil {
+		target = "user " + *flow.EscalationUserID
+	} else {
+		target = "role " + *flow.EscalationRoleID
+	}
+	reason := fmt.Sprintf("Escalated to %s: step %q had no action for %d hours",
+		target, step.StepName, *step.TimeoutHours)
+
+	escalated := false
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.TransactionApproval{}).
+			Where("id = ? AND status = ? AND escalated_at IS NULL", approval.ID, "pending").
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil // sudah diproses user di saat yang sama
+		}
+		escalated = true
+
+		return createSystemSignature(tx, approval, "escalated", flow.EscalationRoleID, reason, now)
+	})
+	return escalated, err
+}
+
+func skipApproval(approval models.TransactionApproval, now time.Time) (bool, error) {
+	step := approval.ApprovalFlowStep
+	reason := fmt.Sprintf("Skipped automatically: step %q had no action for %d hours",
+		step.StepName, *step.TimeoutHours)
+
+	skipped := false
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.TransactionApproval{}).
+			Where("id = ? AND status = ?", approval.ID, "pending").
+			Updates(map[string]interface{}{
+				"status": "skipped",
+				"notes":  reason,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		skipped = true
+
+		return createSystemSignature(tx, approval, "skipped", approval.ApproverRoleID, reason, now)
+	}); err != nil {
+		return false, err
+	}
+
+	if skipped {
+		notifyApprovalClosed(approval, "skipped", SystemActorID, &reason)
+		onApprovalStepCompleted(SystemActorID, approval, dto.RequestMeta{})
+	}
+	return skipped, nil
+}
+
+func rejectApprovalByTimeout(approval models.TransactionApproval, reason string, now time.Time) (bool, error) {
+	rejected := false
+	if err := config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.TransactionApproval{}).
+			Where("id = ? AND status = ?", approval.ID, "pending").
+			Updates(map[string]interface{}{
+				"status":      "rejected",
+				"rejected_at": now,
+				"notes":       reason,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		rejected = true
+
+		return createSystemSignature(tx, approval, "rejected", approval.ApproverRoleID, reason, now)
+	}); err != nil {
+		return false, err
+	}
+
+	if !rejected {
+		return false, nil
+	}
+	notifyApprovalClosed(approval, "rejected", SystemActorID, &reason)
+	return true, onApprovalStepRejected(SystemActorID, approval, reason, dto.RequestMeta{})
+}
+
+// createSystemSignature - signature tanpa user untuk aksi sistem
+func createSystemSignature(tx *gorm.DB, approval models.TransactionApproval, status string, roleID *string, reason string, now time.Time) error {
+	signature := models.ApprovalSignature{
+		TransactionNumber: approval.TransactionNumber,
+		TransactionType:   approval.TransactionType,
+		RoleID:            roleID,
+		StepRole:          approval.ApprovalFlowStep.StepRole,
+		SignedAt:          now,
+		Status:            status,
+		Notes:             &reason,
+		IsRecent:          true,
+	}
+
+	return tx.Create(&signature).Error
+}
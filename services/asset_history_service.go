@@ -4,7 +4,9 @@ import (
 	"backend-go/config"
 	"backend-go/dto"
 	"backend-go/models"
+	"encoding/json"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -20,7 +22,6 @@ func GetAssetHistory(assetNumber string) ([]dto.AssetHistoryResponse, error) {
 
 	var histories []models.AssetHistory
 	if err := config.DB.
-		Preload("ChangedByUser").
 		Where("asset_id = ?", asset.ID).
 		Order("created_at DESC").
 		Find(&histories).Error; err != nil {
@@ -39,6 +40,9 @@ func GetAllAssetHistories(filter dto.AssetHistoryFilter) ([]dto.AssetHistoryResp
 	if filter.TransactionType != nil {
 		query = query.Where("transaction_type = ?", *filter.TransactionType)
 	}
+	if filter.TransactionNumber != nil {
+		query = query.Where("transaction_number = ?", *filter.TransactionNumber)
+	}
 	if filter.StartDate != nil {
 		query = query.Where("transaction_date >= ?", *filter.StartDate)
 	}
@@ -57,7 +61,6 @@ func GetAllAssetHistories(filter dto.AssetHistoryFilter) ([]dto.AssetHistoryResp
 	var histories []models.AssetHistory
 	if err := query.
 		Preload("Asset").
-		Preload("ChangedByUser").
 		Order("created_at DESC").
 		Find(&histories).Error; err != nil {
 		return nil, 0, err
@@ -69,3 +72,90 @@ func GetAllAssetHistories(filter dto.AssetHistoryFilter) ([]dto.AssetHistoryResp
 func CreateAssetHistory(history models.AssetHistory) error {
 	return config.DB.Create(&history).Error
 }
+
+// AssetSnapshot - isi before_data / after_data di asset_histories
+type AssetSnapshot struct {
+	Asset      *models.Asset      `json:"asset"`
+	AssetValue *models.AssetValue `json:"asset_value,omitempty"`
+}
+
+// AssetHistoryEntry - data satu perubahan asset dari flow transaksi
+type AssetHistoryEntry struct {
+	AssetID           uint
+	TransactionType   string
+	TransactionID     uint
+	TransactionNumber string
+	DocumentNumber    string
+	TransactionDate   time.Time
+	Before            *AssetSnapshot
+	After             *AssetSnapshot
+	ChangedBy         string
+}
+
+// recordAssetHistory - tulis asset_histories di dalam tx yang sama dengan perubahan asset,
+// jadi kalau flow di-rollback history juga ikut rollback
+func recordAssetHistory(tx *gorm.DB, entry AssetHistoryEntry) error {
+	beforeData, err := marshalAssetSnapshot(entry.Before)
+	if err != nil {
+		return err
+	}
+	afterData, err := marshalAssetSnapshot(entry.After)
+	if err != nil {
+		return err
+	}
+
+	transactionID := entry.TransactionID
+	transactionNumber := entry.TransactionNumber
+	documentNumber := entry.DocumentNumber
+	transactionDate := entry.TransactionDate
+	changedBy := entry.ChangedBy
+
+	history := models.AssetHistory{
+		AssetID:           entry.AssetID,
+		TransactionType:   entry.TransactionType,
+		TransactionID:     &transactionID,
+		TransactionNumber: &transactionNumber,
+		DocumentNumber:    &documentNumber,
+		TransactionDate:   &transactionDate,
+		BeforeData:        beforeData,
+		AfterData:         afterData,
+		ChangedBy:         &changedBy,
+	}
+
+	return tx.Create(&history).Error
+}
+
+// loadAssetSnapshot - ambil kondisi asset + asset value aktif saat ini (pakai tx supaya
+// perubahan yang belum commit ikut terbaca)
+func loadAssetSnapshot(tx *gorm.DB, assetID uint) (*AssetSnapshot, error) {
+	var asset models.Asset
+	if err := tx.First(&asset, assetID).Error; err != nil {
+		return nil, err
+	}
+
+	snapshot := &AssetSnapshot{Asset: &asset}
+
+	var assetValue models.AssetValue
+	err := tx.Where("asset_id = ? AND is_active = ?", assetID, true).
+		Order("effective_date DESC").
+		First(&assetValue).Error
+	if err == nil {
+		snapshot.AssetValue = &assetValue
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+func marshalAssetSnapshot(snapshot *AssetSnapshot) (*string, error) {
+	if snapshot == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	result := string(data)
+	return &result, nil
+}
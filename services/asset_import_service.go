@@ -0,0 +1,861 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"backend-go/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"mime/multipart"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ============================================================
+// IMPORT REGISTER ASSET (migrasi data lama dari spreadsheet)
+// 1. Semua baris divalidasi dulu (kategori, cabang, angka, tanggal,
+//    asset number unik di file & DB) → error dikumpulkan per baris
+// 2. dry_run = true berhenti di sini, tidak ada yang disimpan
+// 3. Baris valid disimpan per batch, satu batch = satu DB transaction.
+//    Kalau satu baris gagal, seluruh batch di-rollback dan barisnya
+//    ikut dilaporkan gagal; batch berikutnya tetap jalan.
+//
+// Per baris: Asset (status default AVAILABLE) + AssetValue awal +
+// AssetAcquisition APPROVED dengan document number MIG + asset history
+// ============================================================
+
+const (
+	defaultAssetImportBatchSize = 100
+	maxAssetImportRows          = 10000
+	maxAssetImportFileSize      = 20 << 20 // 20 MB
+	importValueTolerance        = 0.01     // selisih pembulatan book_value vs acquisition - accumulated
+)
+
+// Field yang bisa di-mapping dari kolom file
+const (
+	importFieldAssetNumber             = "asset_number" // kosong = generate dari kategori
+	importFieldAssetName               = "asset_name"
+	importFieldCategoryCode            = "category_code"
+	importFieldBranchCode              = "branch_code"
+	importFieldAcquisitionDate         = "acquisition_date"
+	importFieldAcquisitionValue        = "acquisition_value"
+	importFieldBookValue               = "book_value"               // default acquisition - accumulated
+	importFieldAccumulatedDepreciation = "accumulated_depreciation" // default acquisition - book_value (0 kalau book_value kosong)
+	importFieldDescription             = "description"
+	importFieldBrand                   = "brand"
+	importFieldUnitOfMeasure           = "unit_of_measure"
+	importFieldUnitQuantity            = "unit_quantity"
+	importFieldLocation                = "location"
+	importFieldGrouping                = "grouping"
+	importFieldIONumber                = "io_number"
+	importFieldCondition               = "condition"
+	importFieldPhysicalStatus          = "physical_status"
+	importFieldAssetStatus             = "asset_status"
+	importFieldLegacyDocumentNumber    = "legacy_document_number" // dicatat di notes acquisition
+)
+
+var assetImportFields = []string{
+	importFieldAssetNumber,
+	importFieldAssetName,
+	importFieldCategoryCode,
+	importFieldBranchCode,
+	importFieldAcquisitionDate,
+	importFieldAcquisitionValue,
+	importFieldBookValue,
+	importFieldAccumulatedDepreciation,
+	importFieldDescription,
+	importFieldBrand,
+	importFieldUnitOfMeasure,
+	importFieldUnitQuantity,
+	importFieldLocation,
+	importFieldGrouping,
+	importFieldIONumber,
+	importFieldCondition,
+	importFieldPhysicalStatus,
+	importFieldAssetStatus,
+	importFieldLegacyDocumentNumber,
+}
+
+var requiredAssetImportFields = []string{
+	importFieldAssetName,
+	importFieldCategoryCode,
+	importFieldBranchCode,
+	importFieldAcquisitionDate,
+	importFieldAcquisitionValue,
+}
+
+// Status yang boleh dibawa dari data lama — PENDING_RECEIPT & DISPOSED tidak
+var importableAssetStatuses = map[string]bool{
+	models.AssetStatusAvailable:   true,
+	models.AssetStatusInactive:    true,
+	models.AssetStatusMaintenance: true,
+	models.AssetStatusRetired:     true,
+}
+
+var importableConditions = map[string]bool{
+	models.ConditionGood:   true,
+	models.ConditionFair:   true,
+	models.ConditionPoor:   true,
+	models.ConditionBroken: true,
+}
+
+var importablePhysicalStatuses = map[string]bool{
+	models.PhysicalStatusExists:   true,
+	models.PhysicalStatusMissing:  true,
+	models.PhysicalStatusDamaged:  true,
+	models.PhysicalStatusObsolete: true,
+}
+
+// assetImportRow - satu baris yang sudah lolos validasi
+type assetImportRow struct {
+	Row                     int
+	AssetNumber             string
+	AssetName               string
+	CategoryID              uint
+	CategoryCode            string
+	BranchCode              string
+	AcquisitionDate         time.Time
+	AcquisitionValue        float64
+	BookValue               float64
+	AccumulatedDepreciation float64
+	Description             *string
+	Brand                   *string
+	UnitOfMeasure           *string
+	UnitQuantity            *float64
+	Location                *string
+	Grouping                *string
+	IONumber                *string
+	Condition               *string
+	PhysicalStatus          *string
+	AssetStatus             string
+	LegacyDocumentNumber    *string
+}
+
+func ImportAssets(
+	userID string,
+	req dto.ImportAssetsRequest,
+	file multipart.File,
+	fileHeader *multipart.FileHeader,
+) (*dto.AssetImportResponse, error) {
+	startedAt := time.Now()
+
+	fileType, err := utils.SpreadsheetType(fileHeader.Filename)
+	if err != nil {
+		return nil, err
+	}
+	if fileHeader.Size > maxAssetImportFileSize {
+		return nil, fmt.Errorf("file is too large, maximum %d MB", maxAssetImportFileSize>>20)
+	}
+
+	headers, rows, sheetName, err := utils.ReadSpreadsheet(file, fileType, derefString(req.Sheet))
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("file has no data rows")
+	}
+	if len(rows) > maxAssetImportRows {
+		return nil, fmt.Errorf("file has %d rows, maximum %d rows per import", len(rows), maxAssetImportRows)
+	}
+
+	columns, mapping, err := resolveAssetImportColumns(headers, req.ColumnMapping)
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := req.BatchSize
+	if batchSize == 0 {
+		batchSize = defaultAssetImportBatchSize
+	}
+
+	validRows, rowErrors, err := validateAssetImportRows(rows, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.AssetImportResponse{
+		DryRun:        req.DryRun,
+		FileName:      fileHeader.Filename,
+		FileType:      fileType,
+		SheetName:     optionalString(sheetName),
+		ColumnMapping: mapping,
+		TotalRows:     len(rows),
+		ValidRows:     len(validRows),
+		BatchSize:     batchSize,
+		CreatedBy:     userID,
+		StartedAt:     startedAt,
+	}
+
+	if req.DryRun {
+		response.Status = models.AssetImportStatusValidated
+		response.FailedRows = len(rows) - len(validRows)
+		response.Errors = sortAssetImportErrors(rowErrors)
+		return response, nil
+	}
+
+	mappingJSON, err := json.Marshal(mapping)
+	if err != nil {
+		return nil, err
+	}
+	mappingData := string(mappingJSON)
+
+	job := models.AssetImport{
+		FileName:      fileHeader.Filename,
+		FileType:      fileType,
+		SheetName:     response.SheetName,
+		ColumnMapping: &mappingData,
+		TotalRows:     len(rows),
+		BatchSize:     batchSize,
+		Status:        models.AssetImportStatusProcessing,
+		CreatedBy:     userID,
+		StartedAt:     startedAt,
+	}
+	if err := config.DB.Create(&job).Error; err != nil {
+		return nil, err
+	}
+
+	imported := make([]dto.AssetImportRowResult, 0, len(validRows))
+	for start := 0; start < len(validRows); start += batchSize {
+		end := start + batchSize
+		if end > len(validRows) {
+			end = len(validRows)
+		}
+
+		results, failedRow, err := importAssetBatch(userID, job.ID, validRows[start:end])
+		if err != nil {
+			for _, row := range validRows[start:end] {
+				message := "batch rolled back: " + err.Error()
+				if row.Row == failedRow {
+					message = err.Error()
+				}
+				rowErrors = append(rowErrors, newAssetImportError(row.Row, row.AssetNumber, "", message))
+			}
+			continue
+		}
+		imported = append(imported, results...)
+	}
+
+	finishedAt := time.Now()
+	job.ImportedRows = len(imported)
+	job.FailedRows = len(rows) - len(imported)
+	job.FinishedAt = &finishedAt
+	switch {
+	case job.ImportedRows == 0:
+		job.Status = models.AssetImportStatusFailed
+	case job.FailedRows > 0:
+		job.Status = models.AssetImportStatusPartial
+	default:
+		job.Status = models.AssetImportStatusCompleted
+	}
+
+	rowErrors = sortAssetImportErrors(rowErrors)
+	if len(rowErrors) > 0 {
+		errorsJSON, err := json.Marshal(rowErrors)
+		if err != nil {
+			return nil, err
+		}
+		errorsData := string(errorsJSON)
+		job.Errors = &errorsData
+	}
+
+	if err := config.DB.Model(&job).Updates(map[string]interface{}{
+		"imported_rows": job.ImportedRows,
+		"failed_rows":   job.FailedRows,
+		"status":        job.Status,
+		"errors":        job.Errors,
+		"finished_at":   job.FinishedAt,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	response.ID = &job.ID
+	response.ImportedRows = job.ImportedRows
+	response.FailedRows = job.FailedRows
+	response.Status = job.Status
+	response.Errors = rowErrors
+	response.Imported = imported
+	response.FinishedAt = job.FinishedAt
+	return response, nil
+}
+
+// importAssetBatch - satu batch satu transaction, returns nomor baris yang gagal kalau error
+func importAssetBatch(userID string, importID uint, rows []assetImportRow) ([]dto.AssetImportRowResult, int, error) {
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	results := make([]dto.AssetImportRowResult, 0, len(rows))
+	for _, row := range rows {
+		result, err := importAssetRow(tx, userID, importID, row)
+		if err != nil {
+			tx.Rollback()
+			return nil, row.Row, err
+		}
+		results = append(results, *result)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, 0, err
+	}
+	return results, 0, nil
+}
+
+func importAssetRow(tx *gorm.DB, userID string, importID uint, row assetImportRow) (*dto.AssetImportRowResult, error) {
+	assetNumber := row.AssetNumber
+	if assetNumber == "" {
+		generated, err := GenerateAssetNumber(tx, row.CategoryCode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate asset number: %w", err)
+		}
+		assetNumber = generated
+	}
+
+	categoryID := row.CategoryID
+	branchCode := row.BranchCode
+	recordType := models.AssetRecordTypeMigration
+	asset := models.Asset{
+		AssetNumber:   assetNumber,
+		AssetName:     row.AssetName,
+		Description:   row.Description,
+		Brand:         row.Brand,
+		UnitOfMeasure: row.UnitOfMeasure,
+		UnitQuantity:  row.UnitQuantity,
+		Location:      row.Location,
+		Grouping:      row.Grouping,
+		CategoryID:    &categoryID,
+		BranchCode:    &branchCode,
+		IONumber:      row.IONumber,
+		RecordType:    &recordType,
+		AssetStatus:   row.AssetStatus,
+	}
+	if err := tx.Create(&asset).Error; err != nil {
+		return nil, fmt.Errorf("failed to create asset %s: %w", assetNumber, err)
+	}
+
+	assetStatus := row.AssetStatus
+	assetValue := models.AssetValue{
+		AssetID:                 asset.ID,
+		EffectiveDate:           row.AcquisitionDate,
+		BookValue:               row.BookValue,
+		AcquisitionValue:        row.AcquisitionValue,
+		AccumulatedDepreciation: row.AccumulatedDepreciation,
+		Condition:               row.Condition,
+		PhysicalStatus:          row.PhysicalStatus,
+		AssetStatus:             &assetStatus,
+		IsActive:                true,
+	}
+	if err := tx.Create(&assetValue).Error; err != nil {
+		return nil, fmt.Errorf("failed to create asset value: %w", err)
+	}
+
+	documentNumber, err := GenerateMigrationDocumentNumber(tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate document number: %w", err)
+	}
+
+	notes := fmt.Sprintf("Imported from asset import #%d row %d", importID, row.Row)
+	if row.LegacyDocumentNumber != nil {
+		notes += fmt.Sprintf(", legacy document %s", *row.LegacyDocumentNumber)
+	}
+
+	now := time.Now()
+	acquisition := models.AssetAcquisition{
+		DocumentNumber:   documentNumber,
+		AssetImportID:    &importID,
+		TransactionDate:  row.AcquisitionDate,
+		AssetID:          &asset.ID,
+		AssetNumber:      assetNumber,
+		AssetName:        row.AssetName,
+		AcquisitionValue: row.AcquisitionValue,
+		CategoryID:       &categoryID,
+		BranchCode:       branchCode,
+		Location:         derefString(row.Location),
+		IONumber:         derefString(row.IONumber),
+		Notes:            notes,
+		Status:           models.TransactionStatusApproved,
+		CreatedBy:        userID,
+		ApprovedBy:       userID,
+		ApprovedAt:       &now,
+	}
+	if err := tx.Create(&acquisition).Error; err != nil {
+		return nil, fmt.Errorf("failed to create asset acquisition: %w", err)
+	}
+
+	// Tidak ada transaction header untuk data migrasi → history tanpa transaction_id
+	afterData, err := marshalAssetSnapshot(&AssetSnapshot{Asset: &asset, AssetValue: &assetValue})
+	if err != nil {
+		return nil, err
+	}
+	transactionDate := row.AcquisitionDate
+	history := models.AssetHistory{
+		AssetID:         asset.ID,
+		TransactionType: models.TransactionTypeAcquisition,
+		DocumentNumber:  &documentNumber,
+		TransactionDate: &transactionDate,
+		AfterData:       afterData,
+		ChangedBy:       &userID,
+	}
+	if err := tx.Create(&history).Error; err != nil {
+		return nil, fmt.Errorf("failed to record asset history: %w", err)
+	}
+
+	return &dto.AssetImportRowResult{
+		Row:            row.Row,
+		AssetID:        asset.ID,
+		AssetNumber:    assetNumber,
+		DocumentNumber: documentNumber,
+	}, nil
+}
+
+// ============================================================
+// GENERATE MIGRATION DOCUMENT NUMBER
+// Format: MIG{8 digit nomor urut global}
+// Contoh: MIG00000001
+// ============================================================
+
+func GenerateMigrationDocumentNumber(tx *gorm.DB) (string, error) {
+	var seq models.DocumentNumberSequence
+
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("sequence_type = ? AND reference_code = ?", models.SeqTypeMigration, models.SeqTypeMigration).
+		First(&seq).Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		seq = models.DocumentNumberSequence{
+			SequenceType:  models.SeqTypeMigration,
+			ReferenceCode: models.SeqTypeMigration,
+			LastSequence:  0,
+		}
+		if err := tx.Create(&seq).Error; err != nil {
+			return "", err
+		}
+	} else if err != nil {
+		return "", err
+	}
+
+	seq.LastSequence++
+	if err := tx.Model(&seq).Update("last_sequence", seq.LastSequence).Error; err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("MIG%08d", seq.LastSequence), nil
+}
+
+// ============================================================
+// COLUMN MAPPING
+// ============================================================
+
+// resolveAssetImportColumns returns field → index kolom, dan field → header untuk response
+func resolveAssetImportColumns(headers []string, rawMapping *string) (map[string]int, map[string]string, error) {
+	headerIndex := make(map[string]int, len(headers))
+	for i, header := range headers {
+		key := utils.NormalizeSpreadsheetHeader(header)
+		if key == "" {
+			continue
+		}
+		if _, exists := headerIndex[key]; !exists {
+			headerIndex[key] = i
+		}
+	}
+
+	knownFields := make(map[string]bool, len(assetImportFields))
+	for _, field := range assetImportFields {
+		knownFields[field] = true
+	}
+
+	explicit := map[string]string{}
+	if rawMapping != nil && strings.TrimSpace(*rawMapping) != "" {
+		if err := json.Unmarshal([]byte(*rawMapping), &explicit); err != nil {
+			return nil, nil, errors.New("column_mapping must be a JSON object of field to column header")
+		}
+	}
+
+	columns := map[string]int{}
+	mapping := map[string]string{}
+	for field, header := range explicit {
+		if !knownFields[field] {
+			return nil, nil, fmt.Errorf("unknown field %q in column_mapping, allowed: %s", field, strings.Join(assetImportFields, ", "))
+		}
+		index, ok := headerIndex[utils.NormalizeSpreadsheetHeader(header)]
+		if !ok {
+			return nil, nil, fmt.Errorf("column %q mapped to %s not found in file", header, field)
+		}
+		columns[field] = index
+		mapping[field] = headers[index]
+	}
+
+	// Field yang tidak di-mapping: cari header dengan nama field
+	for _, field := range assetImportFields {
+		if _, ok := columns[field]; ok {
+			continue
+		}
+		if index, ok := headerIndex[field]; ok {
+			columns[field] = index
+			mapping[field] = headers[index]
+		}
+	}
+
+	var missing []string
+	for _, field := range requiredAssetImportFields {
+		if _, ok := columns[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf("required columns not mapped: %s", strings.Join(missing, ", "))
+	}
+
+	return columns, mapping, nil
+}
+
+// ============================================================
+// VALIDASI BARIS
+// ============================================================
+
+func validateAssetImportRows(rows []utils.SpreadsheetRow, columns map[string]int) ([]assetImportRow, []dto.AssetImportRowError, error) {
+	cell := func(row utils.SpreadsheetRow, field string) string {
+		index, ok := columns[field]
+		if !ok || index >= len(row.Cells) {
+			return ""
+		}
+		return strings.TrimSpace(row.Cells[index])
+	}
+
+	// Master data diambil sekali untuk seluruh file
+	var categories []models.AssetCategory
+	if err := config.DB.Where("is_active = ? AND deleted_at IS NULL", true).Find(&categories).Error; err != nil {
+		return nil, nil, err
+	}
+	categoryByCode := make(map[string]models.AssetCategory, len(categories))
+	for _, category := range categories {
+		categoryByCode[strings.ToUpper(category.CategoryCode)] = category
+	}
+
+	var branchCodes []string
+	if err := config.DB.Model(&models.Branch{}).Pluck("branch_code", &branchCodes).Error; err != nil {
+		return nil, nil, err
+	}
+	branchByCode := make(map[string]string, len(branchCodes))
+	for _, code := range branchCodes {
+		branchByCode[strings.ToUpper(code)] = code
+	}
+
+	existingNumbers, err := findExistingAssetNumbers(rows, cell)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	today := time.Now()
+	seenNumbers := map[string]int{}
+	validRows := make([]assetImportRow, 0, len(rows))
+	var rowErrors []dto.AssetImportRowError
+
+	for _, raw := range rows {
+		var errs []dto.AssetImportRowError
+		fail := func(field, message string) {
+			errs = append(errs, newAssetImportError(raw.Number, cell(raw, importFieldAssetNumber), field, message))
+		}
+
+		row := assetImportRow{
+			Row:                  raw.Number,
+			AssetNumber:          cell(raw, importFieldAssetNumber),
+			AssetName:            cell(raw, importFieldAssetName),
+			Description:          optionalString(cell(raw, importFieldDescription)),
+			Brand:                optionalString(cell(raw, importFieldBrand)),
+			UnitOfMeasure:        optionalString(cell(raw, importFieldUnitOfMeasure)),
+			Location:             optionalString(cell(raw, importFieldLocation)),
+			Grouping:             optionalString(cell(raw, importFieldGrouping)),
+			IONumber:             optionalString(cell(raw, importFieldIONumber)),
+			LegacyDocumentNumber: optionalString(cell(raw, importFieldLegacyDocumentNumber)),
+			AssetStatus:          models.AssetStatusAvailable,
+		}
+
+		if row.AssetNumber != "" {
+			switch {
+			case len(row.AssetNumber) > 100:
+				fail(importFieldAssetNumber, "asset_number must be at most 100 characters")
+			case existingNumbers[row.AssetNumber]:
+				fail(importFieldAssetNumber, fmt.Sprintf("asset_number %s already exists", row.AssetNumber))
+			case seenNumbers[row.AssetNumber] > 0:
+				fail(importFieldAssetNumber, fmt.Sprintf("asset_number %s is duplicated in row %d", row.AssetNumber, seenNumbers[row.AssetNumber]))
+			default:
+				seenNumbers[row.AssetNumber] = raw.Number
+			}
+		}
+
+		switch {
+		case row.AssetName == "":
+			fail(importFieldAssetName, "asset_name is required")
+		case len(row.AssetName) > 255:
+			fail(importFieldAssetName, "asset_name must be at most 255 characters")
+		}
+
+		if code := cell(raw, importFieldCategoryCode); code == "" {
+			fail(importFieldCategoryCode, "category_code is required")
+		} else if category, ok := categoryByCode[strings.ToUpper(code)]; !ok {
+			fail(importFieldCategoryCode, fmt.Sprintf("category %s not found or inactive", code))
+		} else {
+			row.CategoryID = category.ID
+			row.CategoryCode = category.CategoryCode
+		}
+
+		if code := cell(raw, importFieldBranchCode); code == "" {
+			fail(importFieldBranchCode, "branch_code is required")
+		} else if branchCode, ok := branchByCode[strings.ToUpper(code)]; !ok {
+			fail(importFieldBranchCode, fmt.Sprintf("branch not found: %s", code))
+		} else {
+			row.BranchCode = branchCode
+		}
+
+		if value := cell(raw, importFieldAcquisitionDate); value == "" {
+			fail(importFieldAcquisitionDate, "acquisition_date is required")
+		} else if date, err := utils.ParseSpreadsheetDate(value); err != nil {
+			fail(importFieldAcquisitionDate, err.Error())
+		} else if date.After(today) {
+			fail(importFieldAcquisitionDate, "acquisition_date cannot be in the future")
+		} else {
+			row.AcquisitionDate = date
+		}
+
+		valuesOK := true
+		parseAmount := func(field string, required bool) (float64, bool) {
+			value := cell(raw, field)
+			if value == "" {
+				if required {
+					fail(field, field+" is required")
+					valuesOK = false
+				}
+				return 0, false
+			}
+			amount, err := utils.ParseSpreadsheetNumber(value)
+			if err != nil {
+				fail(field, err.Error())
+				valuesOK = false
+				return 0, false
+			}
+			if amount < 0 {
+				fail(field, field+" cannot be negative")
+				valuesOK = false
+				return 0, false
+			}
+			return amount, true
+		}
+
+		row.AcquisitionValue, _ = parseAmount(importFieldAcquisitionValue, true)
+		accumulated, hasAccumulated := parseAmount(importFieldAccumulatedDepreciation, false)
+		bookValue, hasBookValue := parseAmount(importFieldBookValue, false)
+		if valuesOK {
+			row.AccumulatedDepreciation = accumulated
+			if row.AccumulatedDepreciation > row.AcquisitionValue {
+				fail(importFieldAccumulatedDepreciation, "accumulated_depreciation cannot exceed acquisition_value")
+			}
+			switch {
+			case hasBookValue && bookValue > row.AcquisitionValue:
+				fail(importFieldBookValue, "book_value cannot exceed acquisition_value")
+			case hasBookValue && hasAccumulated:
+				// Ketiga nilai diisi → harus konsisten: book_value = acquisition_value - accumulated_depreciation
+				if math.Abs(row.AcquisitionValue-row.AccumulatedDepreciation-bookValue) > importValueTolerance {
+					fail(importFieldBookValue, fmt.Sprintf("book_value %.2f does not match acquisition_value - accumulated_depreciation (%.2f)",
+						bookValue, row.AcquisitionValue-row.AccumulatedDepreciation))
+				}
+				row.BookValue = bookValue
+			case hasBookValue:
+				row.BookValue = bookValue
+				row.AccumulatedDepreciation = row.AcquisitionValue - bookValue
+			default:
+				row.BookValue = row.AcquisitionValue - row.AccumulatedDepreciation
+			}
+		}
+
+		if value := cell(raw, importFieldUnitQuantity); value != "" {
+			quantity, err := utils.ParseSpreadsheetNumber(value)
+			if err != nil {
+				fail(importFieldUnitQuantity, err.Error())
+			} else {
+				row.UnitQuantity = &quantity
+			}
+		}
+
+		if value := strings.ToUpper(cell(raw, importFieldAssetStatus)); value != "" {
+			if !importableAssetStatuses[value] {
+				fail(importFieldAssetStatus, fmt.Sprintf("asset_status %s cannot be imported", value))
+			} else {
+				row.AssetStatus = value
+			}
+		}
+		if value := strings.ToUpper(cell(raw, importFieldCondition)); value != "" {
+			if !importableConditions[value] {
+				fail(importFieldCondition, fmt.Sprintf("invalid condition %s", value))
+			} else {
+				row.Condition = &value
+			}
+		}
+		if value := strings.ToUpper(cell(raw, importFieldPhysicalStatus)); value != "" {
+			if !importablePhysicalStatuses[value] {
+				fail(importFieldPhysicalStatus, fmt.Sprintf("invalid physical_status %s", value))
+			} else {
+				row.PhysicalStatus = &value
+			}
+		}
+
+		if len(errs) > 0 {
+			rowErrors = append(rowErrors, errs...)
+			continue
+		}
+		validRows = append(validRows, row)
+	}
+
+	return validRows, rowErrors, nil
+}
+
+// findExistingAssetNumbers - cek asset number yang diisi di file terhadap DB, per 500
+func findExistingAssetNumbers(rows []utils.SpreadsheetRow, cell func(utils.SpreadsheetRow, string) string) (map[string]bool, error) {
+	numbers := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if number := cell(row, importFieldAssetNumber); number != "" {
+			numbers = append(numbers, number)
+		}
+	}
+
+	existing := map[string]bool{}
+	for start := 0; start < len(numbers); start += 500 {
+		end := start + 500
+		if end > len(numbers) {
+			end = len(numbers)
+		}
+
+		var found []string
+		if err := config.DB.Model(&models.Asset{}).
+			Where("asset_number IN ?", numbers[start:end]).
+			Pluck("asset_number", &found).Error; err != nil {
+			return nil, err
+		}
+		for _, number := range found {
+			existing[number] = true
+		}
+	}
+	return existing, nil
+}
+
+func newAssetImportError(row int, assetNumber, field, message string) dto.AssetImportRowError {
+	return dto.AssetImportRowError{
+		Row:         row,
+		AssetNumber: optionalString(assetNumber),
+		Field:       optionalString(field),
+		Message:     message,
+	}
+}
+
+func sortAssetImportErrors(rowErrors []dto.AssetImportRowError) []dto.AssetImportRowError {
+	if rowErrors == nil {
+		return []dto.AssetImportRowError{}
+	}
+	sort.SliceStable(rowErrors, func(i, j int) bool { return rowErrors[i].Row < rowErrors[j].Row })
+	return rowErrors
+}
+
+// ============================================================
+// RIWAYAT IMPORT
+// ============================================================
+
+func GetAssetImports(filter dto.AssetImportFilter) ([]dto.AssetImportResponse, int64, error) {
+	query := config.DB.Model(&models.AssetImport{})
+	if filter.Status != nil {
+		query = query.Where("status = ?", *filter.Status)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var jobs []models.AssetImport
+	if err := query.
+		Order("created_at DESC").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Find(&jobs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]dto.AssetImportResponse, 0, len(jobs))
+	for _, job := range jobs {
+		response := mapAssetImportToResponse(job)
+		response.Errors = nil // detail error hanya di GET /:id
+		responses = append(responses, response)
+	}
+	return responses, total, nil
+}
+
+func GetAssetImportByID(id uint) (*dto.AssetImportResponse, error) {
+	var job models.AssetImport
+	if err := config.DB.First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("asset import not found")
+		}
+		return nil, err
+	}
+
+	response := mapAssetImportToResponse(job)
+
+	var acquisitions []models.AssetAcquisition
+	if err := config.DB.
+		Where("asset_import_id = ?", job.ID).
+		Order("id ASC").
+		Find(&acquisitions).Error; err != nil {
+		return nil, err
+	}
+	response.Imported = make([]dto.AssetImportRowResult, 0, len(acquisitions))
+	for _, acquisition := range acquisitions {
+		var assetID uint
+		if acquisition.AssetID != nil {
+			assetID = *acquisition.AssetID
+		}
+		response.Imported = append(response.Imported, dto.AssetImportRowResult{
+			AssetID:        assetID,
+			AssetNumber:    acquisition.AssetNumber,
+			DocumentNumber: acquisition.DocumentNumber,
+		})
+	}
+
+	return &response, nil
+}
+
+func mapAssetImportToResponse(job models.AssetImport) dto.AssetImportResponse {
+	id := job.ID
+	response := dto.AssetImportResponse{
+		ID:           &id,
+		FileName:     job.FileName,
+		FileType:     job.FileType,
+		SheetName:    job.SheetName,
+		TotalRows:    job.TotalRows,
+		ImportedRows: job.ImportedRows,
+		FailedRows:   job.FailedRows,
+		ValidRows:    job.TotalRows - job.FailedRows,
+		BatchSize:    job.BatchSize,
+		Status:       job.Status,
+		Errors:       []dto.AssetImportRowError{},
+		CreatedBy:    job.CreatedBy,
+		StartedAt:    job.StartedAt,
+		FinishedAt:   job.FinishedAt,
+	}
+
+	if job.ColumnMapping != nil {
+		json.Unmarshal([]byte(*job.ColumnMapping), &response.ColumnMapping)
+	}
+	if job.Errors != nil {
+		json.Unmarshal([]byte(*job.Errors), &response.Errors)
+	}
+
+	return response
+}
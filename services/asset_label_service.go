@@ -0,0 +1,146 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"backend-go/utils"
+	"errors"
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================
+// ASSET LABEL
+// PNG per asset, PDF sheet untuk banyak asset, dan lookup hasil scan.
+// Payload QR lihat utils.AssetLabelPayload.
+// ============================================================
+
+const maxLabelsPerSheet = 1000
+
+func GetAssetLabelPNG(assetNumber string, format string) ([]byte, error) {
+	format, err := utils.NormalizeLabelFormat(format)
+	if err != nil {
+		return nil, err
+	}
+
+	var count int64
+	if err := config.DB.Model(&models.Asset{}).
+		Where("asset_number = ? AND deleted_at IS NULL", assetNumber).
+		Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, errors.New("asset not found")
+	}
+
+	return utils.RenderAssetLabelPNG(format, assetNumber)
+}
+
+func GetAssetLabelSheetPDF(req dto.AssetLabelSheetRequest) ([]byte, error) {
+	format, err := utils.NormalizeLabelFormat(req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.AssetNumbers) == 0 && (req.TransactionNumber == nil || *req.TransactionNumber == "") {
+		return nil, errors.New("asset_numbers or transaction_number is required")
+	}
+
+	query := config.DB.Preload("Category").Where("deleted_at IS NULL")
+
+	var assetIDs []uint
+	if req.TransactionNumber != nil && *req.TransactionNumber != "" {
+		assetIDs, err = procurementAssetIDs(*req.TransactionNumber)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case len(req.AssetNumbers) > 0 && len(assetIDs) > 0:
+		query = query.Where("asset_number IN ? OR id IN ?", req.AssetNumbers, assetIDs)
+	case len(req.AssetNumbers) > 0:
+		query = query.Where("asset_number IN ?", req.AssetNumbers)
+	default:
+		query = query.Where("id IN ?", assetIDs)
+	}
+
+	var assets []models.Asset
+	if err := query.Order("asset_number ASC").Find(&assets).Error; err != nil {
+		return nil, err
+	}
+
+	if len(req.AssetNumbers) > 0 {
+		found := make(map[string]bool, len(assets))
+		for _, asset := range assets {
+			found[asset.AssetNumber] = true
+		}
+		var missing []string
+		for _, number := range req.AssetNumbers {
+			if !found[number] {
+				missing = append(missing, number)
+			}
+		}
+		if len(missing) > 0 {
+			sort.Strings(missing)
+			return nil, fmt.Errorf("asset not found: %v", missing)
+		}
+	}
+
+	if len(assets) > maxLabelsPerSheet {
+		return nil, fmt.Errorf("too many labels (%d), maximum is %d per sheet", len(assets), maxLabelsPerSheet)
+	}
+
+	labels := make([]utils.AssetLabel, 0, len(assets))
+	for _, asset := range assets {
+		label := utils.AssetLabel{
+			AssetNumber: asset.AssetNumber,
+			AssetName:   asset.AssetName,
+			BranchCode:  derefString(asset.BranchCode),
+		}
+		if asset.Category != nil {
+			label.CategoryName = asset.Category.CategoryName
+		}
+		labels = append(labels, label)
+	}
+
+	return utils.RenderAssetLabelSheetPDF(format, labels)
+}
+
+// procurementAssetIDs - asset yang dibuat ExecuteProcurementAsset (masuk stage GR)
+func procurementAssetIDs(transactionNumber string) ([]uint, error) {
+	var transaction models.Transaction
+	if err := config.DB.
+		Where("transaction_number = ? AND transaction_type = ?", transactionNumber, TxProcurement).
+		First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("procurement transaction not found")
+		}
+		return nil, err
+	}
+
+	var assetIDs []uint
+	if err := config.DB.Model(&models.AssetAcquisition{}).
+		Where("transaction_id = ? AND asset_id IS NOT NULL", transaction.ID).
+		Pluck("asset_id", &assetIDs).Error; err != nil {
+		return nil, err
+	}
+	if len(assetIDs) == 0 {
+		return nil, fmt.Errorf("procurement %s has no assets yet, assets are created when it reaches %s stage",
+			transactionNumber, models.StageGR)
+	}
+
+	return assetIDs, nil
+}
+
+// LookupAssetByLabel - payload hasil scan QR / Code128 → asset
+func LookupAssetByLabel(payload string) (*dto.AssetResponse, error) {
+	assetNumber := utils.ParseAssetLabelPayload(payload)
+	if assetNumber == "" {
+		return nil, errors.New("invalid label payload")
+	}
+	return GetAssetByNumber(assetNumber)
+}
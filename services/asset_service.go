@@ -10,21 +10,7 @@ import (
 )
 
 func GetAllAssets(filter dto.AssetListFilter) ([]dto.AssetResponse, int64, error) {
-	query := config.DB.Model(&models.Asset{}).Where("deleted_at IS NULL")
-
-	if filter.BranchCode != nil {
-		query = query.Where("branch_code = ?", *filter.BranchCode)
-	}
-	if filter.CategoryID != nil {
-		query = query.Where("category_id = ?", *filter.CategoryID)
-	}
-	if filter.AssetStatus != nil {
-		query = query.Where("asset_status = ?", *filter.AssetStatus)
-	}
-	if filter.Search != nil && *filter.Search != "" {
-		search := "%" + *filter.Search + "%"
-		query = query.Where("asset_number ILIKE ? OR asset_name ILIKE ?", search, search)
-	}
+	query := applyAssetListFilter(config.DB.Model(&models.Asset{}), filter)
 
 	var total int64
 	if err := query.Count(&total).Error; err != nil {
@@ -60,6 +46,29 @@ func GetAllAssets(filter dto.AssetListFilter) ([]dto.AssetResponse, int64, error
 	return responses, total, nil
 }
 
+// applyAssetListFilter - dipakai list & export supaya hasilnya sama.
+// Kolom di-qualify karena query export join ke branches / asset_categories
+func applyAssetListFilter(query *gorm.DB, filter dto.AssetListFilter) *gorm.DB {
+	query = query.Where("assets.deleted_at IS NULL")
+
+	if filter.BranchCode != nil {
+		query = query.Where("assets.branch_code = ?", *filter.BranchCode)
+	}
+	if filter.CategoryID != nil {
+		query = query.Where("assets.category_id = ?", *filter.CategoryID)
+	}
+	if filter.AssetStatus != nil {
+		query = query.Where("assets.asset_status = ?", *filter.AssetStatus)
+	}
+	if filter.Search != nil && *filter.Search != "" {
+		// MySQL tidak punya ILIKE, collation default sudah case-insensitive
+		search := "%" + *filter.Search + "%"
+		query = query.Where("(assets.asset_number LIKE ? OR assets.asset_name LIKE ?)", search, search)
+	}
+
+	return query
+}
+
 func GetAssetByNumber(assetNumber string) (*dto.AssetResponse, error) {
 	var asset models.Asset
 
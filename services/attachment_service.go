@@ -163,33 +163,9 @@ func UploadAttachment(
 		return nil, errors.New("attachment already uploaded for this config, please wait for review or re-upload after rejection")
 	}
 
-	// Buat direktori kalau belum ada
-	// Struktur: /home/dev/.../documents/{transaction_type}/{transaction_number}/{stage}/
-	dirPath := filepath.Join(
-		AttachmentStoragePath,
-		transactionType,
-		sanitizePathSegment(transactionNumber),
-		stage,
-	)
-	if err := os.MkdirAll(dirPath, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create directory: %w", err)
-	}
-
-	// Nama file: {timestamp}_{original_filename}
-	timestamp := time.Now().Format("20060102150405")
-	fileName := fmt.Sprintf("%s_%s", timestamp, fileHeader.Filename)
-	filePath := filepath.Join(dirPath, fileName)
-
-	// Simpan file
-	dst, err := os.Create(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create file: %w", err)
-	}
-	defer dst.Close()
-
-	fileSize, err := io.Copy(dst, file)
+	filePath, fileSize, err := storeAttachmentFile(transactionType, transactionNumber, stage, file, fileHeader.Filename)
 	if err != nil {
-		return nil, fmt.Errorf("failed to save file: %w", err)
+		return nil, err
 	}
 
 	// Detect mime type dari extension
@@ -261,6 +237,11 @@ func ReviewAttachment(reviewerID string, attachmentID uint, req dto.ReviewAttach
 
 	// Reload
 	config.DB.Preload("AttachmentConfig").First(&attachment, attachmentID)
+
+	if attachment.Status == models.AttachmentStatusRejected && attachment.UploadedBy != reviewerID {
+		notifyAttachmentRejected(attachment)
+	}
+
 	response := mapTransactionAttachmentToResponse(attachment)
 	return &response, nil
 }
@@ -411,6 +392,39 @@ func getRequiredConfigs(transactionType, stage, branchCode string) ([]models.Att
 	return result, nil
 }
 
+// storeAttachmentFile simpan file ke storage attachment, returns path + ukuran file
+// Struktur: {AttachmentStoragePath}/{transaction_type}/{transaction_number}/{stage}/{timestamp}_{original_filename}
+func storeAttachmentFile(transactionType, transactionNumber, stage string, file io.Reader, originalName string) (string, int64, error) {
+	// Buat direktori kalau belum ada
+	dirPath := filepath.Join(
+		AttachmentStoragePath,
+		transactionType,
+		sanitizePathSegment(transactionNumber),
+		stage,
+	)
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		return "", 0, fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	timestamp := time.Now().Format("20060102150405")
+	fileName := fmt.Sprintf("%s_%s", timestamp, sanitizePathSegment(filepath.Base(originalName)))
+	filePath := filepath.Join(dirPath, fileName)
+
+	dst, err := os.Create(filePath)
+	if err != nil {
+		return "", 0, fmt.Errorf("failed to create file: %w", err)
+	}
+	defer dst.Close()
+
+	fileSize, err := io.Copy(dst, file)
+	if err != nil {
+		os.Remove(filePath)
+		return "", 0, fmt.Errorf("failed to save file: %w", err)
+	}
+
+	return filePath, fileSize, nil
+}
+
 // sanitizePathSegment ganti karakter tidak aman jadi underscore
 // Handles: / \ space dan karakter spesial lainnya
 func sanitizePathSegment(s string) string {
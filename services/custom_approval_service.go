@@ -45,21 +45,34 @@ func CreateCustomApproval(userID string, req dto.CreateCustomApprovalRequest) (*
 		return nil, errors.New("you already have a custom approval based on this flow. Please update it instead")
 	}
 
+	// Validate step conditions sebelum ada data yang ditulis
+	for _, stepReq := range req.Steps {
+		if _, err := ParseApprovalConditions(stepReq.Conditions); err != nil {
+			return nil, fmt.Errorf("step %s: %w", stepReq.StepName, err)
+		}
+		if _, err := ParseAutoApproveRules(stepReq.AutoApproveRules); err != nil {
+			return nil, fmt.Errorf("step %s: %w", stepReq.StepName, err)
+		}
+	}
+
 	// 4. Create custom approval flow
 	customStatus := "pending_verification"
 	customFlow := models.ApprovalFlow{
-		FlowCode:       fmt.Sprintf("CUSTOM_%s_%s", userID[:8], baseFlow.FlowCode),
-		FlowName:       req.CustomFlowName,
-		ApprovalWay:    baseFlow.ApprovalWay, // Copy from base
-		AssignmentType: "user_specific",      // Always user_specific for custom
-		AssignedUserID: &userID,              // Assign to creator
-		IsCustomizable: false,                // Custom flows cannot be customized further
-		IsCustom:       true,                 // Mark as custom
-		CreatedBy:      &userID,
-		BaseFlowID:     &req.BaseFlowID,
-		CustomStatus:   &customStatus,
-		Description:    fmt.Sprintf("Custom approval created by user based on %s", baseFlow.FlowName),
-		IsActive:       false, // Will be active after verification
+		FlowCode:         fmt.Sprintf("CUSTOM_%s_%s", userID[:8], baseFlow.FlowCode),
+		FlowName:         req.CustomFlowName,
+		ApprovalWay:      baseFlow.ApprovalWay, // Copy from base
+		TimeoutAction:    baseFlow.TimeoutAction,
+		EscalationRoleID: baseFlow.EscalationRoleID,
+		EscalationUserID: baseFlow.EscalationUserID,
+		AssignmentType:   "user_specific", // Always user_specific for custom
+		AssignedUserID:   &userID,         // Assign to creator
+		IsCustomizable:   false,           // Custom flows cannot be customized further
+		IsCustom:         true,            // Mark as custom
+		CreatedBy:        &userID,
+		BaseFlowID:       &req.BaseFlowID,
+		CustomStatus:     &customStatus,
+		Description:      fmt.Sprintf("Custom approval created by user based on %s", baseFlow.FlowName),
+		IsActive:         false, // Will be active after verification
 	}
 
 	if err := config.DB.Create(&customFlow).Error; err != nil {
@@ -82,24 +95,31 @@ func CreateCustomApproval(userID string, req dto.CreateCustomApprovalRequest) (*
 		if stepReq.ApprovalWay != "" {
 			stepApprovalWay = stepReq.ApprovalWay
 		}
+		stepQuorumType := models.QuorumAll
+		if stepReq.QuorumType != "" {
+			stepQuorumType = stepReq.QuorumType
+		}
 
 		step := models.ApprovalFlowStep{
-			FlowID:       customFlow.ID,
-			StepOrder:    stepReq.StepOrder,
-			StepName:     stepReq.StepName,
-			StepRole:     stepReq.StepRole,
-			RoleID:       stepReq.RoleID,
-			BranchID:     stepReq.BranchID,
-			Structure:    stepReq.Structure,
-			IsRequired:   stepReq.IsRequired,
-			CanSkip:      stepReq.CanSkip,
-			IsVisible:    stepReq.IsVisible,
-			Type:         stepType,
-			Category:     stepCategory,
-			ApprovalWay:  stepApprovalWay,
-			AutoApprove:  stepReq.AutoApprove,
-			TimeoutHours: stepReq.TimeoutHours,
-			Conditions:   stepReq.Conditions,
+			FlowID:           customFlow.ID,
+			StepOrder:        stepReq.StepOrder,
+			QuorumType:       stepQuorumType,
+			QuorumCount:      stepReq.QuorumCount,
+			StepName:         stepReq.StepName,
+			StepRole:         stepReq.StepRole,
+			RoleID:           stepReq.RoleID,
+			BranchID:         stepReq.BranchID,
+			Structure:        stepReq.Structure,
+			IsRequired:       stepReq.IsRequired,
+			CanSkip:          stepReq.CanSkip,
+			IsVisible:        stepReq.IsVisible,
+			Type:             stepType,
+			Category:         stepCategory,
+			ApprovalWay:      stepApprovalWay,
+			AutoApprove:      stepReq.AutoApprove,
+			AutoApproveRules: stepReq.AutoApproveRules,
+			TimeoutHours:     stepReq.TimeoutHours,
+			Conditions:       stepReq.Conditions,
 		}
 
 		if err := config.DB.Create(&step).Error; err != nil {
@@ -167,6 +187,16 @@ func UpdateCustomApproval(userID, flowID string, req dto.UpdateCustomApprovalReq
 		}
 	}
 
+	// Validate step conditions sebelum ada data yang ditulis
+	for _, stepReq := range req.Steps {
+		if _, err := ParseApprovalConditions(stepReq.Conditions); err != nil {
+			return nil, fmt.Errorf("step %s: %w", stepReq.StepName, err)
+		}
+		if _, err := ParseAutoApproveRules(stepReq.AutoApproveRules); err != nil {
+			return nil, fmt.Errorf("step %s: %w", stepReq.StepName, err)
+		}
+	}
+
 	// 4. Update flow
 	pendingStatus := "pending_verification"
 	updates := map[string]interface{}{
@@ -200,24 +230,31 @@ func UpdateCustomApproval(userID, flowID string, req dto.UpdateCustomApprovalReq
 		if stepReq.ApprovalWay != nil && *stepReq.ApprovalWay != "" {
 			stepApprovalWay = *stepReq.ApprovalWay
 		}
+		stepQuorumType := models.QuorumAll
+		if stepReq.QuorumType != nil && *stepReq.QuorumType != "" {
+			stepQuorumType = *stepReq.QuorumType
+		}
 
 		step := models.ApprovalFlowStep{
-			FlowID:       flowID,
-			StepOrder:    *stepReq.StepOrder,
-			StepName:     stepReq.StepName,
-			StepRole:     stepReq.StepRole,
-			RoleID:       stepReq.RoleID,
-			BranchID:     stepReq.BranchID,
-			Structure:    stepReq.Structure,
-			IsRequired:   *stepReq.IsRequired,
-			CanSkip:      *stepReq.CanSkip,
-			IsVisible:    *stepReq.IsVisible,
-			Type:         stepType,
-			Category:     stepCategory,
-			ApprovalWay:  stepApprovalWay,
-			AutoApprove:  *stepReq.AutoApprove,
-			TimeoutHours: stepReq.TimeoutHours,
-			Conditions:   stepReq.Conditions,
+			FlowID:           flowID,
+			StepOrder:        *stepReq.StepOrder,
+			QuorumType:       stepQuorumType,
+			QuorumCount:      stepReq.QuorumCount,
+			StepName:         stepReq.StepName,
+			StepRole:         stepReq.StepRole,
+			RoleID:           stepReq.RoleID,
+			BranchID:         stepReq.BranchID,
+			Structure:        stepReq.Structure,
+			IsRequired:       *stepReq.IsRequired,
+			CanSkip:          *stepReq.CanSkip,
+			IsVisible:        *stepReq.IsVisible,
+			Type:             stepType,
+			Category:         stepCategory,
+			ApprovalWay:      stepApprovalWay,
+			AutoApprove:      *stepReq.AutoApprove,
+			AutoApproveRules: stepReq.AutoApproveRules,
+			TimeoutHours:     stepReq.TimeoutHours,
+			Conditions:       stepReq.Conditions,
 		}
 
 		if err := config.DB.Create(&step).Error; err != nil {
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// DEPRECIATION BOOKS
+// Buku komersial menggerakkan chain AssetValue, buku lain (fiskal) hanya
+// menyimpan baris kalkulasi sendiri per asset + periode + buku
+// ============================================================================
+
+// DefaultDeferredTaxRate - tarif PPh Badan (persen) untuk hitung pajak tangguhan
+const DefaultDeferredTaxRate = 22.0
+
+func GetDepreciationBooks() ([]dto.DepreciationBookResponse, error) {
+	var books []models.DepreciationBook
+	if err := config.DB.Order("id ASC").Find(&books).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.DepreciationBookResponse, len(books))
+	for i, book := range books {
+		result[i] = dto.DepreciationBookResponse{
+			ID:               book.ID,
+			BookCode:         book.BookCode,
+			BookName:         book.BookName,
+			Description:      book.Description,
+			DrivesAssetValue: book.DrivesAssetValue,
+			IsActive:         book.IsActive,
+		}
+	}
+	return result, nil
+}
+
+// GetFiscalTaxGroups - kelompok harta fiskal bawaan (Pasal 11 UU PPh)
+func GetFiscalTaxGroups() []models.FiscalTaxGroup {
+	return models.FiscalTaxGroups
+}
+
+func getDepreciationBook(bookCode string) (*models.DepreciationBook, error) {
+	var book models.DepreciationBook
+	if err := config.DB.Where("book_code = ?", bookCode).First(&book).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("depreciation book %s not found", bookCode)
+		}
+		return nil, err
+	}
+	if !book.IsActive {
+		return nil, fmt.Errorf("depreciation book %s is inactive", bookCode)
+	}
+	return &book, nil
+}
+
+// depreciationBooksFor - satu buku kalau bookCode diisi, kalau kosong semua buku aktif
+// (buku yang menggerakkan AssetValue selalu diproses pertama)
+func depreciationBooksFor(bookCode string) ([]models.DepreciationBook, error) {
+	if bookCode != "" {
+		book, err := getDepreciationBook(bookCode)
+		if err != nil {
+			return nil, err
+		}
+		return []models.DepreciationBook{*book}, nil
+	}
+
+	var books []models.DepreciationBook
+	if err := config.DB.
+		Where("is_active = ?", true).
+		Order("drives_asset_value DESC, id ASC").
+		Find(&books).Error; err != nil {
+		return nil, err
+	}
+	if len(books) == 0 {
+		return nil, errors.New("no active depreciation book")
+	}
+	return books, nil
+}
+
+// resolveDepreciationSetting - setting aktif untuk buku ini yang berlaku di periode
+// [periodStart, periodEnd]: asset-level dulu, fallback ke kategori.
+// nil kalau asset tidak punya setting di buku ini
+func resolveDepreciationSetting(db *gorm.DB, asset models.Asset, bookCode string, periodStart, periodEnd time.Time) (*models.DepreciationSetting, error) {
+	find := func(settingType string, referenceID uint) (*models.DepreciationSetting, error) {
+		var setting models.DepreciationSetting
+		err := db.Where("book_code = ? AND setting_type = ? AND reference_id = ? AND is_active = ?",
+			bookCode, settingType, referenceID, true).
+			Where("start_date <= ? AND (end_date IS NULL OR end_date >= ?)", periodEnd, periodStart).
+			Order("start_date DESC, id DESC").
+			First(&setting).Error
+		if err == nil {
+			return &setting, nil
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	setting, err := find(models.SettingTypeAsset, asset.ID)
+	if err != nil || setting != nil {
+		return setting, err
+	}
+
+	if asset.CategoryID == nil {
+		return nil, nil
+	}
+	return find(models.SettingTypeCategory, *asset.CategoryID)
+}
+
+// bookPositionBefore - book value & akumulasi asset di buku ini sebelum periode ini.
+// Belum pernah dihitung di buku ini = saldo awal: buku lain ikut posisi COMMERCIAL sebelum periode
+// (asset lama / hasil import tidak mulai lagi dari nilai perolehan), COMMERCIAL dari AssetValue pertama
+func bookPositionBefore(db *gorm.DB, assetID uint, bookCode, period string, acquisitionValue float64) (float64, float64, error) {
+	var last models.MonthlyDepreciationCalculation
+	err := db.Where("asset_id = ? AND book_code = ? AND period < ?", assetID, bookCode, period).
+		Order("period DESC").
+		First(&last).Error
+	if err == nil {
+		return last.EndingBookValue, last.EndingAccumulatedDepreciation, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, 0, err
+	}
+
+	if bookCode != models.DepreciationBookCommercial {
+		return bookPositionBefore(db, assetID, models.DepreciationBookCommercial, period, acquisitionValue)
+	}
+
+	var opening models.AssetValue
+	err = db.Where("asset_id = ?", assetID).
+		Order("effective_date ASC, id ASC").
+		First(&opening).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return acquisitionValue, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return opening.BookValue, opening.AccumulatedDepreciation, nil
+}
+
+// yearStartBookValue - book value buku ini di awal tahun pajak (Januari) periode yang dihitung.
+// Asset yang belum pernah dihitung sebelum tahun ini mulai dari saldo awal (bookPositionBefore)
+func yearStartBookValue(db *gorm.DB, assetID uint, bookCode string, periodStart time.Time, acquisitionValue float64) (float64, error) {
+	yearStart := time.Date(periodStart.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	bookValue, _, err := bookPositionBefore(db, assetID, bookCode, yearStart.Format("2006-01"), acquisitionValue)
+	return bookValue, err
+}
+
+// applyFiscalTaxGroup - isi masa manfaat & tarif dari kelompok harta fiskal.
+// Tarif saldo menurun disimpan tahunan; engine membebankan tarif × nilai buku awal tahun pajak / 12 per bulan
+func applyFiscalTaxGroup(bookCode string, taxGroup *string, method string, lifeMonths *int, rate **float64, residualType *string) error {
+	if taxGroup == nil {
+		return nil
+	}
+	if bookCode != models.DepreciationBookFiscal {
+		return errors.New("tax_group is only allowed for FISCAL book")
+	}
+
+	group, ok := models.FindFiscalTaxGroup(*taxGroup)
+	if !ok {
+		return fmt.Errorf("unknown tax_group %s", *taxGroup)
+	}
+	if residualType != nil {
+		return errors.New("fiscal tax group depreciation has no residual value")
+	}
+
+	switch method {
+	case models.CalculationMethodStraightLine:
+		*rate = nil
+	case models.CalculationMethodDecliningBalance:
+		if group.DecliningRate == nil {
+			return fmt.Errorf("%s can only use STRAIGHT_LINE", group.Name)
+		}
+		annual := *group.DecliningRate
+		*rate = &annual
+	default:
+		return errors.New("tax_group only supports STRAIGHT_LINE or DECLINING_BALANCE")
+	}
+
+	*lifeMonths = group.UsefulLifeYears * 12
+	return nil
+}
+
+// ============================================================================
+// REKONSILIASI KOMERSIAL vs FISKAL
+// Beda temporer = nilai buku fiskal - nilai buku komersial
+// positif → aset pajak tangguhan, negatif → liabilitas pajak tangguhan
+// ============================================================================
+
+func GetDepreciationReconciliation(period string, taxRate float64) (*dto.DepreciationReconciliationResponse, error) {
+	var calculations []models.MonthlyDepreciationCalculation
+	if err := config.DB.
+		Preload("Asset").
+		Where("period = ? AND book_code IN ?", period,
+			[]string{models.DepreciationBookCommercial, models.DepreciationBookFiscal}).
+		Find(&calculations).Error; err != nil {
+		return nil, err
+	}
+
+	type bookPair struct {
+		commercial *models.MonthlyDepreciationCalculation
+		fiscal     *models.MonthlyDepreciationCalculation
+	}
+	pairs := map[uint]*bookPair{}
+	for i := range calculations {
+		calc := &calculations[i]
+		pair, ok := pairs[calc.AssetID]
+		if !ok {
+			pair = &bookPair{}
+			pairs[calc.AssetID] = pair
+		}
+		if calc.BookCode == models.DepreciationBookCommercial {
+			pair.commercial = calc
+		} else {
+			pair.fiscal = calc
+		}
+	}
+
+	response := &dto.DepreciationReconciliationResponse{
+		Period:  period,
+		TaxRate: taxRate,
+		Items:   make([]dto.DepreciationReconciliationItem, 0, len(pairs)),
+	}
+
+	for assetID, pair := range pairs {
+		item := dto.DepreciationReconciliationItem{AssetID: assetID}
+
+		for _, calc := range []*models.MonthlyDepreciationCalculation{pair.commercial, pair.fiscal} {
+			if calc != nil && calc.Asset != nil {
+				item.AssetNumber = calc.Asset.AssetNumber
+				item.AssetName = calc.Asset.AssetName
+			}
+		}
+
+		if pair.commercial != nil {
+			item.CommercialDepreciation = pair.commercial.DepreciationAmount
+			item.CommercialBookValue = pair.commercial.EndingBookValue
+		} else {
+			missing := models.DepreciationBookCommercial
+			item.MissingBook = &missing
+		}
+		if pair.fiscal != nil {
+			item.FiscalDepreciation = pair.fiscal.DepreciationAmount
+			item.FiscalBookValue = pair.fiscal.EndingBookValue
+		} else {
+			missing := models.DepreciationBookFiscal
+			item.MissingBook = &missing
+		}
+
+		item.FiscalCorrection = roundCurrency(item.CommercialDepreciation - item.FiscalDepreciation)
+		item.DeferredTaxType = "NONE"
+
+		// Beda temporer hanya berarti kalau kedua buku sudah dihitung
+		if item.MissingBook == nil {
+			item.TemporaryDifference = roundCurrency(item.FiscalBookValue - item.CommercialBookValue)
+			item.DeferredTax = roundCurrency(item.TemporaryDifference * taxRate / 100)
+			switch {
+			case item.TemporaryDifference > 0:
+				item.DeferredTaxType = "ASSET"
+			case item.TemporaryDifference < 0:
+				item.DeferredTaxType = "LIABILITY"
+			}
+		}
+
+		response.TotalCommercialDepreciation += item.CommercialDepreciation
+		response.TotalFiscalDepreciation += item.FiscalDepreciation
+		response.TotalFiscalCorrection += item.FiscalCorrection
+		response.TotalTemporaryDifference += item.TemporaryDifference
+		response.TotalDeferredTax += item.DeferredTax
+		response.Items = append(response.Items, item)
+	}
+
+	sort.Slice(response.Items, func(i, j int) bool {
+		return response.Items[i].AssetNumber < response.Items[j].AssetNumber
+	})
+
+	response.TotalCommercialDepreciation = roundCurrency(response.TotalCommercialDepreciation)
+	response.TotalFiscalDepreciation = roundCurrency(response.TotalFiscalDepreciation)
+	response.TotalFiscalCorrection = roundCurrency(response.TotalFiscalCorrection)
+	response.TotalTemporaryDifference = roundCurrency(response.TotalTemporaryDifference)
+	response.TotalDeferredTax = roundCurrency(response.TotalDeferredTax)
+
+	return response, nil
+}
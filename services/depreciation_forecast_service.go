@@ -0,0 +1,478 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// FORECAST DEPRESIASI
+// Proyeksi bulan per bulan dari posisi terakhir asset (AssetValue aktif /
+// kalkulasi terakhir buku) memakai engine yang sama dengan kalkulasi bulanan.
+// Tidak ada yang ditulis ke DB
+// ============================================================================
+
+const (
+	DepreciationScheduleActual   = "ACTUAL"
+	DepreciationScheduleForecast = "FORECAST"
+)
+
+// maxForecastMonths - batas aman proyeksi (50 tahun)
+const maxForecastMonths = 600
+
+// depreciationForecastPlan - titik awal proyeksi satu asset di satu buku
+type depreciationForecastPlan struct {
+	Setting                 models.DepreciationSetting
+	AssetStart              time.Time
+	FirstPeriod             time.Time // periode pertama yang belum dihitung
+	AcquisitionValue        float64
+	BookValue               float64
+	AccumulatedDepreciation float64
+	YearStartBookValue      float64 // book value awal tahun pajak FirstPeriod
+}
+
+// ForecastAssetDepreciation - histori kalkulasi aktual + proyeksi sampai book value mencapai nilai sisa
+func ForecastAssetDepreciation(assetID uint, req dto.DepreciationForecastRequest) (*dto.AssetDepreciationForecastResponse, error) {
+	var asset models.Asset
+	if err := config.DB.First(&asset, assetID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("asset not found")
+		}
+		return nil, err
+	}
+
+	book, err := getDepreciationBook(forecastBookCode(req.BookCode))
+	if err != nil {
+		return nil, err
+	}
+
+	plan, err := planDepreciationForecast(config.DB, asset, *book)
+	if err != nil {
+		return nil, err
+	}
+	if plan == nil {
+		return nil, fmt.Errorf("asset has no depreciation setting or value for book %s", book.BookCode)
+	}
+	if err := applyForecastWhatIf(&plan.Setting, req); err != nil {
+		return nil, err
+	}
+
+	var history []models.MonthlyDepreciationCalculation
+	if err := config.DB.
+		Where("asset_id = ? AND book_code = ?", asset.ID, book.BookCode).
+		Order("period ASC").
+		Find(&history).Error; err != nil {
+		return nil, err
+	}
+
+	forecast := projectDepreciationSchedule(*plan, time.Time{})
+
+	response := &dto.AssetDepreciationForecastResponse{
+		AssetID:               asset.ID,
+		AssetNumber:           asset.AssetNumber,
+		AssetName:             asset.AssetName,
+		BookCode:              book.BookCode,
+		DepreciationSettingID: plan.Setting.ID,
+		CalculationMethod:     plan.Setting.CalculationMethod,
+		DepreciationPeriod:    plan.Setting.DepreciationPeriod,
+		UsefulLifeMonths:      plan.Setting.UsefulLifeMonths,
+		DepreciationRate:      plan.Setting.DepreciationRate,
+		ResidualValue:         residualValueFor(plan.Setting, plan.AcquisitionValue),
+		IsWhatIf:              req.IsWhatIf(),
+		CurrentBookValue:      plan.BookValue,
+		Schedule:              make([]dto.DepreciationSchedulePeriod, 0, len(history)+len(forecast)),
+	}
+
+	for _, calc := range history {
+		response.Schedule = append(response.Schedule, dto.DepreciationSchedulePeriod{
+			Period:                        calc.Period,
+			Source:                        DepreciationScheduleActual,
+			BeginningBookValue:            calc.BeginningBookValue,
+			DepreciationAmount:            calc.DepreciationAmount,
+			EndingAccumulatedDepreciation: calc.EndingAccumulatedDepreciation,
+			EndingBookValue:               calc.EndingBookValue,
+		})
+	}
+
+	for _, row := range forecast {
+		response.TotalForecastDepreciation += row.DepreciationAmount
+		response.Schedule = append(response.Schedule, row)
+	}
+	response.TotalForecastDepreciation = roundCurrency(response.TotalForecastDepreciation)
+
+	if n := len(forecast); n > 0 && forecast[n-1].EndingBookValue <= response.ResidualValue+0.005 {
+		period := forecast[n-1].Period
+		response.FullyDepreciatedPeriod = &period
+	}
+
+	return response, nil
+}
+
+// GetDepreciationForecastSummary - total depresiasi setahun per kategori + branch.
+// Periode yang sudah dihitung diambil dari kalkulasi aktual, sisanya dari proyeksi
+func GetDepreciationForecastSummary(req dto.DepreciationForecastSummaryRequest) (*dto.DepreciationForecastSummaryResponse, error) {
+	book, err := getDepreciationBook(forecastBookCode(req.BookCode))
+	if err != nil {
+		return nil, err
+	}
+
+	yearStart := time.Date(req.FiscalYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	yearEnd := time.Date(req.FiscalYear, time.December, 1, 0, 0, 0, 0, time.UTC)
+
+	type groupKey struct {
+		categoryID uint
+		branchCode string
+	}
+	type groupAcc struct {
+		group  dto.DepreciationForecastSummaryGroup
+		months map[string]*dto.DepreciationForecastSummaryMonth
+		assets map[uint]bool
+	}
+	groups := map[groupKey]*groupAcc{}
+
+	groupFor := func(categoryID *uint, branchCode *string) *groupAcc {
+		key := groupKey{}
+		if categoryID != nil {
+			key.categoryID = *categoryID
+		}
+		if branchCode != nil {
+			key.branchCode = *branchCode
+		}
+		acc, ok := groups[key]
+		if !ok {
+			acc = &groupAcc{
+				group:  dto.DepreciationForecastSummaryGroup{CategoryID: categoryID, BranchCode: branchCode},
+				months: map[string]*dto.DepreciationForecastSummaryMonth{},
+				assets: map[uint]bool{},
+			}
+			groups[key] = acc
+		}
+		return acc
+	}
+	monthFor := func(acc *groupAcc, period string) *dto.DepreciationForecastSummaryMonth {
+		month, ok := acc.months[period]
+		if !ok {
+			month = &dto.DepreciationForecastSummaryMonth{Period: period}
+			acc.months[period] = month
+		}
+		return month
+	}
+
+	// Aktual: semua asset yang punya kalkulasi di tahun ini, termasuk yang sudah disposal
+	type actualRow struct {
+		AssetID            uint
+		CategoryID         *uint
+		BranchCode         *string
+		Period             string
+		DepreciationAmount float64
+	}
+	var actuals []actualRow
+	actualQuery := config.DB.Model(&models.MonthlyDepreciationCalculation{}).
+		Select(`monthly_depreciation_calculations.asset_id, assets.category_id, assets.branch_code,
+			monthly_depreciation_calculations.period, monthly_depreciation_calculations.depreciation_amount`).
+		Joins("JOIN assets ON assets.id = monthly_depreciation_calculations.asset_id").
+		Where("monthly_depreciation_calculations.book_code = ?", book.BookCode).
+		Where("monthly_depreciation_calculations.period BETWEEN ? AND ?", yearStart.Format("2006-01"), yearEnd.Format("2006-01"))
+	actualQuery = applyForecastAssetFilter(actualQuery, req)
+	if err := actualQuery.Scan(&actuals).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range actuals {
+		acc := groupFor(row.CategoryID, row.BranchCode)
+		acc.assets[row.AssetID] = true
+		monthFor(acc, row.Period).ActualDepreciation += row.DepreciationAmount
+	}
+
+	// Forecast: asset yang masih didepresiasi, mulai dari periode setelah kalkulasi terakhirnya
+	var assets []models.Asset
+	assetQuery := config.DB.Model(&models.Asset{}).
+		Where("assets.asset_status IN ?", []string{models.AssetStatusAvailable})
+	assetQuery = applyForecastAssetFilter(assetQuery, req)
+	if err := assetQuery.Find(&assets).Error; err != nil {
+		return nil, err
+	}
+
+	for _, asset := range assets {
+		plan, err := planDepreciationForecast(config.DB, asset, *book)
+		if err != nil {
+			return nil, err
+		}
+		if plan == nil || plan.FirstPeriod.After(yearEnd) {
+			continue
+		}
+
+		for _, row := range projectDepreciationSchedule(*plan, yearEnd) {
+			if row.Period < yearStart.Format("2006-01") {
+				continue
+			}
+			acc := groupFor(asset.CategoryID, asset.BranchCode)
+			acc.assets[asset.ID] = true
+			monthFor(acc, row.Period).ForecastDepreciation += row.DepreciationAmount
+		}
+	}
+
+	categoryNames, branchNames, err := forecastGroupNames()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.DepreciationForecastSummaryResponse{
+		FiscalYear: req.FiscalYear,
+		BookCode:   book.BookCode,
+		Groups:     make([]dto.DepreciationForecastSummaryGroup, 0, len(groups)),
+	}
+
+	for key, acc := range groups {
+		group := acc.group
+		group.AssetCount = len(acc.assets)
+		if name, ok := categoryNames[key.categoryID]; ok {
+			group.CategoryName = &name
+		}
+		if name, ok := branchNames[key.branchCode]; ok {
+			group.BranchName = &name
+		}
+
+		group.Months = make([]dto.DepreciationForecastSummaryMonth, 0, len(acc.months))
+		for _, month := range acc.months {
+			month.ActualDepreciation = roundCurrency(month.ActualDepreciation)
+			month.ForecastDepreciation = roundCurrency(month.ForecastDepreciation)
+			month.TotalDepreciation = roundCurrency(month.ActualDepreciation + month.ForecastDepreciation)
+
+			group.ActualDepreciation += month.ActualDepreciation
+			group.ForecastDepreciation += month.ForecastDepreciation
+			group.Months = append(group.Months, *month)
+		}
+		sort.Slice(group.Months, func(i, j int) bool {
+			return group.Months[i].Period < group.Months[j].Period
+		})
+
+		group.ActualDepreciation = roundCurrency(group.ActualDepreciation)
+		group.ForecastDepreciation = roundCurrency(group.ForecastDepreciation)
+		group.TotalDepreciation = roundCurrency(group.ActualDepreciation + group.ForecastDepreciation)
+
+		response.ActualDepreciation += group.ActualDepreciation
+		response.ForecastDepreciation += group.ForecastDepreciation
+		response.Groups = append(response.Groups, group)
+	}
+
+	sort.Slice(response.Groups, func(i, j int) bool {
+		a, b := response.Groups[i], response.Groups[j]
+		if derefString(a.CategoryName) != derefString(b.CategoryName) {
+			return derefString(a.CategoryName) < derefString(b.CategoryName)
+		}
+		return derefString(a.BranchCode) < derefString(b.BranchCode)
+	})
+
+	response.ActualDepreciation = roundCurrency(response.ActualDepreciation)
+	response.ForecastDepreciation = roundCurrency(response.ForecastDepreciation)
+	response.TotalDepreciation = roundCurrency(response.ActualDepreciation + response.ForecastDepreciation)
+
+	return response, nil
+}
+
+// planDepreciationForecast - posisi awal proyeksi. nil kalau asset tidak punya setting / AssetValue aktif
+func planDepreciationForecast(db *gorm.DB, asset models.Asset, book models.DepreciationBook) (*depreciationForecastPlan, error) {
+	var currentValue models.AssetValue
+	if err := db.Where("asset_id = ? AND is_active = ?", asset.ID, true).
+		First(&currentValue).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	assetStart, err := depreciationStartDate(db, asset.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Periode pertama = setelah kalkulasi terakhir buku ini; belum pernah dihitung = bulan berjalan
+	// (atau bulan mulai asset kalau lebih belakang)
+	now := time.Now()
+	firstPeriod := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if startMonth := time.Date(assetStart.Year(), assetStart.Month(), 1, 0, 0, 0, 0, time.UTC); startMonth.After(firstPeriod) {
+		firstPeriod = startMonth
+	}
+
+	var last models.MonthlyDepreciationCalculation
+	err = db.Where("asset_id = ? AND book_code = ?", asset.ID, book.BookCode).
+		Order("period DESC").
+		First(&last).Error
+	if err == nil {
+		lastPeriod, parseErr := time.Parse("2006-01", last.Period)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		firstPeriod = lastPeriod.AddDate(0, 1, 0)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	setting, err := resolveDepreciationSetting(db, asset, book.BookCode, firstPeriod, firstPeriod.AddDate(0, 1, -1))
+	if err != nil {
+		return nil, err
+	}
+	if setting == nil {
+		return nil, nil
+	}
+
+	plan := &depreciationForecastPlan{
+		Setting:                 *setting,
+		AssetStart:              assetStart,
+		FirstPeriod:             firstPeriod,
+		AcquisitionValue:        currentValue.AcquisitionValue,
+		BookValue:               currentValue.BookValue,
+		AccumulatedDepreciation: currentValue.AccumulatedDepreciation,
+	}
+
+	plan.YearStartBookValue, err = yearStartBookValue(db, asset.ID, book.BookCode, firstPeriod, currentValue.AcquisitionValue)
+	if err != nil {
+		return nil, err
+	}
+
+	// Buku non-AssetValue lanjut dari kalkulasi terakhirnya sendiri
+	if !book.DrivesAssetValue {
+		plan.BookValue, plan.AccumulatedDepreciation, err = bookPositionBefore(db, asset.ID, book.BookCode,
+			firstPeriod.Format("2006-01"), currentValue.AcquisitionValue)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return plan, nil
+}
+
+// projectDepreciationSchedule - proyeksi bulan per bulan sampai nilai sisa, EndDate setting,
+// atau sampai periode until (zero = tanpa batas selain maxForecastMonths)
+func projectDepreciationSchedule(plan depreciationForecastPlan, until time.Time) []dto.DepreciationSchedulePeriod {
+	setting := plan.Setting
+	residual := residualValueFor(setting, plan.AcquisitionValue)
+	bookValue := plan.BookValue
+	accumulated := plan.AccumulatedDepreciation
+	yearStartValue := plan.YearStartBookValue
+
+	var schedule []dto.DepreciationSchedulePeriod
+	for i := 0; i < maxForecastMonths; i++ {
+		periodStart := plan.FirstPeriod.AddDate(0, i, 0)
+		if !until.IsZero() && periodStart.After(until) {
+			break
+		}
+		if setting.EndDate != nil && periodStart.After(dateOnly(*setting.EndDate)) {
+			break
+		}
+		if bookValue-residual < 0.005 {
+			break
+		}
+
+		if i > 0 && periodStart.Month() == time.January {
+			yearStartValue = bookValue
+		}
+
+		from, to, ok := accrualWindow(periodStart, plan.AssetStart, setting, nil)
+		if !ok {
+			continue
+		}
+
+		amount := calculateDepreciation(depreciationInput{
+			BookValue:          bookValue,
+			AcquisitionValue:   plan.AcquisitionValue,
+			YearStartBookValue: yearStartValue,
+			ElapsedMonths:      elapsedLifeMonths(plan.AssetStart, periodStart),
+			Fraction:           periodFraction(setting.DepreciationPeriod, from, to),
+			Setting:            setting,
+		})
+		if amount > bookValue {
+			amount = bookValue
+		}
+
+		beginning := bookValue
+		bookValue = roundCurrency(bookValue - amount)
+		accumulated = roundCurrency(accumulated + amount)
+
+		schedule = append(schedule, dto.DepreciationSchedulePeriod{
+			Period:                        periodStart.Format("2006-01"),
+			Source:                        DepreciationScheduleForecast,
+			BeginningBookValue:            beginning,
+			DepreciationAmount:            amount,
+			EndingAccumulatedDepreciation: accumulated,
+			EndingBookValue:               bookValue,
+		})
+	}
+	return schedule
+}
+
+// applyForecastWhatIf - timpa setting dengan parameter what-if lalu validasi kombinasinya.
+// residual_value tanpa residual_type ditolak (tidak jelas persen atau nominal)
+func applyForecastWhatIf(setting *models.DepreciationSetting, req dto.DepreciationForecastRequest) error {
+	if req.ResidualValue != nil && req.ResidualType == nil {
+		return errors.New("residual_type is required when residual_value is set")
+	}
+
+	if req.CalculationMethod != nil {
+		setting.CalculationMethod = *req.CalculationMethod
+	}
+	if req.UsefulLifeMonths != nil {
+		setting.UsefulLifeMonths = *req.UsefulLifeMonths
+	}
+	if req.DepreciationRate != nil {
+		setting.DepreciationRate = req.DepreciationRate
+	}
+	if req.ResidualType != nil {
+		if *req.ResidualType == "NONE" {
+			setting.ResidualType = nil
+			setting.ResidualValue = nil
+		} else {
+			setting.ResidualType = req.ResidualType
+			setting.ResidualValue = req.ResidualValue
+		}
+	}
+
+	return validateDepreciationMethod(setting.CalculationMethod, setting.DepreciationRate, setting.ResidualType, setting.ResidualValue)
+}
+
+func applyForecastAssetFilter(query *gorm.DB, req dto.DepreciationForecastSummaryRequest) *gorm.DB {
+	if req.CategoryID != nil {
+		query = query.Where("assets.category_id = ?", *req.CategoryID)
+	}
+	if req.BranchCode != nil && *req.BranchCode != "" {
+		query = query.Where("assets.branch_code = ?", *req.BranchCode)
+	}
+	return query
+}
+
+// forecastGroupNames - nama kategori & branch untuk label group
+func forecastGroupNames() (map[uint]string, map[string]string, error) {
+	var categories []models.AssetCategory
+	if err := config.DB.Select("id, category_name").Find(&categories).Error; err != nil {
+		return nil, nil, err
+	}
+	categoryNames := make(map[uint]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID] = category.CategoryName
+	}
+
+	var branches []models.Branch
+	if err := config.DB.Select("branch_code, branch_name").Find(&branches).Error; err != nil {
+		return nil, nil, err
+	}
+	branchNames := make(map[string]string, len(branches))
+	for _, branch := range branches {
+		branchNames[branch.BranchCode] = branch.BranchName
+	}
+	return categoryNames, branchNames, nil
+}
+
+func forecastBookCode(bookCode string) string {
+	if bookCode == "" {
+		return models.DepreciationBookCommercial
+	}
+	return bookCode
+}
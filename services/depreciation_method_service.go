@@ -0,0 +1,248 @@
+package services
+
+import (
+	"backend-go/models"
+	"errors"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// DEPRECIATION METHODS
+// Semua metode menghitung beban sebulan penuh lalu dikali porsi periode,
+// dan tidak pernah menurunkan book value di bawah nilai sisa (residual value)
+// ============================================================================
+
+// depreciationInput - posisi aset di awal periode yang dihitung
+type depreciationInput struct {
+	BookValue          float64
+	AcquisitionValue   float64
+	YearStartBookValue float64 // book value awal tahun pajak (atau nilai perolehan kalau diperoleh tahun ini)
+	ElapsedMonths      float64 // umur yang sudah terpakai sebelum periode ini (bulan, bisa pecahan)
+	Fraction           float64 // porsi bulan yang dibebankan periode ini: 1 = sebulan penuh
+	Setting            models.DepreciationSetting
+}
+
+// calculateDepreciation - beban depresiasi periode ini sesuai calculation_method setting.
+// Beban sebulan penuh dikali Fraction (pro-rata bulan pertama / disposal / mode DAILY)
+func calculateDepreciation(in depreciationInput) float64 {
+	if in.Fraction <= 0 {
+		return 0
+	}
+
+	residual := residualValueFor(in.Setting, in.AcquisitionValue)
+	depreciable := in.BookValue - residual
+	if depreciable <= 0 {
+		return 0
+	}
+
+	lifeMonths := float64(in.Setting.UsefulLifeMonths)
+	remainingMonths := lifeMonths - in.ElapsedMonths
+
+	// Periode ini menutup (atau sudah lewat) masa manfaat: habiskan sampai nilai sisa
+	if lifeMonths > 0 && remainingMonths <= in.Fraction+1e-9 {
+		return roundCurrency(depreciable)
+	}
+
+	var amount float64
+	switch in.Setting.CalculationMethod {
+	case models.CalculationMethodStraightLine:
+		amount = straightLineAmount(depreciable, remainingMonths)
+
+	case models.CalculationMethodDecliningBalance:
+		rate := in.Setting.DepreciationRate
+		if rate == nil || *rate <= 0 {
+			return 0
+		}
+		if in.Setting.TaxGroup != nil {
+			// Saldo menurun fiskal: tarif tahunan × nilai buku awal tahun pajak, dibebankan 1/12 per bulan
+			yearStart := in.YearStartBookValue
+			if yearStart <= 0 {
+				yearStart = in.BookValue
+			}
+			amount = yearStart * (*rate / 100) / 12
+		} else {
+			amount = in.BookValue * (*rate / 100)
+		}
+
+	case models.CalculationMethodSumOfYearsDigits:
+		amount = sumOfYearsDigitsAmount(in.AcquisitionValue-residual, in.Setting.UsefulLifeMonths, in.ElapsedMonths)
+
+	case models.CalculationMethodDoubleDeclining:
+		// Rate bulanan = 2 / masa manfaat. Pindah ke garis lurus begitu
+		// garis lurus atas sisa umur memberi beban lebih besar
+		declining := in.BookValue * 2 / lifeMonths
+		amount = math.Max(declining, straightLineAmount(depreciable, remainingMonths))
+
+	default:
+		return 0
+	}
+
+	amount *= in.Fraction
+	if amount > depreciable {
+		amount = depreciable
+	}
+	if amount < 0 {
+		amount = 0
+	}
+	return roundCurrency(amount)
+}
+
+// straightLineAmount - sisa nilai yang bisa didepresiasi dibagi sisa umur
+func straightLineAmount(depreciable, remainingMonths float64) float64 {
+	if remainingMonths <= 0 {
+		return depreciable
+	}
+	return depreciable / remainingMonths
+}
+
+// sumOfYearsDigitsAmount - beban tahun ke-k = basis × (N-k) / S, dibagi rata per bulan.
+// N = masa manfaat dalam tahun (boleh pecahan, misal 30 bulan = 2.5). Tahun terakhir yang tidak
+// genap 12 bulan diberi bobot sesuai panjangnya, jadi S = Σ (N-k) × (bulan di tahun k / 12).
+// Masa manfaat kelipatan 12 bulan sama dengan SYD biasa: S = N(N+1)/2
+func sumOfYearsDigitsAmount(base float64, lifeMonths int, elapsedMonths float64) float64 {
+	if base <= 0 || lifeMonths <= 0 {
+		return 0
+	}
+
+	years := (lifeMonths + 11) / 12
+	yearIndex := int(elapsedMonths / 12)
+	if yearIndex >= years {
+		return 0
+	}
+
+	lifeYears := float64(lifeMonths) / 12
+	var digits float64
+	for k := 0; k < years; k++ {
+		monthsInYear := math.Min(12, float64(lifeMonths-12*k))
+		digits += (lifeYears - float64(k)) * monthsInYear / 12
+	}
+
+	annual := base * (lifeYears - float64(yearIndex)) / digits
+	return annual / 12
+}
+
+// residualValueFor - nilai sisa dalam rupiah dari setting (persen nilai perolehan atau nominal)
+func residualValueFor(setting models.DepreciationSetting, acquisitionValue float64) float64 {
+	if setting.ResidualType == nil || setting.ResidualValue == nil {
+		return 0
+	}
+
+	switch *setting.ResidualType {
+	case models.ResidualTypePercentage:
+		return roundCurrency(acquisitionValue * *setting.ResidualValue / 100)
+	case models.ResidualTypeAmount:
+		return *setting.ResidualValue
+	default:
+		return 0
+	}
+}
+
+// ============================================================================
+// PERIODE & PRO-RATA
+// ============================================================================
+
+// depreciationStartDate - tanggal asset mulai didepresiasi: AssetValue pertama (tanggal GR / perolehan)
+func depreciationStartDate(db *gorm.DB, assetID uint) (time.Time, error) {
+	var first models.AssetValue
+	if err := db.Where("asset_id = ?", assetID).
+		Order("effective_date ASC, id ASC").
+		First(&first).Error; err != nil {
+		return time.Time{}, err
+	}
+	return dateOnly(first.EffectiveDate), nil
+}
+
+// elapsedLifeMonths - umur terpakai dari tanggal mulai sampai awal periode.
+// Bulan pertama yang dimulai di tengah bulan dihitung pecahan sesuai jumlah hari
+func elapsedLifeMonths(start, periodStart time.Time) float64 {
+	months := monthsBetween(start, periodStart)
+	if months <= 0 {
+		return 0
+	}
+
+	daysInFirstMonth := daysInMonth(start)
+	firstMonth := float64(daysInFirstMonth-start.Day()+1) / float64(daysInFirstMonth)
+	return firstMonth + float64(months-1)
+}
+
+// accrualWindow - rentang tanggal di dalam periode yang boleh didepresiasi:
+// dibatasi tanggal mulai asset, StartDate/EndDate setting dan tanggal disposal (eksklusif).
+// ok = false kalau tidak ada hari yang didepresiasi
+func accrualWindow(periodStart, assetStart time.Time, setting models.DepreciationSetting, disposalDate *time.Time) (time.Time, time.Time, bool) {
+	from := periodStart
+	to := periodStart.AddDate(0, 1, -1)
+
+	if assetStart.After(from) {
+		from = assetStart
+	}
+	if settingStart := dateOnly(setting.StartDate); settingStart.After(from) {
+		from = settingStart
+	}
+	if setting.EndDate != nil {
+		if settingEnd := dateOnly(*setting.EndDate); settingEnd.Before(to) {
+			to = settingEnd
+		}
+	}
+	if disposalDate != nil {
+		if lastDay := dateOnly(*disposalDate).AddDate(0, 0, -1); lastDay.Before(to) {
+			to = lastDay
+		}
+	}
+
+	return from, to, !to.Before(from)
+}
+
+// periodFraction - porsi bulan yang dibebankan.
+// MONTHLY: hari / hari dalam bulan (bulan penuh = 1). DAILY: hari kalender × 12 / hari dalam setahun
+func periodFraction(depreciationPeriod string, from, to time.Time) float64 {
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days <= 0 {
+		return 0
+	}
+
+	if depreciationPeriod == models.DepreciationPeriodDaily {
+		daysInYear := time.Date(from.Year(), 12, 31, 0, 0, 0, 0, time.UTC).YearDay()
+		return float64(days) * 12 / float64(daysInYear)
+	}
+
+	monthDays := daysInMonth(from)
+	if days >= monthDays {
+		return 1
+	}
+	return float64(days) / float64(monthDays)
+}
+
+// monthsBetween - selisih bulan kalender dari bulan from ke bulan to
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
+
+func daysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// validateDepreciationMethod - cek kombinasi method, rate & nilai sisa sebelum disimpan
+func validateDepreciationMethod(method string, rate *float64, residualType *string, residualValue *float64) error {
+	if method == models.CalculationMethodDecliningBalance && (rate == nil || *rate <= 0) {
+		return errors.New("depreciation_rate is required for DECLINING_BALANCE")
+	}
+
+	if (residualType == nil) != (residualValue == nil) {
+		return errors.New("residual_type and residual_value must be set together")
+	}
+	if residualType != nil && *residualType == models.ResidualTypePercentage && *residualValue > 100 {
+		return errors.New("residual_value percentage must be between 0 and 100")
+	}
+	return nil
+}
+
+func roundCurrency(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ============================================================================
+// REVERSAL DEPRESIASI
+// Batalkan kalkulasi satu periode (termasuk yang sudah di-lock):
+// AssetValue hasil kalkulasi dihapus, AssetValue sebelumnya diaktifkan lagi
+// (atau AssetValue sesudahnya digeser kalau asset sudah berubah lagi), baris kalkulasi dihapus dan disimpan sebagai snapshot di depreciation_period_reversals.
+// Setelah itu periode bisa dihitung ulang lewat CalculateMonthlyDepreciation
+// ============================================================================
+
+// ReverseMonthlyDepreciation - book_code kosong = semua buku yang punya kalkulasi di periode ini
+func ReverseMonthlyDepreciation(userID string, req dto.ReverseDepreciationRequest, meta dto.RequestMeta) ([]dto.DepreciationReversalResponse, error) {
+	if _, err := time.Parse("2006-01", req.Period); err != nil {
+		return nil, errors.New("invalid period format, use YYYY-MM")
+	}
+
+	books, err := depreciationBooksFor(req.BookCode)
+	if err != nil {
+		return nil, err
+	}
+
+	var reversals []models.DepreciationPeriodReversal
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		for _, book := range books {
+			reversal, err := reverseDepreciationBookPeriod(tx, userID, book, req, meta)
+			if err != nil {
+				return err
+			}
+			if reversal != nil {
+				reversals = append(reversals, *reversal)
+			}
+		}
+
+		if len(reversals) == 0 {
+			return fmt.Errorf("no depreciation calculations found for period %s", req.Period)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.DepreciationReversalResponse, len(reversals))
+	for i, reversal := range reversals {
+		result[i] = mapDepreciationReversalToResponse(reversal)
+	}
+	return result, nil
+}
+
+// reverseDepreciationBookPeriod - nil kalau buku ini tidak punya kalkulasi di periode tsb
+func reverseDepreciationBookPeriod(tx *gorm.DB, userID string, book models.DepreciationBook, req dto.ReverseDepreciationRequest, meta dto.RequestMeta) (*models.DepreciationPeriodReversal, error) {
+	var calculations []models.MonthlyDepreciationCalculation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Asset").
+		Where("period = ? AND book_code = ?", req.Period, book.BookCode).
+		Order("asset_id ASC").
+		Find(&calculations).Error; err != nil {
+		return nil, err
+	}
+	if len(calculations) == 0 {
+		return nil, nil
+	}
+
+	// Periode sesudahnya yang sudah di-lock tidak boleh ikut berubah
+	var laterLocked models.MonthlyDepreciationCalculation
+	err := tx.Where("book_code = ? AND period > ? AND is_locked = ?", book.BookCode, req.Period, true).
+		Order("period ASC").
+		First(&laterLocked).Error
+	if err == nil {
+		return nil, fmt.Errorf("cannot reverse %s %s: later period %s is already locked",
+			book.BookCode, req.Period, laterLocked.Period)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// Chain AssetValue / posisi buku fiskal berlanjut ke periode berikutnya → reverse dari yang terakhir
+	later, err := laterCalculatedPeriod(tx, book.BookCode, req.Period)
+	if err != nil {
+		return nil, err
+	}
+	if later != "" {
+		return nil, fmt.Errorf("cannot reverse %s %s: period %s is already calculated, reverse it first",
+			book.BookCode, req.Period, later)
+	}
+
+	reversal := models.DepreciationPeriodReversal{
+		Period:           req.Period,
+		BookCode:         book.BookCode,
+		Reason:           req.Reason,
+		CalculationCount: len(calculations),
+		ReversedBy:       userID,
+		Metadata:         requestMetaJSON(meta),
+	}
+
+	var rebasedAssets []string
+	for _, calc := range calculations {
+		if calc.IsLocked {
+			reversal.WasLocked = true
+		}
+		if !book.DrivesAssetValue {
+			continue
+		}
+
+		assetNumber := fmt.Sprint(calc.AssetID)
+		if calc.Asset != nil {
+			assetNumber = calc.Asset.AssetNumber
+		}
+
+		rebased, err := restoreCalculationAssetValue(tx, calc)
+		if err != nil {
+			return nil, fmt.Errorf("asset %s: %w", assetNumber, err)
+		}
+		if rebased {
+			reversal.RebasedAssetValueCount++
+			rebasedAssets = append(rebasedAssets, assetNumber)
+			continue
+		}
+		reversal.RestoredAssetValueCount++
+	}
+	if len(rebasedAssets) > 0 {
+		rebasedJSON, err := json.Marshal(rebasedAssets)
+		if err != nil {
+			return nil, err
+		}
+		rebasedStr := string(rebasedJSON)
+		reversal.RebasedAssets = &rebasedStr
+	}
+
+	// Snapshot hanya baris kalkulasi, tanpa data asset yang di-preload
+	for i := range calculations {
+		calculations[i].Asset = nil
+	}
+	snapshot, err := json.Marshal(calculations)
+	if err != nil {
+		return nil, err
+	}
+	snapshotStr := string(snapshot)
+	reversal.CalculationsSnapshot = &snapshotStr
+
+	if err := tx.Where("period = ? AND book_code = ?", req.Period, book.BookCode).
+		Delete(&models.MonthlyDepreciationCalculation{}).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Create(&reversal).Error; err != nil {
+		return nil, err
+	}
+	return &reversal, nil
+}
+
+// restoreCalculationAssetValue - hapus AssetValue hasil kalkulasi dan aktifkan lagi AssetValue sebelumnya.
+// Kalau sudah ada AssetValue sesudahnya (disposal, stock opname), baris-baris itu yang digeser sebesar
+// depresiasi yang dibatalkan dan tetap aktif → rebased = true.
+// Nilai buku baris disposal tetap 0, hanya akumulasinya yang digeser
+func restoreCalculationAssetValue(tx *gorm.DB, calc models.MonthlyDepreciationCalculation) (bool, error) {
+	created, previousID, err := calculationAssetValueLink(tx, calc)
+	if err != nil {
+		return false, err
+	}
+
+	var previous models.AssetValue
+	if err := tx.Where("id = ? AND asset_id = ?", previousID, calc.AssetID).
+		First(&previous).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, fmt.Errorf("previous asset value of depreciation %s not found", calc.Period)
+		}
+		return false, err
+	}
+
+	if created.IsActive {
+		if err := tx.Delete(created).Error; err != nil {
+			return false, err
+		}
+		if err := tx.Model(&previous).Update("is_active", true).Error; err != nil {
+			return false, err
+		}
+		return false, nil
+	}
+
+	var laterValues []models.AssetValue
+	if err := tx.Where("asset_id = ? AND id > ?", calc.AssetID, created.ID).
+		Order("id ASC").
+		Find(&laterValues).Error; err != nil {
+		return false, err
+	}
+
+	bookValueDelta := previous.BookValue - created.BookValue
+	accumulatedDelta := previous.AccumulatedDepreciation - created.AccumulatedDepreciation
+	for _, value := range laterValues {
+		updates := map[string]interface{}{
+			"accumulated_depreciation": roundCurrency(value.AccumulatedDepreciation + accumulatedDelta),
+		}
+		disposed := value.AssetStatus != nil && *value.AssetStatus == models.AssetStatusDisposed && value.BookValue == 0
+		if !disposed {
+			updates["book_value"] = roundCurrency(value.BookValue + bookValueDelta)
+		}
+		if err := tx.Model(&models.AssetValue{}).Where("id = ?", value.ID).
+			Updates(updates).Error; err != nil {
+			return false, err
+		}
+	}
+
+	if err := tx.Delete(created).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// calculationAssetValueLink - AssetValue yang dibuat kalkulasi + id AssetValue sebelumnya.
+// Kalkulasi lama (sebelum ada asset_value_id) dicocokkan dari AssetValue yang nilainya sama
+// dengan hasil kalkulasi, sebelumnya = AssetValue terakhir sebelum itu
+func calculationAssetValueLink(tx *gorm.DB, calc models.MonthlyDepreciationCalculation) (*models.AssetValue, uint, error) {
+	var created models.AssetValue
+
+	if calc.AssetValueID != nil && calc.PreviousAssetValueID != nil {
+		if err := tx.Where("id = ? AND asset_id = ?", *calc.AssetValueID, calc.AssetID).
+			First(&created).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, 0, fmt.Errorf("asset value of depreciation %s not found", calc.Period)
+			}
+			return nil, 0, err
+		}
+		return &created, *calc.PreviousAssetValueID, nil
+	}
+
+	err := tx.Where("asset_id = ? AND effective_date = ? AND book_value = ? AND accumulated_depreciation = ?",
+		calc.AssetID, calc.CalculationDate, calc.EndingBookValue, calc.EndingAccumulatedDepreciation).
+		Order("id DESC").
+		First(&created).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, fmt.Errorf("asset value of depreciation %s cannot be matched, reverse it manually", calc.Period)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var previous models.AssetValue
+	err = tx.Where("asset_id = ? AND id < ?", calc.AssetID, created.ID).
+		Order("id DESC").
+		First(&previous).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, fmt.Errorf("previous asset value of depreciation %s not found", calc.Period)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return &created, previous.ID, nil
+}
+
+// GetDepreciationReversals - histori reversal, terbaru di atas
+func GetDepreciationReversals(period, bookCode string) ([]dto.DepreciationReversalResponse, error) {
+	query := config.DB.Model(&models.DepreciationPeriodReversal{})
+	if period != "" {
+		query = query.Where("period = ?", period)
+	}
+	if bookCode != "" {
+		query = query.Where("book_code = ?", bookCode)
+	}
+
+	var reversals []models.DepreciationPeriodReversal
+	if err := query.Order("created_at DESC, id DESC").Find(&reversals).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.DepreciationReversalResponse, len(reversals))
+	for i, reversal := range reversals {
+		result[i] = mapDepreciationReversalToResponse(reversal)
+	}
+	return result, nil
+}
+
+func mapDepreciationReversalToResponse(reversal models.DepreciationPeriodReversal) dto.DepreciationReversalResponse {
+	var rebasedAssets []string
+	if reversal.RebasedAssets != nil {
+		_ = json.Unmarshal([]byte(*reversal.RebasedAssets), &rebasedAssets)
+	}
+
+	return dto.DepreciationReversalResponse{
+		ID:                      reversal.ID,
+		Period:                  reversal.Period,
+		BookCode:                reversal.BookCode,
+		Reason:                  reversal.Reason,
+		WasLocked:               reversal.WasLocked,
+		CalculationCount:        reversal.CalculationCount,
+		RestoredAssetValueCount: reversal.RestoredAssetValueCount,
+		RebasedAssetValueCount:  reversal.RebasedAssetValueCount,
+		RebasedAssets:           rebasedAssets,
+		ReversedBy:              reversal.ReversedBy,
+		ReversedAt:              reversal.CreatedAt,
+	}
+}
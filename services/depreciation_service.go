@@ -48,6 +48,24 @@ func CreateDepreciationSetting(req dto.CreateDepreciationSettingRequest) (*dto.D
 		}
 	}
 
+	// Default buku komersial; buku fiskal boleh pakai kelompok harta
+	if req.BookCode == "" {
+		req.BookCode = models.DepreciationBookCommercial
+	}
+	if _, err := getDepreciationBook(req.BookCode); err != nil {
+		return nil, err
+	}
+	if err := applyFiscalTaxGroup(req.BookCode, req.TaxGroup, req.CalculationMethod, &req.UsefulLifeMonths, &req.DepreciationRate, req.ResidualType); err != nil {
+		return nil, err
+	}
+	if req.UsefulLifeMonths <= 0 {
+		return nil, errors.New("useful_life_months is required")
+	}
+
+	if err := validateDepreciationMethod(req.CalculationMethod, req.DepreciationRate, req.ResidualType, req.ResidualValue); err != nil {
+		return nil, err
+	}
+
 	// Parse StartDate
 	startDate, err := time.Parse("2006-01-02", req.StartDate)
 	if err != nil {
@@ -66,6 +84,7 @@ func CreateDepreciationSetting(req dto.CreateDepreciationSettingRequest) (*dto.D
 
 	// FIX: pakai ReferenceID & ReferenceValue, hapus CategoryID/AssetID/ResidualValue
 	setting := models.DepreciationSetting{
+		BookCode:           req.BookCode,
 		SettingType:        req.SettingType,
 		ReferenceID:        req.ReferenceID,
 		ReferenceValue:     req.ReferenceValue,
@@ -73,6 +92,9 @@ func CreateDepreciationSetting(req dto.CreateDepreciationSettingRequest) (*dto.D
 		DepreciationPeriod: req.DepreciationPeriod,
 		UsefulLifeMonths:   req.UsefulLifeMonths,
 		DepreciationRate:   req.DepreciationRate,
+		ResidualType:       req.ResidualType,
+		ResidualValue:      req.ResidualValue,
+		TaxGroup:           req.TaxGroup,
 		StartDate:          startDate,
 		EndDate:            endDate,
 		IsActive:           req.IsActive,
@@ -111,6 +133,55 @@ func UpdateDepreciationSetting(id uint, req dto.UpdateDepreciationSettingRequest
 	if req.IsActive != nil {
 		updates["is_active"] = *req.IsActive
 	}
+
+	// Validasi dengan nilai gabungan (request + yang sudah tersimpan)
+	method := setting.CalculationMethod
+	if req.CalculationMethod != nil {
+		method = *req.CalculationMethod
+	}
+	rate := setting.DepreciationRate
+	if req.DepreciationRate != nil {
+		rate = req.DepreciationRate
+	}
+	residualType, residualValue := setting.ResidualType, setting.ResidualValue
+	if req.ResidualType != nil {
+		if *req.ResidualType == "NONE" {
+			residualType, residualValue = nil, nil
+		} else {
+			residualType = req.ResidualType
+			if req.ResidualValue != nil {
+				residualValue = req.ResidualValue
+			}
+		}
+		updates["residual_type"] = residualType
+		updates["residual_value"] = residualValue
+	} else if req.ResidualValue != nil {
+		residualValue = req.ResidualValue
+		updates["residual_value"] = residualValue
+	}
+
+	// Kelompok harta fiskal menentukan masa manfaat & tarif
+	taxGroup := setting.TaxGroup
+	if req.TaxGroup != nil {
+		taxGroup = req.TaxGroup
+		if *req.TaxGroup == "" {
+			taxGroup = nil
+		}
+		updates["tax_group"] = taxGroup
+	}
+	if taxGroup != nil {
+		lifeMonths := setting.UsefulLifeMonths
+		if err := applyFiscalTaxGroup(setting.BookCode, taxGroup, method, &lifeMonths, &rate, residualType); err != nil {
+			return nil, err
+		}
+		updates["useful_life_months"] = lifeMonths
+		updates["depreciation_rate"] = rate
+	}
+
+	if err := validateDepreciationMethod(method, rate, residualType, residualValue); err != nil {
+		return nil, err
+	}
+
 	// FIX: tambah EndDate update
 	if req.EndDate != nil {
 		if *req.EndDate == "" {
@@ -175,13 +246,18 @@ func DeleteDepreciationSetting(id uint) error {
 // Monthly Depreciation Calculations
 // ============================================================================
 
-func GetMonthlyDepreciationCalculations(period string) ([]dto.MonthlyDepreciationResponse, error) {
+func GetMonthlyDepreciationCalculations(period, bookCode string) ([]dto.MonthlyDepreciationResponse, error) {
 	var calculations []models.MonthlyDepreciationCalculation
 
 	// FIX: query pakai period VARCHAR(7) bukan month+year terpisah
-	if err := config.DB.
+	query := config.DB.
 		Preload("Asset").
-		Where("period = ?", period).
+		Where("period = ?", period)
+	if bookCode != "" {
+		query = query.Where("book_code = ?", bookCode)
+	}
+
+	if err := query.
 		Order("created_at DESC").
 		Find(&calculations).Error; err != nil {
 		return nil, err
@@ -191,20 +267,46 @@ func GetMonthlyDepreciationCalculations(period string) ([]dto.MonthlyDepreciatio
 }
 
 func CalculateMonthlyDepreciation(userID string, req dto.CalculateDepreciationRequest) error {
-	// FIX: cek pakai period string bukan month+year
-	var existing models.MonthlyDepreciationCalculation
-	if err := config.DB.
-		Where("period = ? AND is_locked = ?", req.Period, true).
-		First(&existing).Error; err == nil {
-		return errors.New("depreciation already calculated and locked for this period")
-	}
-
 	// Parse period untuk dapat calculation_date (hari pertama bulan tersebut)
 	calculationDate, err := time.Parse("2006-01", req.Period)
 	if err != nil {
 		return errors.New("invalid period format, use YYYY-MM")
 	}
 
+	books, err := depreciationBooksFor(req.BookCode)
+	if err != nil {
+		return err
+	}
+
+	// Buku yang periode ini sudah di-lock dilewati; kalau buku diminta spesifik → error
+	openBooks := make([]models.DepreciationBook, 0, len(books))
+	for _, book := range books {
+		locked, err := isDepreciationPeriodLocked(config.DB, book.BookCode, req.Period)
+		if err != nil {
+			return err
+		}
+		if locked {
+			if req.BookCode != "" {
+				return errors.New("depreciation already calculated and locked for this period")
+			}
+			continue
+		}
+
+		// Hitung ulang periode lama memutus chain AssetValue periode sesudahnya
+		later, err := laterCalculatedPeriod(config.DB, book.BookCode, req.Period)
+		if err != nil {
+			return err
+		}
+		if later != "" {
+			return fmt.Errorf("cannot calculate %s %s: period %s is already calculated, reverse it first",
+				book.BookCode, req.Period, later)
+		}
+		openBooks = append(openBooks, book)
+	}
+	if len(openBooks) == 0 {
+		return errors.New("depreciation already calculated and locked for this period")
+	}
+
 	// Get all active assets yang siap didepresiasi
 	// Status ACTIVE (asset lama) atau AVAILABLE (asset baru setelah GR)
 	var assets []models.Asset
@@ -221,101 +323,163 @@ func CalculateMonthlyDepreciation(userID string, req dto.CalculateDepreciationRe
 		}
 	}()
 
-	for _, asset := range assets {
-		// Get depreciation setting - coba asset-specific dulu
-		var setting models.DepreciationSetting
-
-		// FIX: query pakai reference_id bukan asset_id/category_id
-		err := tx.Where("setting_type = ? AND reference_id = ? AND is_active = ?",
-			models.SettingTypeAsset, asset.ID, true).
-			First(&setting).Error
-
-		if err != nil {
-			// Fallback ke category setting
-			if asset.CategoryID != nil {
-				err = tx.Where("setting_type = ? AND reference_id = ? AND is_active = ?",
-					models.SettingTypeCategory, *asset.CategoryID, true).
-					First(&setting).Error
+	for _, book := range openBooks {
+		for _, asset := range assets {
+			if err := calculateAssetBookDepreciation(tx, book, asset, req.Period, calculationDate, nil, 0); err != nil {
+				tx.Rollback()
+				return err
 			}
 		}
+	}
 
-		if err != nil {
-			// Skip asset yang tidak punya depreciation setting
-			continue
+	return tx.Commit().Error
+}
+
+// laterCalculatedPeriod - periode terakhir sesudah period yang sudah dihitung di buku ini, "" kalau belum ada
+func laterCalculatedPeriod(db *gorm.DB, bookCode, period string) (string, error) {
+	var later models.MonthlyDepreciationCalculation
+	err := db.Where("book_code = ? AND period > ?", bookCode, period).
+		Order("period DESC").
+		First(&later).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return later.Period, nil
+}
+
+// calculateAssetBookDepreciation - hitung satu asset di satu buku untuk satu periode.
+// disposalDate diisi saat disposal: beban hanya sampai sehari sebelum tanggal disposal.
+// carried = beban periode sebelumnya yang sudah di-lock tanpa kalkulasi asset ini, ikut dibebankan di periode ini.
+// Hanya buku yang DrivesAssetValue yang membuat AssetValue baru
+func calculateAssetBookDepreciation(tx *gorm.DB, book models.DepreciationBook, asset models.Asset, period string, calculationDate time.Time, disposalDate *time.Time, carried float64) error {
+	// Periode yang sudah dihitung tapi belum di-lock dihitung ulang dari awal:
+	// kembalikan dulu AssetValue sebelum kalkulasi lama supaya tidak terdepresiasi dua kali
+	var existingCalc models.MonthlyDepreciationCalculation
+	err := tx.Where("asset_id = ? AND period = ? AND book_code = ?", asset.ID, period, book.BookCode).
+		First(&existingCalc).Error
+	if err == nil {
+		if existingCalc.IsLocked {
+			return nil // skip yang sudah locked
 		}
 
-		// Get current active asset value
-		var currentValue models.AssetValue
-		if err := tx.Where("asset_id = ? AND is_active = ?", asset.ID, true).
-			First(&currentValue).Error; err != nil {
-			continue
+		var laterCount int64
+		if err := tx.Model(&models.MonthlyDepreciationCalculation{}).
+			Where("asset_id = ? AND book_code = ? AND period > ?", asset.ID, book.BookCode, period).
+			Count(&laterCount).Error; err != nil {
+			return err
+		}
+		if laterCount > 0 {
+			return fmt.Errorf("asset %s: %s %s has later calculations, reverse them first",
+				asset.AssetNumber, book.BookCode, period)
 		}
 
-		// FIX: hapus residualValue dari kalkulasi, pakai DepreciationRate langsung
-		depreciationAmount := calculateDepreciation(
-			currentValue.BookValue,
-			setting.CalculationMethod,
-			setting.UsefulLifeMonths,
-			setting.DepreciationRate,
-		)
-
-		// Pastikan book value tidak negatif
-		newBookValue := currentValue.BookValue - depreciationAmount
-		if newBookValue < 0 {
-			newBookValue = 0
-			depreciationAmount = currentValue.BookValue
-		}
-
-		beginningAccumDepr := currentValue.AccumulatedDepreciation
-		endingAccumDepr := beginningAccumDepr + depreciationAmount
-
-		// FIX: Create calculation record dengan field baru
-		calculationMethod := setting.CalculationMethod
-		calculation := models.MonthlyDepreciationCalculation{
-			AssetID:                          asset.ID,
-			Period:                           req.Period,      // FIX: pakai Period
-			CalculationDate:                  calculationDate, // FIX: tambah
-			BeginningBookValue:               currentValue.BookValue,
-			DepreciationAmount:               depreciationAmount,
-			BeginningAccumulatedDepreciation: beginningAccumDepr, // FIX: tambah
-			EndingAccumulatedDepreciation:    endingAccumDepr,    // FIX: tambah
-			EndingBookValue:                  newBookValue,
-			CalculationMethod:                &calculationMethod, // FIX: *string
-			DepreciationSettingID:            &setting.ID,        // FIX: tambah
-			IsLocked:                         false,
-		}
-
-		// Cek apakah sudah ada kalkulasi untuk period ini (update jika belum locked)
-		var existingCalc models.MonthlyDepreciationCalculation
-		err = tx.Where("asset_id = ? AND period = ?", asset.ID, req.Period).
-			First(&existingCalc).Error
-		if err == nil {
-			if existingCalc.IsLocked {
-				continue // skip yang sudah locked
-			}
-			// Update existing
-			if err := tx.Model(&existingCalc).Updates(calculation).Error; err != nil {
-				tx.Rollback()
-				return err
-			}
-		} else {
-			// Create baru
-			if err := tx.Create(&calculation).Error; err != nil {
-				tx.Rollback()
-				return err
+		if book.DrivesAssetValue {
+			if _, err := restoreCalculationAssetValue(tx, existingCalc); err != nil {
+				return fmt.Errorf("asset %s: %w", asset.AssetNumber, err)
 			}
 		}
+		if err := tx.Delete(&existingCalc).Error; err != nil {
+			return err
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
 
+	periodEnd := calculationDate.AddDate(0, 1, -1)
+
+	setting, err := resolveDepreciationSetting(tx, asset, book.BookCode, calculationDate, periodEnd)
+	if err != nil {
+		return err
+	}
+	if setting == nil {
+		// Skip asset yang tidak punya depreciation setting di buku ini
+		return nil
+	}
+
+	// Get current active asset value
+	var currentValue models.AssetValue
+	if err := tx.Where("asset_id = ? AND is_active = ?", asset.ID, true).
+		First(&currentValue).Error; err != nil {
+		return nil
+	}
+
+	// Depresiasi mulai dari AssetValue pertama (tanggal GR / perolehan)
+	assetStart, err := depreciationStartDate(tx, asset.ID)
+	if err != nil {
+		return nil
+	}
+
+	// Hari yang didepresiasi di periode ini: pro-rata bulan GR / disposal, StartDate/EndDate setting
+	from, to, ok := accrualWindow(calculationDate, assetStart, *setting, disposalDate)
+	if !ok {
+		return nil
+	}
+
+	// Buku komersial mulai dari AssetValue aktif, buku lain dari kalkulasi terakhir bukunya sendiri
+	bookValue, beginningAccumDepr := currentValue.BookValue, currentValue.AccumulatedDepreciation
+	if !book.DrivesAssetValue {
+		bookValue, beginningAccumDepr, err = bookPositionBefore(tx, asset.ID, book.BookCode, period, currentValue.AcquisitionValue)
+		if err != nil {
+			return err
+		}
+	}
+
+	yearStartValue, err := yearStartBookValue(tx, asset.ID, book.BookCode, calculationDate, currentValue.AcquisitionValue)
+	if err != nil {
+		return err
+	}
+
+	depreciationAmount := carried + calculateDepreciation(depreciationInput{
+		BookValue:          bookValue - carried,
+		AcquisitionValue:   currentValue.AcquisitionValue,
+		YearStartBookValue: yearStartValue,
+		ElapsedMonths:      elapsedLifeMonths(assetStart, calculationDate),
+		Fraction:           periodFraction(setting.DepreciationPeriod, from, to),
+		Setting:            *setting,
+	})
+	// Pastikan book value tidak negatif
+	newBookValue := bookValue - depreciationAmount
+	if newBookValue < 0 {
+		newBookValue = 0
+		depreciationAmount = bookValue
+	}
+
+	endingAccumDepr := beginningAccumDepr + depreciationAmount
+
+	// FIX: Create calculation record dengan field baru
+	calculationMethod := setting.CalculationMethod
+	calculation := models.MonthlyDepreciationCalculation{
+		AssetID:                          asset.ID,
+		Period:                           period, // FIX: pakai Period
+		BookCode:                         book.BookCode,
+		CalculationDate:                  calculationDate, // FIX: tambah
+		BeginningBookValue:               bookValue,
+		DepreciationAmount:               depreciationAmount,
+		BeginningAccumulatedDepreciation: beginningAccumDepr, // FIX: tambah
+		EndingAccumulatedDepreciation:    endingAccumDepr,    // FIX: tambah
+		EndingBookValue:                  newBookValue,
+		CalculationMethod:                &calculationMethod, // FIX: *string
+		DepreciationSettingID:            &setting.ID,        // FIX: tambah
+		IsLocked:                         false,
+	}
+
+	if book.DrivesAssetValue {
 		// Update asset value lama jadi tidak active
 		if err := tx.Model(&currentValue).Update("is_active", false).Error; err != nil {
-			tx.Rollback()
 			return err
 		}
 
-		// Buat asset value baru
+		// Buat asset value baru; saat disposal berlaku per tanggal disposal
+		effectiveDate := calculationDate
+		if disposalDate != nil {
+			effectiveDate = *disposalDate
+		}
 		newAssetValue := models.AssetValue{
 			AssetID:                 asset.ID,
-			EffectiveDate:           calculationDate,
+			EffectiveDate:           effectiveDate,
 			BookValue:               newBookValue,
 			AcquisitionValue:        currentValue.AcquisitionValue,
 			AccumulatedDepreciation: endingAccumDepr,
@@ -324,44 +488,165 @@ func CalculateMonthlyDepreciation(userID string, req dto.CalculateDepreciationRe
 			AssetStatus:             currentValue.AssetStatus,
 			IsActive:                true,
 		}
-
 		if err := tx.Create(&newAssetValue).Error; err != nil {
-			tx.Rollback()
 			return err
 		}
+
+		// Simpan link chain AssetValue supaya periode ini bisa di-reverse
+		calculation.AssetValueID = &newAssetValue.ID
+		calculation.PreviousAssetValueID = &currentValue.ID
 	}
 
-	return tx.Commit().Error
+	return tx.Create(&calculation).Error
 }
 
-func LockMonthlyDepreciation(period string) error {
-	result := config.DB.Model(&models.MonthlyDepreciationCalculation{}).
-		Where("period = ? AND is_locked = ?", period, false).
-		Update("is_locked", true)
+// depreciateUntilDisposal - bebankan depresiasi sampai tanggal disposal di semua buku aktif,
+// sebelum nilai buku di-nol-kan. Asset IN_DISPOSAL tidak ikut kalkulasi bulanan, jadi periode
+// yang terlewat disusulkan: periode yang masih terbuka dihitung per periode; kalau ada yang sudah
+// di-lock, seluruh susulan dibebankan di bulan disposal (periode ter-lock tidak pernah ditulis).
+// Bulan disposal yang sudah di-lock ditolak
+func depreciateUntilDisposal(tx *gorm.DB, assetID uint, disposalDate time.Time) error {
+	var asset models.Asset
+	if err := tx.First(&asset, assetID).Error; err != nil {
+		return err
+	}
 
-	if result.Error != nil {
-		return result.Error
+	books, err := depreciationBooksFor("")
+	if err != nil {
+		return err
 	}
-	if result.RowsAffected == 0 {
-		return fmt.Errorf("no unlocked calculations found for period %s", period)
+
+	period := disposalDate.Format("2006-01")
+	calculationDate, _ := time.Parse("2006-01", period)
+
+	for _, book := range books {
+		var existing int64
+		if err := tx.Model(&models.MonthlyDepreciationCalculation{}).
+			Where("asset_id = ? AND period = ? AND book_code = ?", assetID, period, book.BookCode).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+		if existing > 0 {
+			continue
+		}
+
+		if locked, err := isDepreciationPeriodLocked(tx, book.BookCode, period); err != nil {
+			return err
+		} else if locked {
+			return fmt.Errorf("depreciation %s %s is already locked, cannot charge disposal of asset %s",
+				book.BookCode, period, asset.AssetNumber)
+		}
+
+		carried, err := catchUpAssetDepreciation(tx, book, asset, calculationDate)
+		if err != nil {
+			return err
+		}
+
+		if err := calculateAssetBookDepreciation(tx, book, asset, period, calculationDate, &disposalDate, carried); err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
-// FIX: hapus residualValue & usefulLifeMonths dari parameter, pakai rate langsung
-func calculateDepreciation(bookValue float64, method string, usefulLifeMonths int, rate *float64) float64 {
-	switch method {
-	case models.CalculationMethodStraightLine:
-		if usefulLifeMonths <= 0 {
-			return 0
+// catchUpAssetDepreciation - susulkan periode sebelum untilPeriod yang belum punya kalkulasi asset ini.
+// Mulai setelah kalkulasi terakhir asset di buku ini; belum pernah dihitung = bulan nilai awal dicatat
+// (nilai hasil import sudah membawa akumulasi sampai saat import).
+// Semua periode terbuka → dihitung per periode, hasil 0. Ada yang ter-lock → tidak ada yang ditulis,
+// total beban susulan dikembalikan untuk dibebankan di untilPeriod
+func catchUpAssetDepreciation(tx *gorm.DB, book models.DepreciationBook, asset models.Asset, untilPeriod time.Time) (float64, error) {
+	var opening models.AssetValue
+	if err := tx.Where("asset_id = ?", asset.ID).
+		Order("effective_date ASC, id ASC").
+		First(&opening).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
 		}
-		return bookValue / float64(usefulLifeMonths)
-	case models.CalculationMethodDecliningBalance:
-		if rate == nil || *rate <= 0 {
-			return 0
+		return 0, err
+	}
+
+	firstPeriod := time.Date(opening.EffectiveDate.Year(), opening.EffectiveDate.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if recorded := time.Date(opening.CreatedAt.Year(), opening.CreatedAt.Month(), 1, 0, 0, 0, 0, time.UTC); recorded.After(firstPeriod) {
+		firstPeriod = recorded
+	}
+
+	var last models.MonthlyDepreciationCalculation
+	err := tx.Where("asset_id = ? AND book_code = ?", asset.ID, book.BookCode).
+		Order("period DESC").
+		First(&last).Error
+	if err == nil {
+		lastPeriod, parseErr := time.Parse("2006-01", last.Period)
+		if parseErr != nil {
+			return 0, parseErr
 		}
-		return bookValue * (*rate / 100)
-	default:
-		return 0
+		firstPeriod = lastPeriod.AddDate(0, 1, 0)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+	if !firstPeriod.Before(untilPeriod) {
+		return 0, nil
 	}
+
+	var lockedGap int64
+	if err := tx.Model(&models.MonthlyDepreciationCalculation{}).
+		Where("book_code = ? AND period >= ? AND period < ? AND is_locked = ?",
+			book.BookCode, firstPeriod.Format("2006-01"), untilPeriod.Format("2006-01"), true).
+		Count(&lockedGap).Error; err != nil {
+		return 0, err
+	}
+
+	if lockedGap == 0 {
+		for calculationDate := firstPeriod; calculationDate.Before(untilPeriod); calculationDate = calculationDate.AddDate(0, 1, 0) {
+			if err := calculateAssetBookDepreciation(tx, book, asset, calculationDate.Format("2006-01"), calculationDate, nil, 0); err != nil {
+				return 0, err
+			}
+		}
+		return 0, nil
+	}
+
+	// Hitung beban susulan dengan proyeksi yang sama dengan forecast, tanpa menulis apa pun
+	plan, err := planDepreciationForecast(tx, asset, book)
+	if err != nil || plan == nil {
+		return 0, err
+	}
+	plan.FirstPeriod = firstPeriod
+	plan.YearStartBookValue, err = yearStartBookValue(tx, asset.ID, book.BookCode, firstPeriod, plan.AcquisitionValue)
+	if err != nil {
+		return 0, err
+	}
+
+	carried := 0.0
+	for _, projected := range projectDepreciationSchedule(*plan, untilPeriod.AddDate(0, -1, 0)) {
+		carried += projected.DepreciationAmount
+	}
+	return roundCurrency(carried), nil
+}
+
+// isDepreciationPeriodLocked - periode + buku sudah di-lock
+func isDepreciationPeriodLocked(db *gorm.DB, bookCode, period string) (bool, error) {
+	var locked int64
+	if err := db.Model(&models.MonthlyDepreciationCalculation{}).
+		Where("period = ? AND book_code = ? AND is_locked = ?", period, bookCode, true).
+		Count(&locked).Error; err != nil {
+		return false, err
+	}
+	return locked > 0, nil
+}
+
+// LockMonthlyDepreciation - bookCode kosong = lock semua buku di periode ini
+func LockMonthlyDepreciation(period, bookCode string) error {
+	query := config.DB.Model(&models.MonthlyDepreciationCalculation{}).
+		Where("period = ? AND is_locked = ?", period, false)
+	if bookCode != "" {
+		query = query.Where("book_code = ?", bookCode)
+	}
+
+	result := query.Update("is_locked", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("no unlocked calculations found for period %s", period)
+	}
+	return nil
 }
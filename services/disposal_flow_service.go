@@ -279,7 +279,7 @@ func RemoveAssetFromDisposal(userID string, transactionNumber string, req dto.Re
 // DRAFT → SUBMITTED
 // ============================================================
 
-func SubmitDisposal(userID string, transactionNumber string, req dto.SubmitDisposalRequest) (*dto.DisposalDetailResponse, error) {
+func SubmitDisposal(userID string, transactionNumber string, req dto.SubmitDisposalRequest, meta dto.RequestMeta) (*dto.DisposalDetailResponse, error) {
 	transaction, err := getDisposalTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -322,7 +322,7 @@ func SubmitDisposal(userID string, transactionNumber string, req dto.SubmitDispo
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, nextStage,
-		models.ActionSubmit, userID, nil, req.Notes); err != nil {
+		models.ActionSubmit, userID, nil, req.Notes, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -339,7 +339,7 @@ func SubmitDisposal(userID string, transactionNumber string, req dto.SubmitDispo
 // SUBMITTED → APPROVAL_REQUEST (setelah purchasing confirm)
 // ============================================================
 
-func SetDisposalSaleValues(userID string, transactionNumber string, req dto.SetDisposalSaleValueRequest) (*dto.DisposalDetailResponse, error) {
+func SetDisposalSaleValues(userID string, transactionNumber string, req dto.SetDisposalSaleValueRequest, meta dto.RequestMeta) (*dto.DisposalDetailResponse, error) {
 	transaction, err := getDisposalTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -389,7 +389,7 @@ func SetDisposalSaleValues(userID string, transactionNumber string, req dto.SetD
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, nextStage,
-		models.ActionSubmit, userID, nil, req.Notes); err != nil {
+		models.ActionSubmit, userID, nil, req.Notes, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -460,25 +460,28 @@ func InitiateDisposalApprovalRequest(userID string, transactionNumber string, re
 	return nil
 }
 
+// autoCompleteDisposalApproval - dipanggil dari ApproveTransaction,
+// masing-masing auto-complete cek stage sendiri jadi aman dipanggil dua-duanya
+func autoCompleteDisposalApproval(userID, transactionNumber, transactionType string, meta dto.RequestMeta) error {
+	if transactionType != TxDisposalFlow {
+		return nil
+	}
+
+	if err := autoCompleteDisposalApprovalRequest(userID, transactionNumber, meta); err != nil {
+		return err
+	}
+
+	return autoCompleteDisposalApprovalAgreement(userID, transactionNumber, meta)
+}
+
 // ============================================================
 // AUTO-COMPLETE APPROVAL REQUEST
 // Dipanggil setelah semua step di APPROVAL_REQUEST approved
 // → pindah ke APPROVAL_AGREEMENT
 // ============================================================
 
-func autoCompleteDisposalApprovalRequest(userID, transactionNumber string) error {
-	var total, approved int64
-	config.DB.Model(&models.TransactionApproval{}).
-		Where("transaction_number = ? AND transaction_type = ? AND approval_flow_code = ?",
-			transactionNumber, TxDisposalFlow, models.FlowDisposalApprovalRequest).
-		Count(&total)
-
-	config.DB.Model(&models.TransactionApproval{}).
-		Where("transaction_number = ? AND transaction_type = ? AND approval_flow_code = ? AND status = ?",
-			transactionNumber, TxDisposalFlow, models.FlowDisposalApprovalRequest, "approved").
-		Count(&approved)
-
-	if total == 0 || approved < total {
+func autoCompleteDisposalApprovalRequest(userID, transactionNumber string, meta dto.RequestMeta) error {
+	if !isApprovalFlowCompleted(transactionNumber, TxDisposalFlow, models.FlowDisposalApprovalRequest) {
 		return nil
 	}
 
@@ -508,7 +511,7 @@ func autoCompleteDisposalApprovalRequest(userID, transactionNumber string) error
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, nextStage,
-		models.ActionApprove, userID, nil, nil); err != nil {
+		models.ActionApprove, userID, nil, nil, meta); err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -579,19 +582,8 @@ func InitiateDisposalApprovalAgreement(userID string, transactionNumber string,
 // → pindah ke EXECUTE
 // ============================================================
 
-func autoCompleteDisposalApprovalAgreement(userID, transactionNumber string) error {
-	var total, approved int64
-	config.DB.Model(&models.TransactionApproval{}).
-		Where("transaction_number = ? AND transaction_type = ? AND approval_flow_code = ?",
-			transactionNumber, TxDisposalFlow, models.FlowDisposalApprovalAgreement).
-		Count(&total)
-
-	config.DB.Model(&models.TransactionApproval{}).
-		Where("transaction_number = ? AND transaction_type = ? AND approval_flow_code = ? AND status = ?",
-			transactionNumber, TxDisposalFlow, models.FlowDisposalApprovalAgreement, "approved").
-		Count(&approved)
-
-	if total == 0 || approved < total {
+func autoCompleteDisposalApprovalAgreement(userID, transactionNumber string, meta dto.RequestMeta) error {
+	if !isApprovalFlowCompleted(transactionNumber, TxDisposalFlow, models.FlowDisposalApprovalAgreement) {
 		return nil
 	}
 
@@ -621,7 +613,7 @@ func autoCompleteDisposalApprovalAgreement(userID, transactionNumber string) err
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, nextStage,
-		models.ActionApprove, userID, nil, nil); err != nil {
+		models.ActionApprove, userID, nil, nil, meta); err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -636,7 +628,7 @@ func autoCompleteDisposalApprovalAgreement(userID, transactionNumber string) err
 // Setelah upload + approve → pindah ke stage berikutnya
 // ============================================================
 
-func ExecuteDisposal(userID string, transactionNumber string, req dto.ExecuteDisposalRequest) (*dto.DisposalDetailResponse, error) {
+func ExecuteDisposal(userID string, transactionNumber string, req dto.ExecuteDisposalRequest, meta dto.RequestMeta) (*dto.DisposalDetailResponse, error) {
 	transaction, err := getDisposalTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -679,7 +671,7 @@ func ExecuteDisposal(userID string, transactionNumber string, req dto.ExecuteDis
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, nextStage,
-		models.ActionExecute, userID, nil, req.Notes); err != nil {
+		models.ActionExecute, userID, nil, req.Notes, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -696,7 +688,7 @@ func ExecuteDisposal(userID string, transactionNumber string, req dto.ExecuteDis
 // EXECUTE → FINANCE → TAX
 // ============================================================
 
-func ConfirmDisposalFinance(userID string, transactionNumber string, req dto.ConfirmDisposalFinanceRequest) (*dto.DisposalDetailResponse, error) {
+func ConfirmDisposalFinance(userID string, transactionNumber string, req dto.ConfirmDisposalFinanceRequest, meta dto.RequestMeta) (*dto.DisposalDetailResponse, error) {
 	transaction, err := getDisposalTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -736,7 +728,7 @@ func ConfirmDisposalFinance(userID string, transactionNumber string, req dto.Con
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, nextStage,
-		models.ActionSubmit, userID, nil, req.Notes); err != nil {
+		models.ActionSubmit, userID, nil, req.Notes, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -753,7 +745,7 @@ func ConfirmDisposalFinance(userID string, transactionNumber string, req dto.Con
 // FINANCE → TAX → ASSET_DELETION
 // ============================================================
 
-func ConfirmDisposalTax(userID string, transactionNumber string, req dto.ConfirmDisposalTaxRequest) (*dto.DisposalDetailResponse, error) {
+func ConfirmDisposalTax(userID string, transactionNumber string, req dto.ConfirmDisposalTaxRequest, meta dto.RequestMeta) (*dto.DisposalDetailResponse, error) {
 	transaction, err := getDisposalTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -793,7 +785,7 @@ func ConfirmDisposalTax(userID string, transactionNumber string, req dto.Confirm
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, nextStage,
-		models.ActionSubmit, userID, nil, req.Notes); err != nil {
+		models.ActionSubmit, userID, nil, req.Notes, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -812,7 +804,7 @@ func ConfirmDisposalTax(userID string, transactionNumber string, req dto.Confirm
 // Generate document_number per asset
 // ============================================================
 
-func ConfirmDisposalAssetDeletion(userID string, transactionNumber string, req dto.ConfirmDisposalAssetDeletionRequest) (*dto.DisposalDetailResponse, error) {
+func ConfirmDisposalAssetDeletion(userID string, transactionNumber string, req dto.ConfirmDisposalAssetDeletionRequest, meta dto.RequestMeta) (*dto.DisposalDetailResponse, error) {
 	transaction, err := getDisposalTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -841,6 +833,8 @@ func ConfirmDisposalAssetDeletion(userID string, transactionNumber string, req d
 		}
 	}()
 
+	disposalDate := time.Now()
+
 	for _, da := range disposalAssets {
 		// Generate document number per asset
 		docNumber, err := GenerateDocumentNumber(tx)
@@ -849,6 +843,18 @@ func ConfirmDisposalAssetDeletion(userID string, transactionNumber string, req d
 			return nil, fmt.Errorf("failed to generate document number: %w", err)
 		}
 
+		// Depresiasi bulan disposal dibebankan pro-rata sampai tanggal disposal sebelum nilai buku di-nol-kan
+		if err := depreciateUntilDisposal(tx, da.AssetID, disposalDate); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to depreciate disposed asset: %w", err)
+		}
+
+		before, err := loadAssetSnapshot(tx, da.AssetID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
 		// Update disposal asset → document_number + status DELETED
 		if err := tx.Model(&da).Updates(map[string]interface{}{
 			"document_number": docNumber,
@@ -858,16 +864,61 @@ func ConfirmDisposalAssetDeletion(userID string, transactionNumber string, req d
 			return nil, err
 		}
 
-		// Asset status → DISPOSED, asset_value → 0
+		// Asset status → DISPOSED
 		if err := tx.Model(&models.Asset{}).
 			Where("id = ?", da.AssetID).
-			Updates(map[string]interface{}{
-				"asset_status": models.AssetStatusDisposed,
-				"asset_value":  0,
-			}).Error; err != nil {
+			Update("asset_status", models.AssetStatusDisposed).Error; err != nil {
 			tx.Rollback()
 			return nil, fmt.Errorf("failed to update asset status: %w", err)
 		}
+
+		// Nilai buku → 0: nonaktifkan asset value aktif, buat value baru dengan book_value 0
+		if before.AssetValue != nil {
+			if err := tx.Model(&models.AssetValue{}).
+				Where("asset_id = ? AND is_active = ?", da.AssetID, true).
+				Update("is_active", false).Error; err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("failed to deactivate asset value: %w", err)
+			}
+
+			disposedStatus := models.AssetStatusDisposed
+			disposedValue := models.AssetValue{
+				AssetID:                 da.AssetID,
+				EffectiveDate:           disposalDate,
+				BookValue:               0,
+				AcquisitionValue:        before.AssetValue.AcquisitionValue,
+				AccumulatedDepreciation: before.AssetValue.AccumulatedDepreciation,
+				Condition:               before.AssetValue.Condition,
+				PhysicalStatus:          before.AssetValue.PhysicalStatus,
+				AssetStatus:             &disposedStatus,
+				IsActive:                true,
+			}
+			if err := tx.Create(&disposedValue).Error; err != nil {
+				tx.Rollback()
+				return nil, fmt.Errorf("failed to create asset value: %w", err)
+			}
+		}
+
+		after, err := loadAssetSnapshot(tx, da.AssetID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if err := recordAssetHistory(tx, AssetHistoryEntry{
+			AssetID:           da.AssetID,
+			TransactionType:   models.TransactionTypeDisposal,
+			TransactionID:     transaction.ID,
+			TransactionNumber: transactionNumber,
+			DocumentNumber:    docNumber,
+			TransactionDate:   time.Now(),
+			Before:            before,
+			After:             after,
+			ChangedBy:         userID,
+		}); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to record asset history: %w", err)
+		}
 	}
 
 	fromStage := transaction.CurrentStage
@@ -880,7 +931,7 @@ func ConfirmDisposalAssetDeletion(userID string, transactionNumber string, req d
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, nextStage,
-		models.ActionExecute, userID, nil, req.Notes); err != nil {
+		models.ActionExecute, userID, nil, req.Notes, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -889,6 +940,8 @@ func ConfirmDisposalAssetDeletion(userID string, transactionNumber string, req d
 		return nil, err
 	}
 
+	notifyTransactionStage(transactionNumber, nextStage, req.Notes)
+
 	return GetDisposalDetail(transactionNumber)
 }
 
@@ -896,7 +949,7 @@ func ConfirmDisposalAssetDeletion(userID string, transactionNumber string, req d
 // REJECT
 // ============================================================
 
-func RejectDisposal(userID string, transactionNumber string, req dto.RejectDisposalRequest) (*dto.DisposalDetailResponse, error) {
+func RejectDisposal(userID string, transactionNumber string, req dto.RejectDisposalRequest, meta dto.RequestMeta) (*dto.DisposalDetailResponse, error) {
 	transaction, err := getDisposalTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -943,7 +996,7 @@ func RejectDisposal(userID string, transactionNumber string, req dto.RejectDispo
 	reason := req.Reason
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, models.StageDisposalRejected,
-		models.ActionReject, userID, nil, &reason); err != nil {
+		models.ActionReject, userID, nil, &reason, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -954,6 +1007,8 @@ func RejectDisposal(userID string, transactionNumber string, req dto.RejectDispo
 		return nil, err
 	}
 
+	notifyTransactionStage(transactionNumber, models.StageDisposalRejected, &reason)
+
 	return GetDisposalDetail(transactionNumber)
 }
 
@@ -1042,27 +1097,7 @@ func GetDisposalDetail(transactionNumber string) (*dto.DisposalDetailResponse, e
 // ============================================================
 
 func GetAllDisposals(filter dto.DisposalListFilter) ([]dto.DisposalDetailResponse, int64, error) {
-	query := config.DB.Model(&models.Transaction{}).
-		Where("transaction_type = ?", TxDisposalFlow)
-
-	if filter.DisposalType != nil {
-		query = query.Where("disposal_type = ?", *filter.DisposalType)
-	}
-	if filter.Status != nil {
-		query = query.Where("status = ?", *filter.Status)
-	}
-	if filter.CurrentStage != nil {
-		query = query.Where("current_stage = ?", *filter.CurrentStage)
-	}
-	if filter.CreatedBy != nil {
-		query = query.Where("created_by = ?", *filter.CreatedBy)
-	}
-	if filter.StartDate != nil {
-		query = query.Where("transaction_date >= ?", *filter.StartDate)
-	}
-	if filter.EndDate != nil {
-		query = query.Where("transaction_date <= ?", *filter.EndDate)
-	}
+	query := applyDisposalListFilter(config.DB.Model(&models.Transaction{}), filter)
 
 	page := filter.Page
 	if page < 1 {
@@ -1093,6 +1128,31 @@ func GetAllDisposals(filter dto.DisposalListFilter) ([]dto.DisposalDetailRespons
 	return responses, total, nil
 }
 
+// applyDisposalListFilter - dipakai list & export
+func applyDisposalListFilter(query *gorm.DB, filter dto.DisposalListFilter) *gorm.DB {
+	query = query.Where("transactions.transaction_type = ?", TxDisposalFlow)
+
+	if filter.DisposalType != nil {
+		query = query.Where("transactions.disposal_type = ?", *filter.DisposalType)
+	}
+	if filter.Status != nil {
+		query = query.Where("transactions.status = ?", *filter.Status)
+	}
+	if filter.CurrentStage != nil {
+		query = query.Where("transactions.current_stage = ?", *filter.CurrentStage)
+	}
+	if filter.CreatedBy != nil {
+		query = query.Where("transactions.created_by = ?", *filter.CreatedBy)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("transactions.transaction_date >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("transactions.transaction_date <= ?", *filter.EndDate)
+	}
+	return query
+}
+
 // ============================================================
 // ATTACHMENT PER ASSET PER STAGE
 // ============================================================
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"backend-go/utils"
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ============================================================================
+// EMAIL NOTIFICATION
+// Channel ketiga di samping inbox & push, hanya untuk event berikut:
+//   - approval_request     → approver step yang baru terbuka
+//   - transaction_rejected → creator, beserta notes penolakan
+//   - attachment_rejected  → uploader lampiran yang ditolak ReviewAttachment
+// Template per jenis transaksi + bahasa user (utils/email_templates).
+// User bisa opt out per event (default opt in).
+// Mailer default: SMTP kalau SMTP_HOST diisi, selain itu FakeMailer (hanya log).
+// ============================================================================
+
+// emailEventTypes - event yang dikirim juga lewat email
+var emailEventTypes = []string{
+	NotificationApprovalRequest,
+	NotificationRejected,
+	NotificationAttachmentRejected,
+}
+
+const emailSendTimeout = 30 * time.Second
+
+var (
+	mailerMu sync.Mutex
+	mailer   utils.Mailer
+)
+
+// SetMailer - ganti mailer, misal utils.NewFakeMailer() untuk testing
+func SetMailer(m utils.Mailer) {
+	mailerMu.Lock()
+	defer mailerMu.Unlock()
+	mailer = m
+}
+
+func getMailer() utils.Mailer {
+	mailerMu.Lock()
+	defer mailerMu.Unlock()
+
+	if mailer == nil {
+		mailer = utils.NewMailerFromEnv()
+	}
+	return mailer
+}
+
+// ============================================================================
+// PREFERENCES
+// ============================================================================
+
+func GetEmailPreferences(userID string) (*dto.EmailPreferencesResponse, error) {
+	var user models.User
+	if err := config.DB.First(&user, "id = ?", userID).Error; err != nil {
+		return nil, err
+	}
+
+	var prefs []models.EmailNotificationPreference
+	if err := config.DB.Where("user_id = ?", userID).Find(&prefs).Error; err != nil {
+		return nil, err
+	}
+
+	enabled := map[string]bool{}
+	for _, p := range prefs {
+		enabled[p.EventType] = p.Enabled
+	}
+
+	response := &dto.EmailPreferencesResponse{
+		Email:       user.Email,
+		Language:    utils.NormalizeEmailLanguage(user.Language),
+		Preferences: make([]dto.EmailPreferenceResponse, 0, len(emailEventTypes)),
+	}
+	for _, eventType := range emailEventTypes {
+		value, ok := enabled[eventType]
+		response.Preferences = append(response.Preferences, dto.EmailPreferenceResponse{
+			EventType: eventType,
+			Enabled:   !ok || value,
+		})
+	}
+
+	return response, nil
+}
+
+func UpdateEmailPreferences(userID string, req dto.UpdateEmailPreferencesRequest) (*dto.EmailPreferencesResponse, error) {
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if req.Language != nil {
+			if err := tx.Model(&models.User{}).
+				Where("id = ?", userID).
+				Update("language", *req.Language).Error; err != nil {
+				return err
+			}
+		}
+
+		for _, item := range req.Preferences {
+			pref := models.EmailNotificationPreference{
+				UserID:    userID,
+				EventType: item.EventType,
+				Enabled:   *item.Enabled,
+			}
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "event_type"}},
+				DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+			}).Create(&pref).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return GetEmailPreferences(userID)
+}
+
+// ============================================================================
+// DISPATCH
+// ============================================================================
+
+// emailUsers - render & kirim email ke user yang opt in (async)
+func emailUsers(userIDs []string, event notificationEvent) {
+	if !containsString(emailEventTypes, event.Type) || event.TransactionType == "" {
+		return
+	}
+
+	userIDs = uniqueStrings(userIDs)
+	if len(userIDs) == 0 {
+		return
+	}
+
+	var users []models.User
+	if err := config.DB.
+		Where("id IN ? AND status = ?", userIDs, "active").
+		Find(&users).Error; err != nil {
+		log.Printf("[Mail] failed to load recipients: %v", err)
+		return
+	}
+
+	var optedOut []string
+	config.DB.Model(&models.EmailNotificationPreference{}).
+		Where("user_id IN ? AND event_type = ? AND enabled = ?", userIDs, event.Type, false).
+		Pluck("user_id", &optedOut)
+
+	data := utils.EmailTemplateData{
+		TransactionNumber: event.TransactionNumber,
+		TransactionType:   event.TransactionType,
+		Stage:             event.Stage,
+		StepName:          event.StepName,
+		Notes:             event.Notes,
+		AttachmentName:    event.AttachmentName,
+		Link:              transactionLink(event),
+	}
+
+	var messages []utils.EmailMessage
+	for _, user := range users {
+		if user.Email == "" || containsString(optedOut, user.ID) {
+			continue
+		}
+
+		data.RecipientName = user.Fullname
+		subject, text, html, err := utils.RenderEmail(user.Language, event.TransactionType, event.Type, data)
+		if err != nil {
+			log.Printf("[Mail] failed to render %s for %s: %v", event.Type, user.Email, err)
+			continue
+		}
+
+		messages = append(messages, utils.EmailMessage{
+			To:       []string{user.Email},
+			Subject:  subject,
+			TextBody: text,
+			HTMLBody: html,
+		})
+	}
+
+	if len(messages) == 0 {
+		return
+	}
+
+	sender := getMailer()
+	go func() {
+		for _, msg := range messages {
+			ctx, cancel := context.WithTimeout(context.Background(), emailSendTimeout)
+			if err := sender.Send(ctx, msg); err != nil {
+				log.Printf("[Mail] failed to send %q to %s: %v", msg.Subject, strings.Join(msg.To, ", "), err)
+			}
+			cancel()
+		}
+	}()
+}
+
+// transactionLink - deep link ke halaman transaksi, kosong kalau APP_BASE_URL tidak diisi
+func transactionLink(event notificationEvent) string {
+	base := strings.TrimRight(os.Getenv("APP_BASE_URL"), "/")
+	if base == "" || event.TransactionNumber == "" {
+		return ""
+	}
+
+	query := url.Values{}
+	query.Set("type", event.TransactionType)
+	if event.Stage != "" {
+		query.Set("stage", event.Stage)
+	}
+	return fmt.Sprintf("%s/transactions/%s?%s", base, url.PathEscape(event.TransactionNumber), query.Encode())
+}
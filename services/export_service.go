@@ -0,0 +1,428 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"backend-go/utils"
+	"database/sql"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================
+// EXPORT LIST
+// Filter sama dengan endpoint list (applyXxxListFilter) tapi tanpa pagination.
+// Data dibaca baris per baris dari cursor DB (Rows + ScanRows) dan langsung
+// diteruskan ke ExportWriter. Branch / kategori / user di-join supaya file
+// berisi nama, bukan ID.
+// ============================================================
+
+// ============================================================
+// ASSET REGISTER
+// ============================================================
+
+var AssetExportColumns = []utils.ExportColumn{
+	{Header: "Asset Number", Type: utils.ExportText, Width: 18},
+	{Header: "Asset Name", Type: utils.ExportText, Width: 30},
+	{Header: "Category", Type: utils.ExportText, Width: 18},
+	{Header: "Branch Code", Type: utils.ExportText, Width: 11},
+	{Header: "Branch Name", Type: utils.ExportText, Width: 22},
+	{Header: "Location", Type: utils.ExportText, Width: 18},
+	{Header: "Grouping", Type: utils.ExportText, Width: 12},
+	{Header: "Brand", Type: utils.ExportText, Width: 12},
+	{Header: "IO Number", Type: utils.ExportText, Width: 14},
+	{Header: "Asset Status", Type: utils.ExportText, Width: 13},
+	{Header: "Condition", Type: utils.ExportText, Width: 10},
+	{Header: "Acquisition Value (IDR)", Type: utils.ExportMoney, Width: 17},
+	{Header: "Accumulated Depreciation (IDR)", Type: utils.ExportMoney, Width: 17},
+	{Header: "Book Value (IDR)", Type: utils.ExportMoney, Width: 17},
+	{Header: "Value Effective Date", Type: utils.ExportDate, Width: 12},
+}
+
+type assetExportRow struct {
+	AssetNumber             string
+	AssetName               string
+	CategoryName            *string
+	BranchCode              *string
+	BranchName              *string
+	Location                *string
+	Grouping                *string
+	Brand                   *string
+	IONumber                *string
+	AssetStatus             string
+	Condition               *string
+	AcquisitionValue        *float64
+	AccumulatedDepreciation *float64
+	BookValue               *float64
+	EffectiveDate           *time.Time
+}
+
+func ExportAssets(filter dto.AssetListFilter, writer utils.ExportWriter) error {
+	query := applyAssetListFilter(config.DB.Model(&models.Asset{}), filter).
+		Select(`assets.asset_number, assets.asset_name, asset_categories.category_name,
+			assets.branch_code, branches.branch_name, assets.location, assets.grouping,
+			assets.brand, assets.io_number, assets.asset_status,
+			av.condition AS ` + "`condition`" + `, av.acquisition_value, av.accumulated_depreciation,
+			av.book_value, av.effective_date`).
+		Joins("LEFT JOIN asset_categories ON asset_categories.id = assets.category_id").
+		Joins("LEFT JOIN branches ON branches.branch_code = assets.branch_code AND branches.deleted_at IS NULL").
+		Joins(`LEFT JOIN asset_values av ON av.id = (
+			SELECT MAX(v.id) FROM asset_values v WHERE v.asset_id = assets.id AND v.is_active = 1)`).
+		Order("assets.asset_number ASC")
+
+	return streamExportRows(query, writer, func(row *assetExportRow) []interface{} {
+		return []interface{}{
+			row.AssetNumber, row.AssetName, row.CategoryName, row.BranchCode, row.BranchName,
+			row.Location, row.Grouping, row.Brand, row.IONumber, row.AssetStatus, row.Condition,
+			row.AcquisitionValue, row.AccumulatedDepreciation, row.BookValue, row.EffectiveDate,
+		}
+	})
+}
+
+// ============================================================
+// TRANSACTIONS
+// ============================================================
+
+var TransactionExportColumns = []utils.ExportColumn{
+	{Header: "Transaction Number", Type: utils.ExportText, Width: 22},
+	{Header: "Transaction Type", Type: utils.ExportText, Width: 14},
+	{Header: "Transaction Date", Type: utils.ExportDate, Width: 12},
+	{Header: "Status", Type: utils.ExportText, Width: 11},
+	{Header: "Current Stage", Type: utils.ExportText, Width: 18},
+	{Header: "Notes", Type: utils.ExportText, Width: 30},
+	{Header: "Created By", Type: utils.ExportText, Width: 20},
+	{Header: "Created At", Type: utils.ExportDateTime, Width: 16},
+	{Header: "Approved By", Type: utils.ExportText, Width: 20},
+	{Header: "Approved At", Type: utils.ExportDateTime, Width: 16},
+}
+
+type transactionExportRow struct {
+	TransactionNumber string
+	TransactionType   string
+	TransactionDate   time.Time
+	Status            string
+	CurrentStage      string
+	Notes             *string
+	CreatorName       *string
+	CreatedAt         time.Time
+	ApproverName      *string
+	ApprovedAt        *time.Time
+}
+
+func ExportTransactions(filter dto.TransactionListFilter, writer utils.ExportWriter) error {
+	query := applyTransactionListFilter(config.DB.Model(&models.Transaction{}), filter).
+		Select(`transactions.transaction_number, transactions.transaction_type, transactions.transaction_date,
+			transactions.status, transactions.current_stage, transactions.notes,
+			COALESCE(creator.fullname, transactions.created_by) AS creator_name, transactions.created_at,
+			COALESCE(approver.fullname, transactions.approved_by) AS approver_name, transactions.approved_at`).
+		Joins("LEFT JOIN users creator ON creator.id = transactions.created_by").
+		Joins("LEFT JOIN users approver ON approver.id = transactions.approved_by").
+		Order("transactions.created_at DESC")
+
+	return streamExportRows(query, writer, func(row *transactionExportRow) []interface{} {
+		return []interface{}{
+			row.TransactionNumber, row.TransactionType, row.TransactionDate, row.Status,
+			row.CurrentStage, row.Notes, row.CreatorName, row.CreatedAt, row.ApproverName, row.ApprovedAt,
+		}
+	})
+}
+
+// ============================================================
+// DISPOSAL — satu baris per asset, draft tanpa asset tetap muncul
+// ============================================================
+
+var DisposalExportColumns = []utils.ExportColumn{
+	{Header: "Transaction Number", Type: utils.ExportText, Width: 22},
+	{Header: "Transaction Date", Type: utils.ExportDate, Width: 12},
+	{Header: "Disposal Type", Type: utils.ExportText, Width: 11},
+	{Header: "Status", Type: utils.ExportText, Width: 11},
+	{Header: "Current Stage", Type: utils.ExportText, Width: 18},
+	{Header: "Asset Number", Type: utils.ExportText, Width: 18},
+	{Header: "Asset Name", Type: utils.ExportText, Width: 26},
+	{Header: "Category", Type: utils.ExportText, Width: 16},
+	{Header: "Branch Code", Type: utils.ExportText, Width: 11},
+	{Header: "Branch Name", Type: utils.ExportText, Width: 20},
+	{Header: "Book Value (IDR)", Type: utils.ExportMoney, Width: 16},
+	{Header: "Sale Value (IDR)", Type: utils.ExportMoney, Width: 16},
+	{Header: "Disposal Reason", Type: utils.ExportText, Width: 24},
+	{Header: "Asset Disposal Status", Type: utils.ExportText, Width: 12},
+	{Header: "Document Number", Type: utils.ExportText, Width: 16},
+	{Header: "Created By", Type: utils.ExportText, Width: 20},
+}
+
+type disposalExportRow struct {
+	TransactionNumber string
+	TransactionDate   time.Time
+	DisposalType      *string
+	Status            string
+	CurrentStage      string
+	AssetNumber       *string
+	AssetName         *string
+	CategoryName      *string
+	BranchCode        *string
+	BranchName        *string
+	BookValue         *float64
+	SaleValue         *float64
+	DisposalReason    *string
+	AssetStatus       *string
+	DocumentNumber    *string
+	CreatorName       *string
+}
+
+func ExportDisposals(filter dto.DisposalListFilter, writer utils.ExportWriter) error {
+	query := applyDisposalListFilter(config.DB.Model(&models.Transaction{}), filter).
+		Select(`transactions.transaction_number, transactions.transaction_date,
+			COALESCE(tda.disposal_type, transactions.disposal_type) AS disposal_type,
+			transactions.status, transactions.current_stage,
+			tda.asset_number, assets.asset_name, asset_categories.category_name,
+			assets.branch_code, branches.branch_name, av.book_value,
+			tda.sale_value, tda.disposal_reason, tda.status AS asset_status, tda.document_number,
+			COALESCE(creator.fullname, transactions.created_by) AS creator_name`).
+		Joins("LEFT JOIN transaction_disposal_assets tda ON tda.transaction_id = transactions.id").
+		Joins("LEFT JOIN assets ON assets.id = tda.asset_id").
+		Joins("LEFT JOIN asset_categories ON asset_categories.id = assets.category_id").
+		Joins("LEFT JOIN branches ON branches.branch_code = assets.branch_code AND branches.deleted_at IS NULL").
+		// Asset yang sudah dihapus tidak punya asset value aktif → ambil yang terakhir
+		Joins(`LEFT JOIN asset_values av ON av.id = (
+			SELECT MAX(v.id) FROM asset_values v WHERE v.asset_id = tda.asset_id)`).
+		Joins("LEFT JOIN users creator ON creator.id = transactions.created_by").
+		Order("transactions.created_at DESC, tda.asset_number ASC")
+
+	return streamExportRows(query, writer, func(row *disposalExportRow) []interface{} {
+		return []interface{}{
+			row.TransactionNumber, row.TransactionDate, row.DisposalType, row.Status, row.CurrentStage,
+			row.AssetNumber, row.AssetName, row.CategoryName, row.BranchCode, row.BranchName,
+			row.BookValue, row.SaleValue, row.DisposalReason, row.AssetStatus, row.DocumentNumber,
+			row.CreatorName,
+		}
+	})
+}
+
+// ============================================================
+// MUTASI — satu baris per asset, draft tanpa asset tetap muncul
+// ============================================================
+
+var MutationExportColumns = []utils.ExportColumn{
+	{Header: "Transaction Number", Type: utils.ExportText, Width: 22},
+	{Header: "Transaction Date", Type: utils.ExportDate, Width: 12},
+	{Header: "Status", Type: utils.ExportText, Width: 11},
+	{Header: "Current Stage", Type: utils.ExportText, Width: 18},
+	{Header: "Asset Number", Type: utils.ExportText, Width: 18},
+	{Header: "Asset Name", Type: utils.ExportText, Width: 26},
+	{Header: "Category", Type: utils.ExportText, Width: 16},
+	{Header: "From Branch Code", Type: utils.ExportText, Width: 11},
+	{Header: "From Branch Name", Type: utils.ExportText, Width: 20},
+	{Header: "To Branch Code", Type: utils.ExportText, Width: 11},
+	{Header: "To Branch Name", Type: utils.ExportText, Width: 20},
+	{Header: "From Location", Type: utils.ExportText, Width: 16},
+	{Header: "To Location", Type: utils.ExportText, Width: 16},
+	{Header: "Book Value (IDR)", Type: utils.ExportMoney, Width: 16},
+	{Header: "Asset Mutation Status", Type: utils.ExportText, Width: 12},
+	{Header: "Document Number", Type: utils.ExportText, Width: 16},
+	{Header: "Created By", Type: utils.ExportText, Width: 20},
+}
+
+type mutationExportRow struct {
+	TransactionNumber string
+	TransactionDate   time.Time
+	Status            string
+	CurrentStage      string
+	AssetNumber       *string
+	AssetName         *string
+	CategoryName      *string
+	FromBranchCode    *string
+	FromBranchName    *string
+	ToBranchCode      *string
+	ToBranchName      *string
+	FromLocation      *string
+	ToLocation        *string
+	BookValue         *float64
+	AssetStatus       *string
+	DocumentNumber    *string
+	CreatorName       *string
+}
+
+func ExportMutations(filter MutationListFilter, writer utils.ExportWriter) error {
+	query := applyMutationListFilter(config.DB.Model(&models.Transaction{}), filter).
+		Select(`transactions.transaction_number, transactions.transaction_date,
+			transactions.status, transactions.current_stage,
+			tma.asset_number, assets.asset_name,
+			COALESCE(asset_categories.category_name, mutation_categories.category_name) AS category_name,
+			tma.from_branch_code, from_branches.branch_name AS from_branch_name,
+			COALESCE(tma.to_branch_code, transactions.mutation_to_branch_code) AS to_branch_code,
+			to_branches.branch_name AS to_branch_name,
+			tma.from_location, tma.to_location, av.book_value,
+			tma.status AS asset_status, tma.document_number,
+			COALESCE(creator.fullname, transactions.created_by) AS creator_name`).
+		Joins("LEFT JOIN transaction_mutation_assets tma ON tma.transaction_id = transactions.id").
+		Joins("LEFT JOIN assets ON assets.id = tma.asset_id").
+		Joins("LEFT JOIN asset_categories ON asset_categories.id = assets.category_id").
+		Joins("LEFT JOIN asset_categories mutation_categories ON mutation_categories.id = transactions.mutation_category_id").
+		Joins("LEFT JOIN branches from_branches ON from_branches.branch_code = tma.from_branch_code AND from_branches.deleted_at IS NULL").
+		Joins(`LEFT JOIN branches to_branches ON to_branches.branch_code = COALESCE(tma.to_branch_code, transactions.mutation_to_branch_code)
+			AND to_branches.deleted_at IS NULL`).
+		Joins(`LEFT JOIN asset_values av ON av.id = (
+			SELECT MAX(v.id) FROM asset_values v WHERE v.asset_id = tma.asset_id AND v.is_active = 1)`).
+		Joins("LEFT JOIN users creator ON creator.id = transactions.created_by").
+		Order("transactions.created_at DESC, tma.asset_number ASC")
+
+	return streamExportRows(query, writer, func(row *mutationExportRow) []interface{} {
+		return []interface{}{
+			row.TransactionNumber, row.TransactionDate, row.Status, row.CurrentStage,
+			row.AssetNumber, row.AssetName, row.CategoryName,
+			row.FromBranchCode, row.FromBranchName, row.ToBranchCode, row.ToBranchName,
+			row.FromLocation, row.ToLocation, row.BookValue, row.AssetStatus, row.DocumentNumber,
+			row.CreatorName,
+		}
+	})
+}
+
+// ============================================================
+// DEPRESIASI BULANAN
+// ============================================================
+
+var MonthlyDepreciationExportColumns = []utils.ExportColumn{
+	{Header: "Period", Type: utils.ExportText, Width: 9},
+	{Header: "Book", Type: utils.ExportText, Width: 11},
+	{Header: "Asset Number", Type: utils.ExportText, Width: 18},
+	{Header: "Asset Name", Type: utils.ExportText, Width: 26},
+	{Header: "Category", Type: utils.ExportText, Width: 16},
+	{Header: "Branch Code", Type: utils.ExportText, Width: 11},
+	{Header: "Branch Name", Type: utils.ExportText, Width: 20},
+	{Header: "Method", Type: utils.ExportText, Width: 14},
+	{Header: "Beginning Book Value (IDR)", Type: utils.ExportMoney, Width: 17},
+	{Header: "Depreciation (IDR)", Type: utils.ExportMoney, Width: 15},
+	{Header: "Beginning Accumulated (IDR)", Type: utils.ExportMoney, Width: 17},
+	{Header: "Ending Accumulated (IDR)", Type: utils.ExportMoney, Width: 17},
+	{Header: "Ending Book Value (IDR)", Type: utils.ExportMoney, Width: 17},
+	{Header: "Locked", Type: utils.ExportText, Width: 8},
+}
+
+type monthlyDepreciationExportRow struct {
+	Period                           string
+	BookCode                         string
+	AssetNumber                      *string
+	AssetName                        *string
+	CategoryName                     *string
+	BranchCode                       *string
+	BranchName                       *string
+	CalculationMethod                *string
+	BeginningBookValue               float64
+	DepreciationAmount               float64
+	BeginningAccumulatedDepreciation float64
+	EndingAccumulatedDepreciation    float64
+	EndingBookValue                  float64
+	IsLocked                         bool
+}
+
+func ExportMonthlyDepreciationCalculations(period, bookCode string, writer utils.ExportWriter) error {
+	query := config.DB.Model(&models.MonthlyDepreciationCalculation{}).
+		Select(`monthly_depreciation_calculations.period, monthly_depreciation_calculations.book_code, assets.asset_number, assets.asset_name,
+			asset_categories.category_name, assets.branch_code, branches.branch_name,
+			monthly_depreciation_calculations.calculation_method,
+			monthly_depreciation_calculations.beginning_book_value,
+			monthly_depreciation_calculations.depreciation_amount,
+			monthly_depreciation_calculations.beginning_accumulated_depreciation,
+			monthly_depreciation_calculations.ending_accumulated_depreciation,
+			monthly_depreciation_calculations.ending_book_value,
+			monthly_depreciation_calculations.is_locked`).
+		Joins("LEFT JOIN assets ON assets.id = monthly_depreciation_calculations.asset_id").
+		Joins("LEFT JOIN asset_categories ON asset_categories.id = assets.category_id").
+		Joins("LEFT JOIN branches ON branches.branch_code = assets.branch_code AND branches.deleted_at IS NULL").
+		Where("monthly_depreciation_calculations.period = ?", period).
+		Order("monthly_depreciation_calculations.book_code ASC, assets.asset_number ASC")
+	if bookCode != "" {
+		query = query.Where("monthly_depreciation_calculations.book_code = ?", bookCode)
+	}
+
+	return streamExportRows(query, writer, func(row *monthlyDepreciationExportRow) []interface{} {
+		return []interface{}{
+			row.Period, row.BookCode, row.AssetNumber, row.AssetName, row.CategoryName, row.BranchCode, row.BranchName,
+			row.CalculationMethod, row.BeginningBookValue, row.DepreciationAmount,
+			row.BeginningAccumulatedDepreciation, row.EndingAccumulatedDepreciation,
+			row.EndingBookValue, row.IsLocked,
+		}
+	})
+}
+
+// ============================================================
+// FORECAST DEPRESIASI
+// Hasil proyeksi dihitung di memori, jadi ditulis dari response (bukan cursor DB)
+// ============================================================
+
+var DepreciationScheduleExportColumns = []utils.ExportColumn{
+	{Header: "Period", Type: utils.ExportText, Width: 9},
+	{Header: "Source", Type: utils.ExportText, Width: 10},
+	{Header: "Beginning Book Value (IDR)", Type: utils.ExportMoney, Width: 17},
+	{Header: "Depreciation (IDR)", Type: utils.ExportMoney, Width: 15},
+	{Header: "Ending Accumulated (IDR)", Type: utils.ExportMoney, Width: 17},
+	{Header: "Ending Book Value (IDR)", Type: utils.ExportMoney, Width: 17},
+}
+
+func ExportAssetDepreciationForecast(forecast *dto.AssetDepreciationForecastResponse, writer utils.ExportWriter) error {
+	for _, row := range forecast.Schedule {
+		if err := writer.WriteRow(
+			row.Period, row.Source, row.BeginningBookValue, row.DepreciationAmount,
+			row.EndingAccumulatedDepreciation, row.EndingBookValue,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var DepreciationForecastSummaryExportColumns = []utils.ExportColumn{
+	{Header: "Period", Type: utils.ExportText, Width: 9},
+	{Header: "Category", Type: utils.ExportText, Width: 18},
+	{Header: "Branch Code", Type: utils.ExportText, Width: 11},
+	{Header: "Branch Name", Type: utils.ExportText, Width: 22},
+	{Header: "Asset Count", Type: utils.ExportNumber, Width: 10},
+	{Header: "Actual (IDR)", Type: utils.ExportMoney, Width: 17},
+	{Header: "Forecast (IDR)", Type: utils.ExportMoney, Width: 17},
+	{Header: "Total (IDR)", Type: utils.ExportMoney, Width: 17},
+}
+
+func ExportDepreciationForecastSummary(summary *dto.DepreciationForecastSummaryResponse, writer utils.ExportWriter) error {
+	for _, group := range summary.Groups {
+		for _, month := range group.Months {
+			if err := writer.WriteRow(
+				month.Period, group.CategoryName, group.BranchCode, group.BranchName, group.AssetCount,
+				month.ActualDepreciation, month.ForecastDepreciation, month.TotalDepreciation,
+			); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ============================================================
+// HELPER
+// ============================================================
+
+// streamExportRows - baca cursor satu baris per satu, scan ke T lalu tulis ke writer
+func streamExportRows[T any](query *gorm.DB, writer utils.ExportWriter, values func(*T) []interface{}) error {
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row T
+		if err := query.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := writer.WriteRow(values(&row)...); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	return nil
+}
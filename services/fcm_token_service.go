@@ -0,0 +1,76 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// RegisterFCMToken - simpan / aktifkan ulang token device user.
+// Token yang sama dipakai user lain (ganti akun di device yang sama) dipindah ke user ini,
+// token lama di device yang sama dinonaktifkan
+func RegisterFCMToken(userID string, req dto.RegisterFCMTokenRequest) (*models.FCMToken, error) {
+	var token models.FCMToken
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if req.DeviceID != "" {
+			if err := tx.Model(&models.FCMToken{}).
+				Where("user_id = ? AND device_id = ? AND token <> ?", userID, req.DeviceID, req.Token).
+				Update("is_active", false).Error; err != nil {
+				return err
+			}
+		}
+
+		err := tx.Where("token = ?", req.Token).First(&token).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			token = models.FCMToken{
+				UserID:   userID,
+				Token:    req.Token,
+				DeviceID: req.DeviceID,
+				Platform: req.Platform,
+				IsActive: true,
+			}
+			return tx.Create(&token).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		token.UserID = userID
+		token.DeviceID = req.DeviceID
+		token.Platform = req.Platform
+		token.IsActive = true
+		return tx.Save(&token).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// UnregisterFCMToken - nonaktifkan token (by token atau device_id), misal saat logout dari device
+func UnregisterFCMToken(userID string, req dto.UnregisterFCMTokenRequest) error {
+	if req.Token == "" && req.DeviceID == "" {
+		return errors.New("token or device_id is required")
+	}
+
+	query := config.DB.Model(&models.FCMToken{}).Where("user_id = ?", userID)
+	if req.Token != "" {
+		query = query.Where("token = ?", req.Token)
+	} else {
+		query = query.Where("device_id = ?", req.DeviceID)
+	}
+
+	result := query.Update("is_active", false)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("fcm token not found")
+	}
+	return nil
+}
@@ -288,16 +288,17 @@ func mapAssetValuesToResponse(values []models.AssetValue) []dto.AssetValueRespon
 
 func mapAssetHistoryToResponse(history models.AssetHistory) dto.AssetHistoryResponse {
 	response := dto.AssetHistoryResponse{
-		ID:              history.ID,
-		AssetID:         history.AssetID,
-		TransactionType: history.TransactionType,
-		TransactionID:   history.TransactionID, // FIX: sudah *uint
-		DocumentNumber:  history.DocumentNumber,
-		TransactionDate: history.TransactionDate,
-		BeforeData:      history.BeforeData,
-		AfterData:       history.AfterData,
-		ChangedBy:       history.ChangedBy,
-		CreatedAt:       history.CreatedAt,
+		ID:                history.ID,
+		AssetID:           history.AssetID,
+		TransactionType:   history.TransactionType,
+		TransactionID:     history.TransactionID, // FIX: sudah *uint
+		TransactionNumber: history.TransactionNumber,
+		DocumentNumber:    history.DocumentNumber,
+		TransactionDate:   history.TransactionDate,
+		BeforeData:        history.BeforeData,
+		AfterData:         history.AfterData,
+		ChangedBy:         history.ChangedBy,
+		CreatedAt:         history.CreatedAt,
 	}
 
 	if history.Asset != nil {
@@ -324,6 +325,7 @@ func mapAssetHistoriesToResponse(histories []models.AssetHistory) []dto.AssetHis
 func mapDepreciationSettingToResponse(setting models.DepreciationSetting) dto.DepreciationSettingResponse {
 	return dto.DepreciationSettingResponse{
 		ID:                 setting.ID,
+		BookCode:           setting.BookCode,
 		SettingType:        setting.SettingType,
 		ReferenceID:        setting.ReferenceID,
 		ReferenceValue:     setting.ReferenceValue,
@@ -331,6 +333,9 @@ func mapDepreciationSettingToResponse(setting models.DepreciationSetting) dto.De
 		DepreciationPeriod: setting.DepreciationPeriod,
 		UsefulLifeMonths:   setting.UsefulLifeMonths,
 		DepreciationRate:   setting.DepreciationRate,
+		ResidualType:       setting.ResidualType,
+		ResidualValue:      setting.ResidualValue,
+		TaxGroup:           setting.TaxGroup,
 		StartDate:          setting.StartDate,
 		EndDate:            setting.EndDate,
 		IsActive:           setting.IsActive,
@@ -352,6 +357,7 @@ func mapMonthlyDepreciationToResponse(calc models.MonthlyDepreciationCalculation
 		ID:                               calc.ID,
 		AssetID:                          calc.AssetID,
 		Period:                           calc.Period,
+		BookCode:                         calc.BookCode,
 		CalculationDate:                  calc.CalculationDate,
 		BeginningBookValue:               calc.BeginningBookValue,
 		DepreciationAmount:               calc.DepreciationAmount,
@@ -341,7 +341,27 @@ func GetSidebarMenus(userID string) ([]dto.MenuResponse, error) {
 	fmt.Printf("Menu IDs found: %v\n", menuIDs)
 	fmt.Printf("Total menus retrieved: %d\n", len(menus))
 
-	return mapMenusToResponseWithPermissions(menus, mergedPermissions), nil
+	response := mapMenusToResponseWithPermissions(menus, mergedPermissions)
+
+	// 5. Badge unread untuk menu notification centre
+	if unread, err := CountUnreadNotifications(userID); err == nil {
+		applyNotificationBadge(response, unread)
+	}
+
+	return response, nil
+}
+
+// notificationMenuPath - path menu notification centre yang diberi badge unread
+const notificationMenuPath = "/notifications"
+
+func applyNotificationBadge(menus []dto.MenuResponse, unread int64) {
+	for i := range menus {
+		if menus[i].Path != nil && *menus[i].Path == notificationMenuPath {
+			count := unread
+			menus[i].Badge = &count
+		}
+		applyNotificationBadge(menus[i].Children, unread)
+	}
 }
 
 // ─── Helpers ─────────────────────────────────────────────────────────────────
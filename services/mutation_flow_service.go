@@ -280,7 +280,7 @@ func RemoveAssetFromMutation(userID string, transactionNumber string, req dto.Re
 // DRAFT → APPROVAL
 // ============================================================
 
-func SubmitMutation(userID string, transactionNumber string, req dto.SubmitMutationRequest) (*dto.MutationDetailResponse, error) {
+func SubmitMutation(userID string, transactionNumber string, req dto.SubmitMutationRequest, meta dto.RequestMeta) (*dto.MutationDetailResponse, error) {
 	transaction, err := getMutationTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -327,7 +327,7 @@ func SubmitMutation(userID string, transactionNumber string, req dto.SubmitMutat
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, models.StageApproval,
-		models.ActionSubmit, userID, nil, req.Notes); err != nil {
+		models.ActionSubmit, userID, nil, req.Notes, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -385,7 +385,7 @@ func InitiateMutationApproval(userID string, transactionNumber string, req dto.I
 // Update branch_code asset + generate document number
 // ============================================================
 
-func ExecuteMutation(userID string, transactionNumber string, req dto.ExecuteMutationRequest) (*dto.MutationDetailResponse, error) {
+func ExecuteMutation(userID string, transactionNumber string, req dto.ExecuteMutationRequest, meta dto.RequestMeta) (*dto.MutationDetailResponse, error) {
 	transaction, err := getMutationTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -424,6 +424,12 @@ func ExecuteMutation(userID string, transactionNumber string, req dto.ExecuteMut
 			return nil, fmt.Errorf("failed to generate document number: %w", err)
 		}
 
+		before, err := loadAssetSnapshot(tx, ma.AssetID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
 		// Update mutation asset — document number + status EXECUTED
 		if err := tx.Model(&ma).Updates(map[string]interface{}{
 			"document_number": docNumber,
@@ -443,6 +449,27 @@ func ExecuteMutation(userID string, transactionNumber string, req dto.ExecuteMut
 			tx.Rollback()
 			return nil, fmt.Errorf("failed to update asset branch: %w", err)
 		}
+
+		after, err := loadAssetSnapshot(tx, ma.AssetID)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+
+		if err := recordAssetHistory(tx, AssetHistoryEntry{
+			AssetID:           ma.AssetID,
+			TransactionType:   models.TransactionTypeMutation,
+			TransactionID:     transaction.ID,
+			TransactionNumber: transactionNumber,
+			DocumentNumber:    docNumber,
+			TransactionDate:   time.Now(),
+			Before:            before,
+			After:             after,
+			ChangedBy:         userID,
+		}); err != nil {
+			tx.Rollback()
+			return nil, fmt.Errorf("failed to record asset history: %w", err)
+		}
 	}
 
 	fromStage := transaction.CurrentStage
@@ -453,7 +480,7 @@ func ExecuteMutation(userID string, transactionNumber string, req dto.ExecuteMut
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, models.StageFinished,
-		models.ActionExecute, userID, nil, req.Notes); err != nil {
+		models.ActionExecute, userID, nil, req.Notes, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -462,6 +489,8 @@ func ExecuteMutation(userID string, transactionNumber string, req dto.ExecuteMut
 		return nil, err
 	}
 
+	notifyTransactionStage(transactionNumber, models.StageFinished, req.Notes)
+
 	return GetMutationDetail(transactionNumber)
 }
 
@@ -469,7 +498,7 @@ func ExecuteMutation(userID string, transactionNumber string, req dto.ExecuteMut
 // REJECT
 // ============================================================
 
-func RejectMutation(userID string, transactionNumber string, req dto.RejectMutationRequest) (*dto.MutationDetailResponse, error) {
+func RejectMutation(userID string, transactionNumber string, req dto.RejectMutationRequest, meta dto.RequestMeta) (*dto.MutationDetailResponse, error) {
 	transaction, err := getMutationTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -516,7 +545,7 @@ func RejectMutation(userID string, transactionNumber string, req dto.RejectMutat
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, models.StageRejected,
-		models.ActionReject, userID, nil, &req.Reason); err != nil {
+		models.ActionReject, userID, nil, &req.Reason, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -527,6 +556,8 @@ func RejectMutation(userID string, transactionNumber string, req dto.RejectMutat
 		return nil, err
 	}
 
+	notifyTransactionStage(transactionNumber, models.StageRejected, &req.Reason)
+
 	return GetMutationDetail(transactionNumber)
 }
 
@@ -656,24 +687,7 @@ type MutationListFilter struct {
 }
 
 func GetAllMutationDrafts(filter MutationListFilter) ([]dto.MutationDetailResponse, int64, error) {
-	query := config.DB.Model(&models.Transaction{}).
-		Where("transaction_type = ?", TxMutationFlow)
-
-	if filter.Status != nil {
-		query = query.Where("status = ?", *filter.Status)
-	}
-	if filter.CurrentStage != nil {
-		query = query.Where("current_stage = ?", *filter.CurrentStage)
-	}
-	if filter.CreatedBy != nil {
-		query = query.Where("created_by = ?", *filter.CreatedBy)
-	}
-	if filter.StartDate != nil {
-		query = query.Where("transaction_date >= ?", *filter.StartDate)
-	}
-	if filter.EndDate != nil {
-		query = query.Where("transaction_date <= ?", *filter.EndDate)
-	}
+	query := applyMutationListFilter(config.DB.Model(&models.Transaction{}), filter)
 
 	page := filter.Page
 	if page < 1 {
@@ -704,6 +718,28 @@ func GetAllMutationDrafts(filter MutationListFilter) ([]dto.MutationDetailRespon
 	return responses, total, nil
 }
 
+// applyMutationListFilter - dipakai list & export
+func applyMutationListFilter(query *gorm.DB, filter MutationListFilter) *gorm.DB {
+	query = query.Where("transactions.transaction_type = ?", TxMutationFlow)
+
+	if filter.Status != nil {
+		query = query.Where("transactions.status = ?", *filter.Status)
+	}
+	if filter.CurrentStage != nil {
+		query = query.Where("transactions.current_stage = ?", *filter.CurrentStage)
+	}
+	if filter.CreatedBy != nil {
+		query = query.Where("transactions.created_by = ?", *filter.CreatedBy)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("transactions.transaction_date >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("transactions.transaction_date <= ?", *filter.EndDate)
+	}
+	return query
+}
+
 // ============================================================
 // ATTACHMENT PER ASSET
 // ============================================================
@@ -981,7 +1017,7 @@ func mapMutationAttachmentToResponse(att models.TransactionMutationAttachment) d
 // Upload dokumen serah terima per asset
 // ============================================================
 
-func ConfirmMutationReceiving(userID string, transactionNumber string, notes *string) (*dto.MutationDetailResponse, error) {
+func ConfirmMutationReceiving(userID string, transactionNumber string, notes *string, meta dto.RequestMeta) (*dto.MutationDetailResponse, error) {
 	transaction, err := getMutationTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -1025,7 +1061,7 @@ func ConfirmMutationReceiving(userID string, transactionNumber string, notes *st
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, models.StageMutationExecute,
-		models.ActionGR, userID, nil, notes); err != nil {
+		models.ActionGR, userID, nil, notes, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -1040,21 +1076,12 @@ func ConfirmMutationReceiving(userID string, transactionNumber string, notes *st
 // autoCompleteMutationApproval — dipanggil otomatis setelah approve step
 // Kalau semua step approved → pindah stage ke EXECUTE_MUTATION
 // Eksekusi dilakukan manual oleh PIC Asset yang berwenang
-func autoCompleteMutationApproval(userID, transactionNumber, transactionType string) error {
+func autoCompleteMutationApproval(userID, transactionNumber, transactionType string, meta dto.RequestMeta) error {
 	if transactionType != TxMutationFlow {
 		return nil
 	}
 
-	var total, approved int64
-	config.DB.Model(&models.TransactionApproval{}).
-		Where("transaction_number = ? AND transaction_type = ?", transactionNumber, transactionType).
-		Count(&total)
-
-	config.DB.Model(&models.TransactionApproval{}).
-		Where("transaction_number = ? AND transaction_type = ? AND status = ?", transactionNumber, transactionType, "approved").
-		Count(&approved)
-
-	if total == 0 || approved < total {
+	if !isApprovalFlowCompleted(transactionNumber, transactionType, "") {
 		return nil
 	}
 
@@ -1083,10 +1110,15 @@ func autoCompleteMutationApproval(userID, transactionNumber, transactionType str
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, models.StageMutationReceiving,
-		models.ActionApprove, userID, nil, nil); err != nil {
+		models.ActionApprove, userID, nil, nil, meta); err != nil {
 		tx.Rollback()
 		return err
 	}
 
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	notifyTransactionStage(transactionNumber, models.StageMutationReceiving, nil)
+	return nil
 }
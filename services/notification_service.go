@@ -0,0 +1,649 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"backend-go/utils"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// NOTIFICATION DISPATCHER
+// Dua channel:
+//   - inbox (tabel notifications) → notification centre di web / app
+//   - push (FCM token aktif user)  → sender default FCM HTTP v1 kalau
+//     FCM_CREDENTIALS_FILE diisi, selain itu FakePushSender (hanya log).
+//     Pengiriman async setelah transaksi DB commit.
+//
+// Event:
+//   - step approval terbuka       → approver (user / role di branch transaksi + delegate), inbox + push
+//   - step approval selesai       → creator, inbox
+//   - perpindahan stage           → creator, inbox
+//   - transaksi REJECTED/FINISHED → creator, inbox + push
+//   - mutasi MUTATION_RECEIVING   → user branch tujuan, inbox + push
+//   - procurement GR              → user branch tujuan item, inbox + push
+//   - lampiran ditolak            → uploader, inbox + push
+// Event approval_request, transaction_rejected & attachment_rejected juga
+// dikirim lewat email (lihat email_notification_service.go)
+// ============================================================================
+
+const (
+	NotificationApprovalRequest = "approval_request"
+	NotificationApprovalClosed  = "approval_closed"
+	NotificationStageChanged    = "stage_changed"
+	NotificationRejected        = "transaction_rejected"
+	NotificationFinished        = "transaction_finished"
+	NotificationReceiving       = "mutation_receiving"
+	NotificationGoodsReceipt    = "procurement_gr"
+
+	NotificationAttachmentRejected = "attachment_rejected"
+)
+
+// notificationEvent - isi satu notifikasi, dipakai untuk inbox maupun push
+type notificationEvent struct {
+	Type                  string
+	Title                 string
+	Body                  string
+	TransactionNumber     string
+	TransactionType       string
+	Stage                 string
+	TransactionApprovalID string
+
+	// Detail tambahan untuk template email
+	StepName       string
+	Notes          string
+	AttachmentName string
+}
+
+func (e notificationEvent) pushMessage() utils.PushMessage {
+	data := map[string]string{"type": e.Type}
+	if e.TransactionNumber != "" {
+		data["transaction_number"] = e.TransactionNumber
+		data["transaction_type"] = e.TransactionType
+	}
+	if e.Stage != "" {
+		data["stage"] = e.Stage
+	}
+	if e.TransactionApprovalID != "" {
+		data["transaction_approval_id"] = e.TransactionApprovalID
+	}
+	return utils.PushMessage{Title: e.Title, Body: e.Body, Data: data}
+}
+
+var (
+	pushSenderMu sync.Mutex
+	pushSender   utils.PushSender
+
+	// step yang baru saja dinotifikasi — cegah notifikasi ganda saat cascade
+	// (approve → auto approve → initiate flow berikutnya)
+	recentApprovalNotifications sync.Map
+)
+
+const approvalNotificationTTL = 10 * time.Minute
+
+// SetPushSender - ganti sender, misal utils.NewFakePushSender() untuk testing
+func SetPushSender(sender utils.PushSender) {
+	pushSenderMu.Lock()
+	defer pushSenderMu.Unlock()
+	pushSender = sender
+}
+
+func getPushSender() utils.PushSender {
+	pushSenderMu.Lock()
+	defer pushSenderMu.Unlock()
+
+	if pushSender != nil {
+		return pushSender
+	}
+
+	fcm, err := utils.NewFCMSenderFromEnv()
+	switch {
+	case err != nil:
+		log.Printf("[Push] FCM disabled: %v", err)
+		pushSender = utils.NewFakePushSender()
+	case fcm == nil:
+		log.Println("[Push] FCM_CREDENTIALS_FILE not set, using fake push sender")
+		pushSender = utils.NewFakePushSender()
+	default:
+		pushSender = fcm
+	}
+	return pushSender
+}
+
+// dispatchNotification - simpan ke inbox lalu kirim push (+ email untuk event tertentu)
+func dispatchNotification(userIDs []string, event notificationEvent) {
+	if err := saveInboxNotifications(config.DB, userIDs, event); err != nil {
+		log.Printf("[Notification] failed to save inbox: %v", err)
+	}
+	notifyUsers(userIDs, event.pushMessage())
+	emailUsers(userIDs, event)
+}
+
+// saveInboxNotifications - satu baris inbox per user, db bisa berupa tx
+func saveInboxNotifications(db *gorm.DB, userIDs []string, event notificationEvent) error {
+	userIDs = uniqueStrings(userIDs)
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	notifications := make([]models.Notification, 0, len(userIDs))
+	for _, userID := range userIDs {
+		if userID == SystemActorID {
+			continue
+		}
+		notifications = append(notifications, models.Notification{
+			UserID:                userID,
+			Type:                  event.Type,
+			Title:                 event.Title,
+			Body:                  optionalString(event.Body),
+			TransactionNumber:     optionalString(event.TransactionNumber),
+			TransactionType:       optionalString(event.TransactionType),
+			Stage:                 optionalString(event.Stage),
+			TransactionApprovalID: optionalString(event.TransactionApprovalID),
+		})
+	}
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	return db.Create(&notifications).Error
+}
+
+// notifyUsers - kirim push ke semua device aktif user (async)
+func notifyUsers(userIDs []string, msg utils.PushMessage) {
+	userIDs = uniqueStrings(userIDs)
+	if len(userIDs) == 0 {
+		return
+	}
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[Push] panic: %v", r)
+			}
+		}()
+
+		var tokens []models.FCMToken
+		if err := config.DB.
+			Where("user_id IN ? AND is_active = ?", userIDs, true).
+			Find(&tokens).Error; err != nil {
+			log.Printf("[Push] failed to load tokens: %v", err)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		sender := getPushSender()
+		for _, token := range tokens {
+			err := sender.Send(ctx, token.Token, msg)
+			if errors.Is(err, utils.ErrPushTokenInvalid) {
+				config.DB.Model(&models.FCMToken{}).Where("id = ?", token.ID).Update("is_active", false)
+				continue
+			}
+			if err != nil {
+				log.Printf("[Push] failed to send to user %s: %v", token.UserID, err)
+			}
+		}
+	}()
+}
+
+// ============================================================================
+// EVENT - APPROVAL STEP TERBUKA
+// ============================================================================
+
+// snapshotOpenApprovals - step pending yang saat ini bisa diproses.
+// Key memuat approver supaya eskalasi (approver berganti) dianggap step baru
+func snapshotOpenApprovals(transactionNumber, transactionType string) map[string]bool {
+	snapshot := map[string]bool{}
+	for _, a := range loadOpenApprovals(transactionNumber, transactionType) {
+		snapshot[approvalNotificationKey(a)] = true
+	}
+	return snapshot
+}
+
+// notifyOpenedApprovals - notifikasi approver untuk step yang terbuka setelah snapshot "before"
+func notifyOpenedApprovals(transactionNumber, transactionType string, before map[string]bool) {
+	for _, approval := range loadOpenApprovals(transactionNumber, transactionType) {
+		key := approvalNotificationKey(approval)
+		if before[key] {
+			continue
+		}
+		if !markApprovalNotified(key) {
+			continue
+		}
+
+		stepName := ""
+		if approval.ApprovalFlowStep != nil {
+			stepName = approval.ApprovalFlowStep.StepName
+		}
+
+		dispatchNotification(approvalRecipients(approval), notificationEvent{
+			Type:                  NotificationApprovalRequest,
+			Title:                 "Approval required",
+			Body:                  fmt.Sprintf("%s %s is waiting for your approval (%s)", transactionType, transactionNumber, stepName),
+			TransactionNumber:     transactionNumber,
+			TransactionType:       transactionType,
+			Stage:                 currentTransactionStage(transactionNumber),
+			TransactionApprovalID: approval.ID,
+			StepName:              stepName,
+		})
+	}
+}
+
+// markApprovalNotified - false kalau key sudah dinotifikasi dalam approvalNotificationTTL.
+// Entry yang sudah lewat TTL dibuang setiap kali menyimpan, supaya map tidak tumbuh terus
+func markApprovalNotified(key string) bool {
+	now := time.Now()
+	if last, ok := recentApprovalNotifications.Load(key); ok && now.Sub(last.(time.Time)) < approvalNotificationTTL {
+		return false
+	}
+
+	recentApprovalNotifications.Range(func(k, v interface{}) bool {
+		if now.Sub(v.(time.Time)) >= approvalNotificationTTL {
+			recentApprovalNotifications.Delete(k)
+		}
+		return true
+	})
+	recentApprovalNotifications.Store(key, now)
+	return true
+}
+
+// notifyApprovalClosed - inbox creator saat step approved / rejected / skipped
+func notifyApprovalClosed(approval models.TransactionApproval, status, actorID string, notes *string) {
+	var transaction models.Transaction
+	if err := config.DB.
+		Where("transaction_number = ? AND transaction_type = ?", approval.TransactionNumber, approval.TransactionType).
+		First(&transaction).Error; err != nil {
+		return
+	}
+	if transaction.CreatedBy == actorID {
+		return
+	}
+
+	stepName := ""
+	if approval.ApprovalFlowStep != nil {
+		stepName = approval.ApprovalFlowStep.StepName
+	}
+
+	body := fmt.Sprintf("Step %q of %s %s was %s", stepName, approval.TransactionType, approval.TransactionNumber, status)
+	if notes != nil && *notes != "" {
+		body += ": " + *notes
+	}
+
+	if err := saveInboxNotifications(config.DB, []string{transaction.CreatedBy}, notificationEvent{
+		Type:                  NotificationApprovalClosed,
+		Title:                 "Approval " + status,
+		Body:                  body,
+		TransactionNumber:     approval.TransactionNumber,
+		TransactionType:       approval.TransactionType,
+		Stage:                 transaction.CurrentStage,
+		TransactionApprovalID: approval.ID,
+	}); err != nil {
+		log.Printf("[Notification] failed to save inbox: %v", err)
+	}
+}
+
+func currentTransactionStage(transactionNumber string) string {
+	var stage string
+	config.DB.Model(&models.Transaction{}).
+		Where("transaction_number = ?", transactionNumber).
+		Select("current_stage").
+		Scan(&stage)
+	return stage
+}
+
+func loadOpenApprovals(transactionNumber, transactionType string) []models.TransactionApproval {
+	var approvals []models.TransactionApproval
+	if err := config.DB.
+		Preload("ApprovalFlow").
+		Preload("ApprovalFlowStep").
+		Where("transaction_number = ? AND transaction_type = ?", transactionNumber, transactionType).
+		Find(&approvals).Error; err != nil {
+		return nil
+	}
+
+	byFlow := map[string][]models.TransactionApproval{}
+	for _, a := range approvals {
+		byFlow[a.FlowID] = append(byFlow[a.FlowID], a)
+	}
+
+	var open []models.TransactionApproval
+	for _, flowApprovals := range byFlow {
+		groups := groupApprovals(flowApprovals)
+		for _, a := range flowApprovals {
+			if a.Status != "pending" || a.StatusView != "visible" || a.ApprovalFlowStep == nil {
+				continue
+			}
+			if enforcesGroupOrder(a.ApprovalFlow.ApprovalWay) && !isApprovalGroupOpen(groups, a.ApprovalFlowStep.StepOrder) {
+				continue
+			}
+			open = append(open, a)
+		}
+	}
+	return open
+}
+
+func approvalNotificationKey(approval models.TransactionApproval) string {
+	key := approval.ID
+	if approval.ApproverUserID != nil {
+		key += "|u:" + *approval.ApproverUserID
+	}
+	if approval.ApproverRoleID != nil {
+		key += "|r:" + *approval.ApproverRoleID
+	}
+	return key
+}
+
+// approvalRecipients - user yang bisa memproses step ini, termasuk delegate yang sedang aktif
+func approvalRecipients(approval models.TransactionApproval) []string {
+	var candidates []string
+	if approval.ApproverUserID != nil {
+		candidates = append(candidates, *approval.ApproverUserID)
+	} else if approval.ApproverRoleID != nil {
+		config.DB.Model(&models.UserRole{}).
+			Where("role_id = ?", *approval.ApproverRoleID).
+			Pluck("user_id", &candidates)
+	}
+
+	var recipients []string
+	for _, userID := range candidates {
+		if validateApproverBranch(userID, approval.TransactionNumber, approval.TransactionType) != nil {
+			continue
+		}
+		recipients = append(recipients, userID)
+
+		var delegations []models.ApprovalDelegation
+		now := time.Now()
+		config.DB.
+			Where("delegator_id = ? AND is_active = ? AND start_date <= ? AND end_date >= ?", userID, true, now, now).
+			Find(&delegations)
+		for _, d := range delegations {
+			if delegationCovers(d, approval.ApprovalFlow.FlowCode, approval.TransactionType) {
+				recipients = append(recipients, d.DelegateID)
+			}
+		}
+	}
+
+	return recipients
+}
+
+// ============================================================================
+// EVENT - PERUBAHAN STAGE TRANSAKSI
+// Inbox dibuat di recordStage (ikut tx, semua stage).
+// Push dikirim setelah commit hanya untuk stage penting lewat notifyTransactionStage.
+// ============================================================================
+
+// pushStages - stage yang juga dikirim sebagai push notification
+var pushStages = map[string]bool{
+	models.StageRejected:          true,
+	models.StageFinished:          true,
+	models.StageMutationReceiving: true,
+	models.StageGR:                true,
+	models.StageOpnameReconciled:  true,
+}
+
+// notifyTransactionStage - push notification setelah commit perpindahan stage
+func notifyTransactionStage(transactionNumber, toStage string, notes *string) {
+	if !pushStages[toStage] {
+		return
+	}
+
+	var transaction models.Transaction
+	if err := config.DB.Where("transaction_number = ?", transactionNumber).First(&transaction).Error; err != nil {
+		return
+	}
+
+	event, recipients := stageNotification(config.DB, transaction, toStage, notes)
+	notifyUsers(recipients, event.pushMessage())
+	emailUsers(recipients, event)
+}
+
+// recordStageNotifications - inbox untuk perpindahan stage, dipanggil recordStage di dalam tx
+func recordStageNotifications(tx *gorm.DB, transactionID uint, toStage, actorID string, notes *string) error {
+	var transaction models.Transaction
+	if err := tx.First(&transaction, transactionID).Error; err != nil {
+		return err
+	}
+
+	event, recipients := stageNotification(tx, transaction, toStage, notes)
+
+	// Actor tidak perlu notifikasi untuk aksinya sendiri
+	filtered := make([]string, 0, len(recipients))
+	for _, userID := range recipients {
+		if userID != actorID {
+			filtered = append(filtered, userID)
+		}
+	}
+
+	return saveInboxNotifications(tx, filtered, event)
+}
+
+// stageNotification - isi notifikasi + penerima untuk stage tujuan
+func stageNotification(db *gorm.DB, transaction models.Transaction, toStage string, notes *string) (notificationEvent, []string) {
+	event := notificationEvent{
+		Type:              NotificationStageChanged,
+		Title:             "Transaction updated",
+		Body:              fmt.Sprintf("%s %s moved to stage %s", transaction.TransactionType, transaction.TransactionNumber, toStage),
+		TransactionNumber: transaction.TransactionNumber,
+		TransactionType:   transaction.TransactionType,
+		Stage:             toStage,
+	}
+	recipients := []string{transaction.CreatedBy}
+
+	switch {
+	case toStage == models.StageRejected:
+		event.Type = NotificationRejected
+		event.Title = "Transaction rejected"
+		event.Body = fmt.Sprintf("%s %s has been rejected", transaction.TransactionType, transaction.TransactionNumber)
+		if notes != nil && *notes != "" {
+			event.Notes = *notes
+		} else if transaction.Notes != nil {
+			event.Notes = *transaction.Notes
+		}
+		if event.Notes != "" {
+			event.Body += ": " + event.Notes
+		}
+
+	case toStage == models.StageFinished || toStage == models.StageOpnameReconciled:
+		event.Type = NotificationFinished
+		event.Title = "Transaction completed"
+		event.Body = fmt.Sprintf("%s %s has been completed", transaction.TransactionType, transaction.TransactionNumber)
+
+	case toStage == models.StageMutationReceiving && transaction.TransactionType == TxMutationFlow:
+		event.Type = NotificationReceiving
+		event.Title = "Incoming asset mutation"
+		event.Body = fmt.Sprintf("Mutation %s is heading to your branch and waits for receiving confirmation", transaction.TransactionNumber)
+		recipients = nil
+		if transaction.MutationToBranchCode != nil {
+			recipients = branchUserIDs(db, []string{*transaction.MutationToBranchCode})
+		}
+
+	case toStage == models.StageGR && transaction.TransactionType == TxProcurement:
+		event.Type = NotificationGoodsReceipt
+		event.Title = "Goods receipt required"
+		event.Body = fmt.Sprintf("Assets from procurement %s are ready for goods receipt at your branch", transaction.TransactionNumber)
+		recipients = branchUserIDs(db, procurementBranchCodes(db, transaction.ID))
+	}
+
+	return event, recipients
+}
+
+// procurementBranchCodes - branch tujuan item procurement
+func procurementBranchCodes(db *gorm.DB, transactionID uint) []string {
+	var branchCodes []string
+	db.Model(&models.TransactionProcurementDetail{}).
+		Joins("JOIN transaction_procurements ON transaction_procurements.id = transaction_procurement_details.transaction_procurement_id").
+		Where("transaction_procurements.transaction_id = ?", transactionID).
+		Distinct().
+		Pluck("transaction_procurement_details.branch_code", &branchCodes)
+	if len(branchCodes) == 0 {
+		db.Model(&models.TransactionProcurement{}).
+			Where("transaction_id = ? AND branch_code <> ''", transactionID).
+			Distinct().
+			Pluck("branch_code", &branchCodes)
+	}
+	return branchCodes
+}
+
+// branchUserIDs - user yang punya akses aktif ke branch
+func branchUserIDs(db *gorm.DB, branchCodes []string) []string {
+	if len(branchCodes) == 0 {
+		return nil
+	}
+
+	var userIDs []string
+	db.Model(&models.UserBranch{}).
+		Joins("JOIN branchs ON branchs.id = user_branchs.branch_id").
+		Where("branchs.branch_code IN ? AND user_branchs.is_active = ?", branchCodes, true).
+		Distinct().
+		Pluck("user_branchs.user_id", &userIDs)
+	return userIDs
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func uniqueStrings(values []string) []string {
+	seen := map[string]bool{}
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	return result
+}
+
+// ============================================================================
+// EVENT - LAMPIRAN DITOLAK
+// ============================================================================
+
+// notifyAttachmentRejected - uploader diminta upload ulang lampiran yang ditolak
+func notifyAttachmentRejected(attachment models.TransactionAttachment) {
+	name := attachment.FileName
+	if attachment.AttachmentConfig != nil {
+		name = attachment.AttachmentConfig.AttachmentType
+	}
+
+	event := notificationEvent{
+		Type:              NotificationAttachmentRejected,
+		Title:             "Attachment rejected",
+		Body:              fmt.Sprintf("Attachment %s on %s was rejected", name, attachment.TransactionNumber),
+		TransactionNumber: attachment.TransactionNumber,
+		TransactionType:   attachment.TransactionType,
+		Stage:             attachment.Stage,
+		AttachmentName:    name,
+	}
+	if attachment.RejectionReason != nil {
+		event.Notes = *attachment.RejectionReason
+		event.Body += ": " + event.Notes
+	}
+
+	dispatchNotification([]string{attachment.UploadedBy}, event)
+}
+
+// ============================================================================
+// INBOX - notification centre per user
+// ============================================================================
+
+// GetUserNotifications - inbox user terbaru dulu, paginated
+func GetUserNotifications(userID string, filter dto.NotificationFilter) ([]dto.NotificationResponse, int64, error) {
+	query := config.DB.Model(&models.Notification{}).Where("user_id = ?", userID)
+	if filter.UnreadOnly {
+		query = query.Where("is_read = ?", false)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []models.Notification
+	if err := query.
+		Order("created_at DESC").
+		Offset((filter.Page - 1) * filter.Limit).
+		Limit(filter.Limit).
+		Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	responses := make([]dto.NotificationResponse, 0, len(notifications))
+	for _, n := range notifications {
+		responses = append(responses, mapNotificationToResponse(n))
+	}
+	return responses, total, nil
+}
+
+// MarkNotificationRead - hanya pemilik notifikasi yang bisa tandai dibaca
+func MarkNotificationRead(userID, id string) error {
+	var notification models.Notification
+	if err := config.DB.Where("id = ? AND user_id = ?", id, userID).First(&notification).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("notification not found")
+		}
+		return err
+	}
+
+	if notification.IsRead {
+		return nil
+	}
+
+	return config.DB.Model(&notification).Updates(map[string]interface{}{
+		"is_read": true,
+		"read_at": time.Now(),
+	}).Error
+}
+
+// MarkAllNotificationsRead returns jumlah notifikasi yang ditandai dibaca
+func MarkAllNotificationsRead(userID string) (int64, error) {
+	result := config.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Updates(map[string]interface{}{
+			"is_read": true,
+			"read_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// CountUnreadNotifications - dipakai endpoint unread-count dan badge sidebar
+func CountUnreadNotifications(userID string) (int64, error) {
+	var count int64
+	err := config.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND is_read = ?", userID, false).
+		Count(&count).Error
+	return count, err
+}
+
+func mapNotificationToResponse(n models.Notification) dto.NotificationResponse {
+	return dto.NotificationResponse{
+		ID:                    n.ID,
+		Type:                  n.Type,
+		Title:                 n.Title,
+		Body:                  n.Body,
+		TransactionNumber:     n.TransactionNumber,
+		TransactionType:       n.TransactionType,
+		Stage:                 n.Stage,
+		TransactionApprovalID: n.TransactionApprovalID,
+		IsRead:                n.IsRead,
+		ReadAt:                n.ReadAt,
+		CreatedAt:             n.CreatedAt,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"backend-go/config"
 	"backend-go/dto"
 	"backend-go/models"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -99,8 +100,9 @@ func checkAttachmentCanProceed(transactionNumber, transactionType, stage, branch
 // STAGE HELPERS
 // ============================================================
 
-// recordStage mencatat perpindahan stage ke transaction_stages
-func recordStage(tx *gorm.DB, transactionID uint, transactionNumber, fromStage, toStage, action, actorID string, actorName *string, notes *string) error {
+// recordStage mencatat perpindahan stage ke transaction_stages.
+// meta (IP, user agent, request ID) disimpan di kolom metadata, kosong untuk aksi sistem
+func recordStage(tx *gorm.DB, transactionID uint, transactionNumber, fromStage, toStage, action, actorID string, actorName *string, notes *string, meta dto.RequestMeta) error {
 	var from *string
 	if fromStage != "" {
 		f := fromStage
@@ -116,9 +118,28 @@ func recordStage(tx *gorm.DB, transactionID uint, transactionNumber, fromStage,
 		ActorID:           actorID,
 		ActorName:         actorName,
 		Notes:             notes,
+		Metadata:          requestMetaJSON(meta),
 	}
 
-	return tx.Create(&stage).Error
+	if err := tx.Create(&stage).Error; err != nil {
+		return err
+	}
+
+	// Inbox ikut tx supaya tidak ada notifikasi untuk stage yang di-rollback
+	return recordStageNotifications(tx, transactionID, toStage, actorID, notes)
+}
+
+// requestMetaJSON - nil kalau tidak ada request (aksi sistem)
+func requestMetaJSON(meta dto.RequestMeta) *string {
+	if meta.IsZero() {
+		return nil
+	}
+	raw, err := json.Marshal(meta)
+	if err != nil {
+		return nil
+	}
+	value := string(raw)
+	return &value
 }
 
 // stageToStatus mapping stage ke status transaksi
@@ -131,6 +152,11 @@ var stageToStatus = map[string]string{
 	models.StageGR:                "PROCESSING",
 	models.StageFinished:          models.TransactionStatusApproved,
 	models.StageRejected:          models.TransactionStatusRejected,
+
+	// Stock opname
+	models.StageOpnameCounting:   models.TransactionStatusProcessing,
+	models.StageOpnameReview:     models.TransactionStatusPending,
+	models.StageOpnameReconciled: models.TransactionStatusApproved,
 }
 
 // updateTransactionStage update current_stage & status di tabel transactions
@@ -16,7 +16,7 @@ import (
 // DRAFT → VERIFIKASI_ASET
 // ============================================================
 
-func SubmitProcurement(userID string, transactionNumber string, req dto.SubmitProcurementRequest) (*dto.ProcurementDetailWithStageResponse, error) {
+func SubmitProcurement(userID string, transactionNumber string, req dto.SubmitProcurementRequest, meta dto.RequestMeta) (*dto.ProcurementDetailWithStageResponse, error) {
 	transaction, err := getProcurementTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -62,7 +62,7 @@ func SubmitProcurement(userID string, transactionNumber string, req dto.SubmitPr
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, models.StageAssetVerification,
-		models.ActionSubmit, userID, nil, req.Notes); err != nil {
+		models.ActionSubmit, userID, nil, req.Notes, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -81,7 +81,7 @@ func SubmitProcurement(userID string, transactionNumber string, req dto.SubmitPr
 // Syarat: branch PIC Asset harus sesuai branch transaksi
 // ============================================================
 
-func VerifyProcurement(userID string, branchCode string, transactionNumber string, req dto.VerifyProcurementRequest) (*dto.ProcurementDetailWithStageResponse, error) {
+func VerifyProcurement(userID string, branchCode string, transactionNumber string, req dto.VerifyProcurementRequest, meta dto.RequestMeta) (*dto.ProcurementDetailWithStageResponse, error) {
 	transaction, err := getProcurementTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -165,7 +165,7 @@ func VerifyProcurement(userID string, branchCode string, transactionNumber strin
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, models.StageApproval,
-		models.ActionVerify, userID, nil, req.Notes); err != nil {
+		models.ActionVerify, userID, nil, req.Notes, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -223,7 +223,7 @@ func InitiateProcurementApproval(userID string, transactionNumber string, req dt
 
 // CompleteProcurementApproval dipanggil setelah semua approval step approved
 // Transisi APPROVAL → PROSES_BUDGET
-func CompleteProcurementApproval(userID string, transactionNumber string) (*dto.ProcurementDetailWithStageResponse, error) {
+func CompleteProcurementApproval(userID string, transactionNumber string, meta dto.RequestMeta) (*dto.ProcurementDetailWithStageResponse, error) {
 	transaction, err := getProcurementTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -257,7 +257,7 @@ func CompleteProcurementApproval(userID string, transactionNumber string) (*dto.
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, models.StageProcessBudget,
-		models.ActionApprove, userID, nil, nil); err != nil {
+		models.ActionApprove, userID, nil, nil, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -275,7 +275,7 @@ func CompleteProcurementApproval(userID string, transactionNumber string) (*dto.
 // PIC Budget generate nomor IO berdasarkan branch
 // ============================================================
 
-func ProcessProcurementBudget(userID string, transactionNumber string, req dto.ProcessBudgetRequest) (*dto.ProcurementDetailWithStageResponse, error) {
+func ProcessProcurementBudget(userID string, transactionNumber string, req dto.ProcessBudgetRequest, meta dto.RequestMeta) (*dto.ProcurementDetailWithStageResponse, error) {
 	transaction, err := getProcurementTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -364,7 +364,7 @@ func ProcessProcurementBudget(userID string, transactionNumber string, req dto.P
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, models.StageExecuteAsset,
-		models.ActionProcessBudget, userID, nil, req.Notes); err != nil {
+		models.ActionProcessBudget, userID, nil, req.Notes, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -383,7 +383,7 @@ func ProcessProcurementBudget(userID string, transactionNumber string, req dto.P
 // Asset status = PENDING_RECEIPT
 // ============================================================
 
-func ExecuteProcurementAsset(userID string, transactionNumber string, req dto.ExecuteAssetRequest) (*dto.ProcurementDetailWithStageResponse, error) {
+func ExecuteProcurementAsset(userID string, transactionNumber string, req dto.ExecuteAssetRequest, meta dto.RequestMeta) (*dto.ProcurementDetailWithStageResponse, error) {
 	transaction, err := getProcurementTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -524,6 +524,20 @@ func ExecuteProcurementAsset(userID string, transactionNumber string, req dto.Ex
 					return nil, fmt.Errorf("failed to create asset acquisition: %w", err)
 				}
 
+				if err := recordAssetHistory(tx, AssetHistoryEntry{
+					AssetID:           asset.ID,
+					TransactionType:   models.TransactionTypeAcquisition,
+					TransactionID:     transaction.ID,
+					TransactionNumber: transaction.TransactionNumber,
+					DocumentNumber:    documentNumber,
+					TransactionDate:   time.Now(),
+					After:             &AssetSnapshot{Asset: &asset},
+					ChangedBy:         userID,
+				}); err != nil {
+					tx.Rollback()
+					return nil, fmt.Errorf("failed to record asset history: %w", err)
+				}
+
 				// AssetValue dibuat saat GR, bukan saat eksekusi
 				// karena nilai asset mulai berlaku setelah barang diterima
 			}
@@ -538,7 +552,7 @@ func ExecuteProcurementAsset(userID string, transactionNumber string, req dto.Ex
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, models.StageGR,
-		models.ActionExecute, userID, nil, req.Notes); err != nil {
+		models.ActionExecute, userID, nil, req.Notes, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -547,6 +561,8 @@ func ExecuteProcurementAsset(userID string, transactionNumber string, req dto.Ex
 		return nil, err
 	}
 
+	notifyTransactionStage(transactionNumber, models.StageGR, req.Notes)
+
 	return GetProcurementDetailWithStage(transactionNumber)
 }
 
@@ -556,7 +572,7 @@ func ExecuteProcurementAsset(userID string, transactionNumber string, req dto.Ex
 // Setelah semua item GR → status transaksi = FINISHED
 // ============================================================
 
-func CreateAssetGR(userID string, transactionNumber string, req dto.CreateGRRequest) (*dto.AssetGRResponse, error) {
+func CreateAssetGR(userID string, transactionNumber string, req dto.CreateGRRequest, meta dto.RequestMeta) (*dto.AssetGRResponse, error) {
 	transaction, err := getProcurementTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -612,6 +628,12 @@ func CreateAssetGR(userID string, transactionNumber string, req dto.CreateGRRequ
 
 	now := time.Now()
 
+	before, err := loadAssetSnapshot(tx, asset.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
 	// Create GR record
 	gr := models.AssetGR{
 		TransactionID:     transaction.ID,
@@ -647,7 +669,7 @@ func CreateAssetGR(userID string, transactionNumber string, req dto.CreateGRRequ
 	// Create initial AssetValue — effective_date = tanggal GR
 	// book_value = acquisition_value = harga beli, accumulated_depreciation = 0
 	var acquisition models.AssetAcquisition
-	config.DB.Where("asset_id = ?", asset.ID).First(&acquisition)
+	tx.Where("asset_id = ?", asset.ID).First(&acquisition)
 
 	acquisitionValue := acquisition.AcquisitionValue
 	assetValue := models.AssetValue{
@@ -665,6 +687,27 @@ func CreateAssetGR(userID string, transactionNumber string, req dto.CreateGRRequ
 		return nil, fmt.Errorf("failed to create asset value: %w", err)
 	}
 
+	after, err := loadAssetSnapshot(tx, asset.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := recordAssetHistory(tx, AssetHistoryEntry{
+		AssetID:           asset.ID,
+		TransactionType:   models.TransactionTypeAcquisition,
+		TransactionID:     transaction.ID,
+		TransactionNumber: transactionNumber,
+		DocumentNumber:    acquisition.DocumentNumber,
+		TransactionDate:   grDate,
+		Before:            before,
+		After:             after,
+		ChangedBy:         userID,
+	}); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to record asset history: %w", err)
+	}
+
 	// Cek apakah semua asset di transaksi ini sudah GR
 	// Pakai config.DB (bukan tx) untuk count yang akurat karena GR baru
 	// sudah di-commit via tx.Create di atas
@@ -680,7 +723,8 @@ func CreateAssetGR(userID string, transactionNumber string, req dto.CreateGRRequ
 		Count(&totalGR)
 
 	// +1 karena GR baru belum ter-commit ke DB (masih dalam tx)
-	if totalAssets > 0 && totalGR+1 >= totalAssets {
+	finished := totalAssets > 0 && totalGR+1 >= totalAssets
+	if finished {
 		// Semua sudah GR → update stage ke FINISHED
 		fromStage := transaction.CurrentStage
 		if err := updateTransactionStage(tx, transaction, models.StageFinished); err != nil {
@@ -691,7 +735,7 @@ func CreateAssetGR(userID string, transactionNumber string, req dto.CreateGRRequ
 		notes := "All assets received"
 		if err := recordStage(tx, transaction.ID, transactionNumber,
 			fromStage, models.StageFinished,
-			models.ActionGR, userID, nil, &notes); err != nil {
+			models.ActionGR, userID, nil, &notes, meta); err != nil {
 			tx.Rollback()
 			return nil, err
 		}
@@ -701,6 +745,10 @@ func CreateAssetGR(userID string, transactionNumber string, req dto.CreateGRRequ
 		return nil, err
 	}
 
+	if finished {
+		notifyTransactionStage(transactionNumber, models.StageFinished, nil)
+	}
+
 	response := mapAssetGRToResponse(gr)
 	return &response, nil
 }
@@ -710,7 +758,7 @@ func CreateAssetGR(userID string, transactionNumber string, req dto.CreateGRRequ
 // Bisa dilakukan di semua stage kecuali DRAFT & FINISHED
 // ============================================================
 
-func RejectProcurement(userID string, transactionNumber string, req dto.RejectProcurementRequest) (*dto.ProcurementDetailWithStageResponse, error) {
+func RejectProcurement(userID string, transactionNumber string, req dto.RejectProcurementRequest, meta dto.RequestMeta) (*dto.ProcurementDetailWithStageResponse, error) {
 	transaction, err := getProcurementTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -737,7 +785,7 @@ func RejectProcurement(userID string, transactionNumber string, req dto.RejectPr
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, models.StageRejected,
-		models.ActionReject, userID, nil, &req.Reason); err != nil {
+		models.ActionReject, userID, nil, &req.Reason, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -749,6 +797,8 @@ func RejectProcurement(userID string, transactionNumber string, req dto.RejectPr
 		return nil, err
 	}
 
+	notifyTransactionStage(transactionNumber, models.StageRejected, &req.Reason)
+
 	return GetProcurementDetailWithStage(transactionNumber)
 }
 
@@ -759,7 +809,7 @@ func RejectProcurement(userID string, transactionNumber string, req dto.RejectPr
 // Kalau stage lain → langsung ke stage tersebut
 // ============================================================
 
-func ReviseProcurement(userID string, transactionNumber string, req dto.ReviseProcurementRequest) (*dto.ProcurementDetailWithStageResponse, error) {
+func ReviseProcurement(userID string, transactionNumber string, req dto.ReviseProcurementRequest, meta dto.RequestMeta) (*dto.ProcurementDetailWithStageResponse, error) {
 	transaction, err := getProcurementTransaction(transactionNumber)
 	if err != nil {
 		return nil, err
@@ -924,7 +974,7 @@ func ReviseProcurement(userID string, transactionNumber string, req dto.RevisePr
 
 	if err := recordStage(tx, transaction.ID, transactionNumber,
 		fromStage, targetStage,
-		models.ActionRevise, userID, nil, &req.RevisionNotes); err != nil {
+		models.ActionRevise, userID, nil, &req.RevisionNotes, meta); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
@@ -0,0 +1,634 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ============================================================
+// STOCK OPNAME FLOW
+// DRAFT → COUNTING → REVIEW → APPROVAL → RECONCILED
+//
+// DRAFT      : creator menyusun daftar asset (CreateStockOpname / UpdateStockOpname),
+//              atau dilewati lewat StartBranchStockOpname (semua asset satu cabang)
+// COUNTING   : snapshot data sistem ke stock_opnames + asset_stock_opnames,
+//              tim lapangan isi physical_status / condition per asset
+// REVIEW     : hasil hitung dikunci (stock_opnames LOCKED), dicek tim asset —
+//              bisa dikembalikan ke COUNTING
+// APPROVAL   : approval flow STOCK_OPNAME_APPROVAL
+// RECONCILED : otomatis setelah semua step approved — hasil hitung ditulis ke
+//              asset value aktif baru + asset history (stock_opnames APPROVED)
+// ============================================================
+
+func getStockOpnameTransaction(transactionNumber string) (*models.Transaction, error) {
+	var transaction models.Transaction
+	if err := config.DB.
+		Where("transaction_number = ? AND transaction_type = ?", transactionNumber, TxStockOpname).
+		First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("stock opname transaction not found")
+		}
+		return nil, err
+	}
+	return &transaction, nil
+}
+
+// getStockOpnameHeader - header stock_opnames milik transaksi (dibuat saat mulai counting)
+func getStockOpnameHeader(db *gorm.DB, transactionID uint) (*models.StockOpname, error) {
+	var header models.StockOpname
+	if err := db.Where("transaction_id = ?", transactionID).First(&header).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("stock opname worksheet not found, counting has not been started")
+		}
+		return nil, err
+	}
+	return &header, nil
+}
+
+// ============================================================
+// START COUNTING
+// DRAFT → COUNTING
+// ============================================================
+
+func StartStockOpnameCounting(userID string, transactionNumber string, req dto.StockOpnameStageRequest, meta dto.RequestMeta) (*dto.StockOpnameResponse, error) {
+	transaction, err := getStockOpnameTransaction(transactionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.CreatedBy != userID {
+		return nil, errors.New("you can only start counting on your own stock opname")
+	}
+
+	if transaction.CurrentStage != models.StageOpnameDraft {
+		return nil, fmt.Errorf("transaction is not in %s stage", models.StageOpnameDraft)
+	}
+
+	var items []models.TransactionStockOpname
+	if err := config.DB.Where("transaction_id = ?", transaction.ID).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, errors.New("cannot start counting a stock opname with no assets")
+	}
+
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	notes := ""
+	if transaction.Notes != nil {
+		notes = *transaction.Notes
+	}
+
+	period := transaction.TransactionDate.Format("2006-01")
+
+	assetIDs := make([]uint, 0, len(items))
+	for _, item := range items {
+		assetIDs = append(assetIDs, item.AssetID)
+	}
+	if err := ensureAssetsNotInOpenOpname(tx, period, assetIDs); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	header := models.StockOpname{
+		TransactionID:     &transaction.ID,
+		TransactionNumber: &transaction.TransactionNumber,
+		DocumentNumber:    transaction.TransactionNumber,
+		OpnameDate:        transaction.TransactionDate,
+		Period:            period,
+		Status:            models.StockOpnameStatusDraft,
+		Notes:             notes,
+		CreatedBy:         userID,
+	}
+	if err := tx.Create(&header).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Snapshot data sistem per asset sebelum dihitung
+	for _, item := range items {
+		var asset models.Asset
+		if err := tx.First(&asset, item.AssetID).Error; err != nil {
+			tx.Rollback()
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("asset not found: %d", item.AssetID)
+			}
+			return nil, err
+		}
+
+		if err := snapshotAssetForOpname(tx, header, *transaction, asset); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	fromStage := transaction.CurrentStage
+	if err := updateTransactionStage(tx, transaction, models.StageOpnameCounting); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := recordStage(tx, transaction.ID, transactionNumber,
+		fromStage, models.StageOpnameCounting,
+		models.ActionStartCounting, userID, nil, req.Notes, meta); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return GetStockOpnameByTransactionNumber(transactionNumber)
+}
+
+// snapshotAssetForOpname - salin kondisi asset + asset value aktif ke asset_stock_opnames
+func snapshotAssetForOpname(tx *gorm.DB, header models.StockOpname, transaction models.Transaction, asset models.Asset) error {
+	var value models.AssetValue
+	err := tx.Where("asset_id = ? AND is_active = ?", asset.ID, true).
+		Order("effective_date DESC").
+		First(&value).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	var active *models.AssetValue
+	if err == nil {
+		active = &value
+	}
+
+	snapshot := buildAssetOpnameSnapshot(header, transaction, asset, active)
+	return tx.Create(&snapshot).Error
+}
+
+// buildAssetOpnameSnapshot - value nil kalau asset belum punya asset value aktif
+func buildAssetOpnameSnapshot(header models.StockOpname, transaction models.Transaction, asset models.Asset, value *models.AssetValue) models.AssetStockOpname {
+	snapshot := models.AssetStockOpname{
+		StockOpnameID:     header.ID,
+		AssetID:           asset.ID,
+		TransactionID:     &transaction.ID,
+		TransactionNumber: &transaction.TransactionNumber,
+		AssetNumber:       asset.AssetNumber,
+		AssetName:         asset.AssetName,
+		Description:       derefString(asset.Description),
+		Brand:             derefString(asset.Brand),
+		UnitOfMeasure:     derefString(asset.UnitOfMeasure),
+		AssetStatus:       asset.AssetStatus,
+		Location:          derefString(asset.Location),
+		Grouping:          derefString(asset.Grouping),
+		CategoryID:        asset.CategoryID,
+		BranchCode:        derefString(asset.BranchCode),
+		IONumber:          derefString(asset.IONumber),
+		RecordType:        derefString(asset.RecordType),
+	}
+	if asset.UnitQuantity != nil {
+		snapshot.UnitQuantity = *asset.UnitQuantity
+	}
+
+	if value != nil {
+		snapshot.BookValue = value.BookValue
+		snapshot.AcquisitionValue = value.AcquisitionValue
+		snapshot.AccumulatedDepreciation = value.AccumulatedDepreciation
+		snapshot.Condition = derefString(value.Condition)
+		snapshot.PhysicalStatus = derefString(value.PhysicalStatus)
+	}
+
+	return snapshot
+}
+
+// ============================================================
+// UPDATE HASIL HITUNG
+// Hanya di stage COUNTING
+// ============================================================
+
+func UpdateStockOpnameCounts(userID string, transactionNumber string, req dto.UpdateStockOpnameCountsRequest) (*dto.StockOpnameResponse, error) {
+	transaction, err := getStockOpnameTransaction(transactionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.CurrentStage != models.StageOpnameCounting {
+		return nil, fmt.Errorf("transaction is not in %s stage", models.StageOpnameCounting)
+	}
+
+	var assetIDs []uint
+	config.DB.Model(&models.TransactionStockOpname{}).
+		Where("transaction_id = ?", transaction.ID).
+		Pluck("asset_id", &assetIDs)
+	inOpname := make(map[uint]bool, len(assetIDs))
+	for _, id := range assetIDs {
+		inOpname[id] = true
+	}
+	for _, item := range req.Items {
+		if !inOpname[item.AssetID] {
+			return nil, fmt.Errorf("asset %d is not part of this stock opname", item.AssetID)
+		}
+		if item.CountedBranchCode != nil && *item.CountedBranchCode != "" {
+			if err := validateBranchExists(*item.CountedBranchCode); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Input web selalu jadi writer terakhir, sync device yang lebih lama akan kalah
+	now := time.Now()
+	for _, item := range req.Items {
+		updates := map[string]interface{}{
+			"physical_status":   item.PhysicalStatus,
+			"condition":         item.Condition,
+			"counted_at":        now,
+			"counted_by":        userID,
+			"counted_device_id": nil,
+			"client_line_id":    nil,
+		}
+		if item.AssetStatus != nil {
+			updates["asset_status"] = *item.AssetStatus
+		}
+		if item.Notes != nil {
+			updates["notes"] = item.Notes
+		}
+		// String kosong = kembali sesuai register
+		if item.CountedBranchCode != nil {
+			updates["counted_branch_code"] = optionalString(*item.CountedBranchCode)
+		}
+		if item.CountedLocation != nil {
+			updates["counted_location"] = optionalString(*item.CountedLocation)
+		}
+
+		if err := tx.Model(&models.TransactionStockOpname{}).
+			Where("transaction_id = ? AND asset_id = ?", transaction.ID, item.AssetID).
+			Updates(updates).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return GetStockOpnameByTransactionNumber(transactionNumber)
+}
+
+// ============================================================
+// SUBMIT HASIL HITUNG
+// COUNTING → REVIEW, worksheet dikunci
+// ============================================================
+
+func SubmitStockOpnameCount(userID string, transactionNumber string, req dto.StockOpnameStageRequest, meta dto.RequestMeta) (*dto.StockOpnameResponse, error) {
+	transaction, err := getStockOpnameTransaction(transactionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	// Worksheet per cabang dibuat tanpa hasil hitung — semua asset wajib dihitung dulu
+	var uncounted int64
+	if err := config.DB.Model(&models.TransactionStockOpname{}).
+		Where("transaction_id = ?", transaction.ID).
+		Where("physical_status = '' OR physical_status IS NULL OR `condition` = '' OR `condition` IS NULL").
+		Count(&uncounted).Error; err != nil {
+		return nil, err
+	}
+	if uncounted > 0 {
+		return nil, fmt.Errorf("%d asset(s) have not been counted yet", uncounted)
+	}
+
+	return moveStockOpnameStage(userID, transactionNumber,
+		models.StageOpnameCounting, models.StageOpnameReview,
+		models.ActionSubmitCount, models.StockOpnameStatusLocked, req.Notes, meta)
+}
+
+// ============================================================
+// REVIEW
+// REVIEW → APPROVAL (confirm) atau REVIEW → COUNTING (return)
+// ============================================================
+
+func ConfirmStockOpnameReview(userID string, transactionNumber string, req dto.StockOpnameStageRequest, meta dto.RequestMeta) (*dto.StockOpnameResponse, error) {
+	return moveStockOpnameStage(userID, transactionNumber,
+		models.StageOpnameReview, models.StageOpnameApproval,
+		models.ActionVerify, models.StockOpnameStatusLocked, req.Notes, meta)
+}
+
+func ReturnStockOpnameToCounting(userID string, transactionNumber string, req dto.StockOpnameStageRequest, meta dto.RequestMeta) (*dto.StockOpnameResponse, error) {
+	return moveStockOpnameStage(userID, transactionNumber,
+		models.StageOpnameReview, models.StageOpnameCounting,
+		models.ActionRevise, models.StockOpnameStatusDraft, req.Notes, meta)
+}
+
+// moveStockOpnameStage - pindah stage + update status worksheet dalam satu tx
+func moveStockOpnameStage(userID, transactionNumber, fromStage, toStage, action, headerStatus string, notes *string, meta dto.RequestMeta) (*dto.StockOpnameResponse, error) {
+	transaction, err := getStockOpnameTransaction(transactionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.CurrentStage != fromStage {
+		return nil, fmt.Errorf("transaction is not in %s stage", fromStage)
+	}
+
+	header, err := getStockOpnameHeader(config.DB, transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Model(header).Update("status", headerStatus).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := updateTransactionStage(tx, transaction, toStage); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := recordStage(tx, transaction.ID, transactionNumber,
+		fromStage, toStage, action, userID, nil, notes, meta); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return GetStockOpnameByTransactionNumber(transactionNumber)
+}
+
+// ============================================================
+// INITIATE APPROVAL
+// ============================================================
+
+func InitiateStockOpnameApproval(userID string, transactionNumber string, req dto.InitiateApprovalRequest) error {
+	transaction, err := getStockOpnameTransaction(transactionNumber)
+	if err != nil {
+		return err
+	}
+
+	if transaction.CurrentStage != models.StageOpnameApproval {
+		return fmt.Errorf("transaction is not in %s stage", models.StageOpnameApproval)
+	}
+
+	// Auto-lookup STOCK_OPNAME_APPROVAL flow by branch creator
+	creatorHomebase, homebaseErr := GetUserActiveHomebase(transaction.CreatedBy)
+	branchCode := "ALL"
+	if homebaseErr == nil {
+		branchCode = creatorHomebase.Branch.BranchCode
+	}
+
+	flow, err := GetApprovalFlowByCodeAndBranch(models.FlowStockOpnameApproval, branchCode)
+	if err != nil {
+		return fmt.Errorf("approval flow %s not found for branch %s or ALL", models.FlowStockOpnameApproval, branchCode)
+	}
+
+	if !flow.IsActive {
+		return fmt.Errorf("approval flow %s is inactive", models.FlowStockOpnameApproval)
+	}
+
+	approvalReq := dto.CreateTransactionApprovalRequest{
+		FlowID:            flow.ID,
+		TransactionNumber: transactionNumber,
+		TransactionType:   TxStockOpname,
+		Metadata:          req.Metadata,
+	}
+
+	return InitiateTransactionApproval(approvalReq)
+}
+
+// ============================================================
+// RECONCILIATION
+// APPROVAL → RECONCILED, dipanggil otomatis setelah semua step approved
+// ============================================================
+
+func autoCompleteStockOpnameApproval(userID, transactionNumber, transactionType string, meta dto.RequestMeta) error {
+	if transactionType != TxStockOpname {
+		return nil
+	}
+
+	if !isApprovalFlowCompleted(transactionNumber, transactionType, "") {
+		return nil
+	}
+
+	transaction, err := getStockOpnameTransaction(transactionNumber)
+	if err != nil {
+		return err
+	}
+
+	if transaction.CurrentStage != models.StageOpnameApproval {
+		return nil
+	}
+
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock transaksi — hook bisa terpanggil bersamaan dari dua approver terakhir
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(transaction, transaction.ID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if transaction.CurrentStage != models.StageOpnameApproval {
+		tx.Rollback()
+		return nil
+	}
+
+	if err := reconcileStockOpname(tx, transaction, userID); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	fromStage := transaction.CurrentStage
+	if err := updateTransactionStage(tx, transaction, models.StageOpnameReconciled); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	now := time.Now()
+	if err := tx.Model(transaction).Updates(map[string]interface{}{
+		"approved_by": userID,
+		"approved_at": now,
+	}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := recordStage(tx, transaction.ID, transactionNumber,
+		fromStage, models.StageOpnameReconciled,
+		models.ActionReconcile, userID, nil, nil, meta); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	notifyTransactionStage(transactionNumber, models.StageOpnameReconciled, nil)
+	return nil
+}
+
+// reconcileStockOpname - tulis hasil hitung ke asset value aktif baru + asset history per asset
+func reconcileStockOpname(tx *gorm.DB, transaction *models.Transaction, userID string) error {
+	header, err := getStockOpnameHeader(tx, transaction.ID)
+	if err != nil {
+		return err
+	}
+
+	var items []models.TransactionStockOpname
+	if err := tx.Where("transaction_id = ?", transaction.ID).Find(&items).Error; err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, item := range items {
+		before, err := loadAssetSnapshot(tx, item.AssetID)
+		if err != nil {
+			return err
+		}
+
+		condition := item.Condition
+		physicalStatus := item.PhysicalStatus
+
+		newValue := models.AssetValue{
+			AssetID:        item.AssetID,
+			EffectiveDate:  now,
+			Condition:      &condition,
+			PhysicalStatus: &physicalStatus,
+			IsActive:       true,
+		}
+		if before.AssetValue != nil {
+			newValue.BookValue = before.AssetValue.BookValue
+			newValue.AcquisitionValue = before.AssetValue.AcquisitionValue
+			newValue.AccumulatedDepreciation = before.AssetValue.AccumulatedDepreciation
+			newValue.AssetStatus = before.AssetValue.AssetStatus
+
+			if err := tx.Model(&models.AssetValue{}).
+				Where("asset_id = ? AND is_active = ?", item.AssetID, true).
+				Update("is_active", false).Error; err != nil {
+				return fmt.Errorf("failed to deactivate asset value: %w", err)
+			}
+		}
+		if item.AssetStatus != "" {
+			assetStatus := item.AssetStatus
+			newValue.AssetStatus = &assetStatus
+		}
+
+		if err := tx.Create(&newValue).Error; err != nil {
+			return fmt.Errorf("failed to create asset value: %w", err)
+		}
+
+		after, err := loadAssetSnapshot(tx, item.AssetID)
+		if err != nil {
+			return err
+		}
+
+		if err := recordAssetHistory(tx, AssetHistoryEntry{
+			AssetID:           item.AssetID,
+			TransactionType:   models.TransactionTypeStockOpname,
+			TransactionID:     transaction.ID,
+			TransactionNumber: transaction.TransactionNumber,
+			DocumentNumber:    header.DocumentNumber,
+			TransactionDate:   now,
+			Before:            before,
+			After:             after,
+			ChangedBy:         userID,
+		}); err != nil {
+			return fmt.Errorf("failed to record asset history: %w", err)
+		}
+	}
+
+	return tx.Model(header).Updates(map[string]interface{}{
+		"status":      models.StockOpnameStatusApproved,
+		"approved_by": userID,
+		"approved_at": now,
+	}).Error
+}
+
+// ============================================================
+// REJECT
+// ============================================================
+
+func RejectStockOpname(userID string, transactionNumber string, req dto.RejectStockOpnameRequest, meta dto.RequestMeta) (*dto.StockOpnameResponse, error) {
+	transaction, err := getStockOpnameTransaction(transactionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	switch transaction.CurrentStage {
+	case models.StageOpnameCounting, models.StageOpnameReview, models.StageOpnameApproval:
+	default:
+		return nil, fmt.Errorf("cannot reject transaction in %s stage", transaction.CurrentStage)
+	}
+
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Model(&models.StockOpname{}).
+		Where("transaction_id = ?", transaction.ID).
+		Update("status", models.StockOpnameStatusRejected).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	fromStage := transaction.CurrentStage
+	if err := updateTransactionStage(tx, transaction, models.StageOpnameRejected); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := recordStage(tx, transaction.ID, transactionNumber,
+		fromStage, models.StageOpnameRejected,
+		models.ActionReject, userID, nil, &req.Reason, meta); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	MarkTransactionAsExpired(transactionNumber)
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	notifyTransactionStage(transactionNumber, models.StageOpnameRejected, &req.Reason)
+
+	return GetStockOpnameByTransactionNumber(transactionNumber)
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -34,6 +34,7 @@ func CreateStockOpname(userID string, req dto.CreateStockOpnameRequest) (*dto.St
 		TransactionType:   TxStockOpname,
 		TransactionDate:   transactionDate,
 		Status:            models.TransactionStatusDraft, // FIX: pakai models konstanta
+		CurrentStage:      models.StageOpnameDraft,
 		Notes:             req.Notes,
 		CreatedBy:         userID,
 	}
@@ -109,9 +110,13 @@ func GetStockOpnameByTransactionNumber(transactionNumber string) (*dto.StockOpna
 		return nil, err
 	}
 
+	var stages []models.TransactionStage
+	config.DB.Where("transaction_id = ?", transaction.ID).Order("created_at ASC").Find(&stages)
+
 	return &dto.StockOpnameResponse{
 		Transaction: mapTransactionHeaderToResponse(transaction),
 		Items:       mapStockOpnameItemsToResponse(stockOpnames),
+		Stages:      mapTransactionStagesToResponse(stages),
 	}, nil
 }
 
@@ -0,0 +1,586 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ============================================================
+// STOCK OPNAME OFFLINE SYNC
+// Untuk scanner mobile yang bekerja tanpa sinyal:
+//   1. device download worksheet cabangnya (GetStockOpnameSyncWorksheets)
+//   2. hitung offline, tiap baris diberi client_line_id + client_timestamp
+//   3. upload bulk (SyncStockOpnameCounts) — boleh diulang, client_line_id
+//      yang sudah diterima tidak diproses lagi (DUPLICATE)
+//
+// Konflik: dua writer (device lain / web) mengisi asset yang sama dengan
+// hasil berbeda → last-writer-wins berdasarkan timestamp hasil hitung,
+// pasangan winner / loser dicatat di stock_opname_sync_conflicts.
+//
+// Foto bukti disimpan di storage attachment (storeAttachmentFile).
+// ============================================================
+
+// syncMaxClockSkew - timestamp device lebih dari ini di depan jam server ditolak,
+// supaya jam device yang salah tidak menang terus
+const syncMaxClockSkew = 5 * time.Minute
+
+// stockOpnameCountValues - nilai hitung yang dibandingkan & dicatat di conflict log
+type stockOpnameCountValues struct {
+	PhysicalStatus    string  `json:"physical_status"`
+	Condition         string  `json:"condition"`
+	AssetStatus       string  `json:"asset_status"`
+	CountedBranchCode *string `json:"counted_branch_code"`
+	CountedLocation   *string `json:"counted_location"`
+	Notes             *string `json:"notes"`
+}
+
+func (v stockOpnameCountValues) differs(other stockOpnameCountValues) bool {
+	return v.PhysicalStatus != other.PhysicalStatus ||
+		v.Condition != other.Condition ||
+		v.AssetStatus != other.AssetStatus ||
+		derefString(v.CountedBranchCode) != derefString(other.CountedBranchCode) ||
+		derefString(v.CountedLocation) != derefString(other.CountedLocation)
+}
+
+func (v stockOpnameCountValues) json() string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func countValuesFromItem(item models.TransactionStockOpname) stockOpnameCountValues {
+	return stockOpnameCountValues{
+		PhysicalStatus:    item.PhysicalStatus,
+		Condition:         item.Condition,
+		AssetStatus:       item.AssetStatus,
+		CountedBranchCode: item.CountedBranchCode,
+		CountedLocation:   item.CountedLocation,
+		Notes:             item.Notes,
+	}
+}
+
+// countValuesFromLine - asset_status / notes kosong = tetap pakai nilai sebelumnya
+func countValuesFromLine(line dto.SyncStockOpnameCountLine, current models.TransactionStockOpname) stockOpnameCountValues {
+	values := stockOpnameCountValues{
+		PhysicalStatus:    line.PhysicalStatus,
+		Condition:         line.Condition,
+		AssetStatus:       current.AssetStatus,
+		CountedBranchCode: optionalString(derefString(line.CountedBranchCode)),
+		CountedLocation:   optionalString(derefString(line.CountedLocation)),
+		Notes:             current.Notes,
+	}
+	if line.AssetStatus != nil {
+		values.AssetStatus = *line.AssetStatus
+	}
+	if line.Notes != nil {
+		values.Notes = line.Notes
+	}
+	return values
+}
+
+// ============================================================
+// DOWNLOAD WORKSHEET
+// Semua opname cabang yang sedang COUNTING
+// ============================================================
+
+func GetStockOpnameSyncWorksheets(userID string, branchCode string) (*dto.StockOpnameSyncWorksheetsResponse, error) {
+	// Default cabang = homebase user
+	if branchCode == "" {
+		homebase, err := GetUserActiveHomebase(userID)
+		if err != nil {
+			return nil, err
+		}
+		if homebase.Branch == nil {
+			return nil, errors.New("branch data not found")
+		}
+		branchCode = homebase.Branch.BranchCode
+	}
+
+	var transactionNumbers []string
+	if err := config.DB.Model(&models.StockOpname{}).
+		Joins("JOIN transactions ON transactions.id = stock_opnames.transaction_id").
+		Where("stock_opnames.branch_code = ? AND transactions.current_stage = ?", branchCode, models.StageOpnameCounting).
+		Order("stock_opnames.opname_date ASC").
+		Pluck("stock_opnames.transaction_number", &transactionNumbers).Error; err != nil {
+		return nil, err
+	}
+
+	response := &dto.StockOpnameSyncWorksheetsResponse{
+		ServerTime: time.Now(),
+		BranchCode: branchCode,
+		Worksheets: make([]dto.StockOpnameWorksheetResponse, 0, len(transactionNumbers)),
+	}
+	for _, transactionNumber := range transactionNumbers {
+		worksheet, err := GetStockOpnameWorksheet(transactionNumber)
+		if err != nil {
+			return nil, err
+		}
+		response.Worksheets = append(response.Worksheets, *worksheet)
+	}
+
+	return response, nil
+}
+
+// ============================================================
+// UPLOAD COUNTS
+// ============================================================
+
+func SyncStockOpnameCounts(userID string, transactionNumber string, req dto.SyncStockOpnameCountsRequest) (*dto.SyncStockOpnameCountsResponse, error) {
+	transaction, err := getStockOpnameTransaction(transactionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := getStockOpnameHeader(config.DB, transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []models.AssetStockOpname
+	if err := config.DB.Select("id", "asset_id").
+		Where("stock_opname_id = ?", header.ID).
+		Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+	snapshotIDs := make(map[uint]uint, len(snapshots))
+	for _, snapshot := range snapshots {
+		snapshotIDs[snapshot.AssetID] = snapshot.ID
+	}
+
+	// Cabang hitung divalidasi sebelum tx, hasilnya di-cache per kode
+	branchErrors := map[string]error{}
+	validBranch := func(code *string) error {
+		if code == nil || *code == "" {
+			return nil
+		}
+		if _, ok := branchErrors[*code]; !ok {
+			branchErrors[*code] = validateBranchExists(*code)
+		}
+		return branchErrors[*code]
+	}
+
+	// Proses urut timestamp supaya last-writer-wins dalam satu batch konsisten
+	lines := make([]dto.SyncStockOpnameCountLine, len(req.Lines))
+	copy(lines, req.Lines)
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].ClientTimestamp.Before(lines[j].ClientTimestamp)
+	})
+
+	clientLineIDs := make([]string, 0, len(lines))
+	for _, line := range lines {
+		clientLineIDs = append(clientLineIDs, line.ClientLineID)
+	}
+
+	now := time.Now()
+	response := &dto.SyncStockOpnameCountsResponse{
+		TransactionNumber: transactionNumber,
+		ServerTime:        now,
+		Lines:             make([]dto.SyncStockOpnameLineResult, 0, len(lines)),
+	}
+
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock transaksi → batch untuk opname yang sama diproses bergantian,
+	// dan stage tidak bisa pindah di tengah sync
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(transaction, transaction.ID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	counting := transaction.CurrentStage == models.StageOpnameCounting
+
+	var received []models.StockOpnameSyncLine
+	if err := tx.Select("client_line_id", "result").
+		Where("client_line_id IN ?", clientLineIDs).
+		Find(&received).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	receivedResults := make(map[string]string, len(received))
+	for _, line := range received {
+		receivedResults[line.ClientLineID] = line.Result
+	}
+
+	var items []models.TransactionStockOpname
+	if err := tx.Where("transaction_id = ?", transaction.ID).Find(&items).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	itemsByAsset := make(map[uint]*models.TransactionStockOpname, len(items))
+	for i := range items {
+		itemsByAsset[items[i].AssetID] = &items[i]
+	}
+
+	for _, line := range lines {
+		result := dto.SyncStockOpnameLineResult{
+			ClientLineID: line.ClientLineID,
+			AssetID:      line.AssetID,
+		}
+		reject := func(message string) {
+			result.Result = models.SyncLineRejected
+			result.Message = &message
+			response.Rejected++
+			response.Lines = append(response.Lines, result)
+		}
+
+		if previous, ok := receivedResults[line.ClientLineID]; ok {
+			message := fmt.Sprintf("already received (%s)", previous)
+			result.Result = models.SyncLineDuplicate
+			result.Message = &message
+			response.Duplicates++
+			response.Lines = append(response.Lines, result)
+			continue
+		}
+
+		if !counting {
+			reject(fmt.Sprintf("transaction is not in %s stage", models.StageOpnameCounting))
+			continue
+		}
+
+		item, ok := itemsByAsset[line.AssetID]
+		if !ok {
+			reject("asset is not part of this stock opname")
+			continue
+		}
+
+		if line.ClientTimestamp.After(now.Add(syncMaxClockSkew)) {
+			reject("client timestamp is in the future, check the device clock")
+			continue
+		}
+
+		if err := validBranch(line.CountedBranchCode); err != nil {
+			reject(err.Error())
+			continue
+		}
+
+		current := countValuesFromItem(*item)
+		incoming := countValuesFromLine(line, *item)
+
+		// Writer lain = device berbeda atau input web (device nil)
+		otherWriter := item.CountedAt != nil &&
+			(item.CountedDeviceID == nil || *item.CountedDeviceID != req.DeviceID)
+		conflict := otherWriter && current.differs(incoming)
+
+		syncLine := models.StockOpnameSyncLine{
+			ClientLineID:      line.ClientLineID,
+			TransactionID:     transaction.ID,
+			TransactionNumber: transaction.TransactionNumber,
+			AssetID:           line.AssetID,
+			DeviceID:          req.DeviceID,
+			UserID:            userID,
+			ClientTimestamp:   line.ClientTimestamp,
+			PhysicalStatus:    line.PhysicalStatus,
+			Condition:         line.Condition,
+			AssetStatus:       line.AssetStatus,
+			CountedBranchCode: line.CountedBranchCode,
+			CountedLocation:   line.CountedLocation,
+			Notes:             line.Notes,
+		}
+		if id, ok := snapshotIDs[line.AssetID]; ok {
+			syncLine.AssetStockOpnameID = &id
+		}
+
+		wins := item.CountedAt == nil || line.ClientTimestamp.After(*item.CountedAt)
+
+		if conflict {
+			incomingSide := syncConflictSide{
+				clientLineID: &syncLine.ClientLineID,
+				deviceID:     &syncLine.DeviceID,
+				userID:       &userID,
+				timestamp:    line.ClientTimestamp,
+				values:       incoming,
+			}
+			currentSide := syncConflictSide{
+				clientLineID: item.ClientLineID,
+				deviceID:     item.CountedDeviceID,
+				userID:       item.CountedBy,
+				timestamp:    *item.CountedAt,
+				values:       current,
+			}
+
+			winner, loser := currentSide, incomingSide
+			if wins {
+				winner, loser = incomingSide, currentSide
+			}
+
+			if err := tx.Create(&models.StockOpnameSyncConflict{
+				TransactionID:      transaction.ID,
+				TransactionNumber:  transaction.TransactionNumber,
+				AssetStockOpnameID: syncLine.AssetStockOpnameID,
+				AssetID:            item.AssetID,
+				AssetNumber:        item.AssetNumber,
+				Resolution:         models.SyncResolutionLastWriterWins,
+				WinnerClientLineID: winner.clientLineID,
+				WinnerDeviceID:     winner.deviceID,
+				WinnerUserID:       winner.userID,
+				WinnerTimestamp:    winner.timestamp,
+				WinnerValues:       winner.values.json(),
+				LoserClientLineID:  loser.clientLineID,
+				LoserDeviceID:      loser.deviceID,
+				LoserUserID:        loser.userID,
+				LoserTimestamp:     loser.timestamp,
+				LoserValues:        loser.values.json(),
+			}).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+
+			result.Conflict = true
+			response.Conflicts++
+		}
+
+		if wins {
+			countedAt := line.ClientTimestamp
+			deviceID := req.DeviceID
+			clientLineID := line.ClientLineID
+			counter := userID
+
+			if err := tx.Model(&models.TransactionStockOpname{}).
+				Where("id = ?", item.ID).
+				Updates(map[string]interface{}{
+					"physical_status":     incoming.PhysicalStatus,
+					"condition":           incoming.Condition,
+					"asset_status":        incoming.AssetStatus,
+					"counted_branch_code": incoming.CountedBranchCode,
+					"counted_location":    incoming.CountedLocation,
+					"notes":               incoming.Notes,
+					"counted_at":          countedAt,
+					"counted_by":          counter,
+					"counted_device_id":   deviceID,
+					"client_line_id":      clientLineID,
+				}).Error; err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+
+			// Update state lokal supaya baris berikutnya di batch yang sama
+			// dibandingkan dengan nilai terbaru
+			item.PhysicalStatus = incoming.PhysicalStatus
+			item.Condition = incoming.Condition
+			item.AssetStatus = incoming.AssetStatus
+			item.CountedBranchCode = incoming.CountedBranchCode
+			item.CountedLocation = incoming.CountedLocation
+			item.Notes = incoming.Notes
+			item.CountedAt = &countedAt
+			item.CountedBy = &counter
+			item.CountedDeviceID = &deviceID
+			item.ClientLineID = &clientLineID
+
+			syncLine.Result = models.SyncLineApplied
+			response.Applied++
+		} else {
+			message := "a newer count for this asset already exists"
+			syncLine.Result = models.SyncLineSuperseded
+			syncLine.Message = &message
+			result.Message = &message
+			response.Superseded++
+		}
+
+		if err := tx.Create(&syncLine).Error; err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		receivedResults[line.ClientLineID] = syncLine.Result
+
+		result.Result = syncLine.Result
+		response.Lines = append(response.Lines, result)
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+type syncConflictSide struct {
+	clientLineID *string
+	deviceID     *string
+	userID       *string
+	timestamp    time.Time
+	values       stockOpnameCountValues
+}
+
+// ============================================================
+// CONFLICT LOG
+// ============================================================
+
+func GetStockOpnameSyncConflicts(transactionNumber string) ([]dto.StockOpnameSyncConflictResponse, error) {
+	transaction, err := getStockOpnameTransaction(transactionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var conflicts []models.StockOpnameSyncConflict
+	if err := config.DB.
+		Where("transaction_id = ?", transaction.ID).
+		Order("created_at DESC").
+		Find(&conflicts).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.StockOpnameSyncConflictResponse, 0, len(conflicts))
+	for _, c := range conflicts {
+		result = append(result, dto.StockOpnameSyncConflictResponse{
+			ID:                 c.ID,
+			AssetStockOpnameID: c.AssetStockOpnameID,
+			AssetID:            c.AssetID,
+			AssetNumber:        c.AssetNumber,
+			Resolution:         c.Resolution,
+			WinnerClientLineID: c.WinnerClientLineID,
+			WinnerDeviceID:     c.WinnerDeviceID,
+			WinnerUserID:       c.WinnerUserID,
+			WinnerTimestamp:    c.WinnerTimestamp,
+			WinnerValues:       json.RawMessage(c.WinnerValues),
+			LoserClientLineID:  c.LoserClientLineID,
+			LoserDeviceID:      c.LoserDeviceID,
+			LoserUserID:        c.LoserUserID,
+			LoserTimestamp:     c.LoserTimestamp,
+			LoserValues:        json.RawMessage(c.LoserValues),
+			CreatedAt:          c.CreatedAt,
+		})
+	}
+	return result, nil
+}
+
+// ============================================================
+// FOTO BUKTI
+// Disimpan di {AttachmentStoragePath}/stock_opname/{transaction_number}/COUNTING/
+// client_photo_id unik → upload ulang mengembalikan foto yang sama
+// ============================================================
+
+func UploadStockOpnameCountPhoto(
+	userID string,
+	transactionNumber string,
+	req dto.UploadStockOpnameCountPhotoRequest,
+	file multipart.File,
+	fileHeader *multipart.FileHeader,
+) (*dto.StockOpnameCountPhotoResponse, error) {
+	transaction, err := getStockOpnameTransaction(transactionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	var existing models.StockOpnameCountPhoto
+	err = config.DB.Where("client_photo_id = ?", req.ClientPhotoID).First(&existing).Error
+	if err == nil {
+		if existing.TransactionID != transaction.ID {
+			return nil, errors.New("client_photo_id already used by another stock opname")
+		}
+		response := mapCountPhotoToResponse(existing)
+		return &response, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// Foto boleh menyusul selama hasil hitung belum disetujui
+	if transaction.CurrentStage != models.StageOpnameCounting && transaction.CurrentStage != models.StageOpnameReview {
+		return nil, fmt.Errorf("photos can only be uploaded in %s or %s stage", models.StageOpnameCounting, models.StageOpnameReview)
+	}
+
+	mimeType := detectMimeType(fileHeader.Filename)
+	if mimeType != "image/jpeg" && mimeType != "image/png" {
+		return nil, errors.New("photo must be a JPEG or PNG image")
+	}
+
+	header, err := getStockOpnameHeader(config.DB, transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot models.AssetStockOpname
+	if err := config.DB.
+		Where("stock_opname_id = ? AND asset_id = ?", header.ID, req.AssetID).
+		First(&snapshot).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("asset is not part of this stock opname")
+		}
+		return nil, err
+	}
+
+	filePath, fileSize, err := storeAttachmentFile(TxStockOpname, transactionNumber, models.StageOpnameCounting, file, fileHeader.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	photo := models.StockOpnameCountPhoto{
+		ClientPhotoID:      req.ClientPhotoID,
+		TransactionID:      transaction.ID,
+		TransactionNumber:  transactionNumber,
+		AssetStockOpnameID: &snapshot.ID,
+		AssetID:            req.AssetID,
+		ClientLineID:       req.ClientLineID,
+		DeviceID:           req.DeviceID,
+		FileName:           filepath.Base(fileHeader.Filename),
+		FilePath:           filePath,
+		FileSize:           &fileSize,
+		MimeType:           &mimeType,
+		TakenAt:            req.TakenAt,
+		UploadedBy:         userID,
+		UploadedAt:         time.Now(),
+	}
+	if err := config.DB.Create(&photo).Error; err != nil {
+		// Hapus file kalau DB error
+		os.Remove(filePath)
+		return nil, err
+	}
+
+	response := mapCountPhotoToResponse(photo)
+	return &response, nil
+}
+
+func GetStockOpnameCountPhotos(transactionNumber string, assetID *uint) ([]dto.StockOpnameCountPhotoResponse, error) {
+	transaction, err := getStockOpnameTransaction(transactionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	query := config.DB.Where("transaction_id = ?", transaction.ID)
+	if assetID != nil {
+		query = query.Where("asset_id = ?", *assetID)
+	}
+
+	var photos []models.StockOpnameCountPhoto
+	if err := query.Order("uploaded_at ASC").Find(&photos).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.StockOpnameCountPhotoResponse, 0, len(photos))
+	for _, photo := range photos {
+		result = append(result, mapCountPhotoToResponse(photo))
+	}
+	return result, nil
+}
+
+func mapCountPhotoToResponse(photo models.StockOpnameCountPhoto) dto.StockOpnameCountPhotoResponse {
+	return dto.StockOpnameCountPhotoResponse{
+		ID:            photo.ID,
+		ClientPhotoID: photo.ClientPhotoID,
+		AssetID:       photo.AssetID,
+		ClientLineID:  photo.ClientLineID,
+		DeviceID:      photo.DeviceID,
+		FileName:      photo.FileName,
+		FileSize:      photo.FileSize,
+		MimeType:      photo.MimeType,
+		TakenAt:       photo.TakenAt,
+		UploadedBy:    photo.UploadedBy,
+		UploadedAt:    photo.UploadedAt,
+	}
+}
@@ -0,0 +1,543 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================
+// STOCK OPNAME VARIANCE
+// Bandingkan hasil hitung dengan register asset:
+//   - missing    : physical_status MISSING
+//   - relocated  : cabang / lokasi hitung beda dengan Asset.BranchCode / Asset.Location
+//   - downgraded : kondisi turun ke POOR / BROKEN dibanding snapshot
+//   - found items: barang di lokasi yang tidak terdaftar
+// Book value at risk (missing + downgraded) dijumlah per kategori.
+//
+// Follow up: draft mutasi per (kategori, cabang ditemukan) untuk asset
+// yang pindah cabang, satu draft disposal untuk asset MISSING / BROKEN.
+// ============================================================
+
+const (
+	FollowUpMutation = "MUTATION"
+	FollowUpDisposal = "DISPOSAL"
+)
+
+// conditionRank - makin kecil makin buruk
+var conditionRank = map[string]int{
+	models.ConditionGood:   3,
+	models.ConditionFair:   2,
+	models.ConditionPoor:   1,
+	models.ConditionBroken: 0,
+}
+
+// isConditionDowngrade - kondisi hitung POOR / BROKEN dan lebih buruk dari kondisi sistem
+func isConditionDowngrade(system, counted string) bool {
+	if counted != models.ConditionPoor && counted != models.ConditionBroken {
+		return false
+	}
+	systemRank, ok := conditionRank[system]
+	if !ok {
+		return false
+	}
+	return conditionRank[counted] < systemRank
+}
+
+func differsFromRegister(counted *string, register *string) bool {
+	if counted == nil || strings.TrimSpace(*counted) == "" {
+		return false
+	}
+	if register == nil {
+		return true
+	}
+	return !strings.EqualFold(strings.TrimSpace(*counted), strings.TrimSpace(*register))
+}
+
+// ============================================================
+// FOUND ITEMS
+// Hanya di stage COUNTING
+// ============================================================
+
+func AddStockOpnameFoundItem(userID string, transactionNumber string, req dto.CreateStockOpnameFoundItemRequest) (*dto.StockOpnameFoundItemResponse, error) {
+	transaction, err := getStockOpnameTransaction(transactionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if transaction.CurrentStage != models.StageOpnameCounting {
+		return nil, fmt.Errorf("transaction is not in %s stage", models.StageOpnameCounting)
+	}
+
+	header, err := getStockOpnameHeader(config.DB, transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.CategoryID != nil {
+		var category models.AssetCategory
+		if err := config.DB.First(&category, *req.CategoryID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("category not found: %d", *req.CategoryID)
+			}
+			return nil, err
+		}
+	}
+
+	// Default cabang = cabang opname
+	branchCode := header.BranchCode
+	if req.BranchCode != nil && *req.BranchCode != "" {
+		if err := validateBranchExists(*req.BranchCode); err != nil {
+			return nil, err
+		}
+		branchCode = req.BranchCode
+	}
+
+	quantity := 1.0
+	if req.Quantity != nil {
+		quantity = *req.Quantity
+	}
+
+	item := models.StockOpnameFoundItem{
+		TransactionID:     transaction.ID,
+		TransactionNumber: transaction.TransactionNumber,
+		ItemName:          req.ItemName,
+		Description:       req.Description,
+		Brand:             req.Brand,
+		SerialNumber:      req.SerialNumber,
+		CategoryID:        req.CategoryID,
+		BranchCode:        branchCode,
+		Location:          req.Location,
+		Condition:         req.Condition,
+		Quantity:          quantity,
+		EstimatedValue:    req.EstimatedValue,
+		Notes:             req.Notes,
+		FoundBy:           userID,
+	}
+	if err := config.DB.Create(&item).Error; err != nil {
+		return nil, err
+	}
+
+	config.DB.Preload("Category").First(&item, item.ID)
+
+	response := mapFoundItemToResponse(item)
+	return &response, nil
+}
+
+func RemoveStockOpnameFoundItem(userID string, transactionNumber string, itemID uint) error {
+	transaction, err := getStockOpnameTransaction(transactionNumber)
+	if err != nil {
+		return err
+	}
+
+	if transaction.CurrentStage != models.StageOpnameCounting {
+		return fmt.Errorf("transaction is not in %s stage", models.StageOpnameCounting)
+	}
+
+	var item models.StockOpnameFoundItem
+	if err := config.DB.
+		Where("id = ? AND transaction_id = ?", itemID, transaction.ID).
+		First(&item).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("found item not found")
+		}
+		return err
+	}
+
+	return config.DB.Delete(&item).Error
+}
+
+func mapFoundItemToResponse(item models.StockOpnameFoundItem) dto.StockOpnameFoundItemResponse {
+	response := dto.StockOpnameFoundItemResponse{
+		ID:             item.ID,
+		ItemName:       item.ItemName,
+		Description:    item.Description,
+		Brand:          item.Brand,
+		SerialNumber:   item.SerialNumber,
+		CategoryID:     item.CategoryID,
+		BranchCode:     item.BranchCode,
+		Location:       item.Location,
+		Condition:      item.Condition,
+		Quantity:       item.Quantity,
+		EstimatedValue: item.EstimatedValue,
+		Notes:          item.Notes,
+		FoundBy:        item.FoundBy,
+		CreatedAt:      item.CreatedAt,
+	}
+	if item.Category != nil {
+		response.CategoryName = &item.Category.CategoryName
+	}
+	return response
+}
+
+// ============================================================
+// VARIANCE REPORT
+// ============================================================
+
+func GetStockOpnameVariance(transactionNumber string) (*dto.StockOpnameVarianceResponse, error) {
+	transaction, err := getStockOpnameTransaction(transactionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := getStockOpnameHeader(config.DB, transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []models.TransactionStockOpname
+	if err := config.DB.
+		Preload("Asset").
+		Where("transaction_id = ?", transaction.ID).
+		Order("asset_number ASC").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	var snapshots []models.AssetStockOpname
+	if err := config.DB.Where("stock_opname_id = ?", header.ID).Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+	snapshotByAsset := make(map[uint]models.AssetStockOpname, len(snapshots))
+	for _, snapshot := range snapshots {
+		snapshotByAsset[snapshot.AssetID] = snapshot
+	}
+
+	var foundItems []models.StockOpnameFoundItem
+	if err := config.DB.
+		Preload("Category").
+		Where("transaction_id = ?", transaction.ID).
+		Order("created_at ASC").
+		Find(&foundItems).Error; err != nil {
+		return nil, err
+	}
+
+	var categories []models.AssetCategory
+	config.DB.Find(&categories)
+	categoryNames := make(map[uint]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.ID] = category.CategoryName
+	}
+	categoryName := func(id *uint) *string {
+		if id == nil {
+			return nil
+		}
+		if name, ok := categoryNames[*id]; ok {
+			return &name
+		}
+		return nil
+	}
+
+	response := &dto.StockOpnameVarianceResponse{
+		TransactionNumber: transaction.TransactionNumber,
+		CurrentStage:      transaction.CurrentStage,
+		BranchCode:        header.BranchCode,
+		Period:            header.Period,
+		TotalAssets:       len(items),
+		Missing:           []dto.StockOpnameVarianceLine{},
+		Relocated:         []dto.StockOpnameVarianceLine{},
+		Downgraded:        []dto.StockOpnameVarianceLine{},
+		FoundItems:        make([]dto.StockOpnameFoundItemResponse, 0, len(foundItems)),
+		ValueAtRisk:       []dto.StockOpnameCategoryValueRisk{},
+	}
+
+	risks := map[uint]*dto.StockOpnameCategoryValueRisk{}
+	var uncategorized *dto.StockOpnameCategoryValueRisk
+	riskFor := func(categoryID *uint) *dto.StockOpnameCategoryValueRisk {
+		if categoryID == nil {
+			if uncategorized == nil {
+				uncategorized = &dto.StockOpnameCategoryValueRisk{}
+			}
+			return uncategorized
+		}
+		if _, ok := risks[*categoryID]; !ok {
+			risks[*categoryID] = &dto.StockOpnameCategoryValueRisk{
+				CategoryID:   categoryID,
+				CategoryName: categoryName(categoryID),
+			}
+		}
+		return risks[*categoryID]
+	}
+
+	for _, item := range items {
+		// Belum dihitung → belum ada variance
+		if item.PhysicalStatus == "" || item.Asset == nil {
+			continue
+		}
+
+		snapshot := snapshotByAsset[item.AssetID]
+		line := dto.StockOpnameVarianceLine{
+			AssetID:            item.AssetID,
+			AssetNumber:        item.AssetNumber,
+			AssetName:          item.Asset.AssetName,
+			CategoryID:         item.Asset.CategoryID,
+			CategoryName:       categoryName(item.Asset.CategoryID),
+			BookValue:          snapshot.BookValue,
+			RegisterBranchCode: item.Asset.BranchCode,
+			RegisterLocation:   item.Asset.Location,
+			CountedBranchCode:  item.CountedBranchCode,
+			CountedLocation:    item.CountedLocation,
+			SystemCondition:    snapshot.Condition,
+			CountedCondition:   item.Condition,
+			PhysicalStatus:     item.PhysicalStatus,
+			Notes:              item.Notes,
+		}
+
+		if item.PhysicalStatus == models.PhysicalStatusMissing {
+			response.Missing = append(response.Missing, line)
+
+			risk := riskFor(line.CategoryID)
+			risk.MissingCount++
+			risk.MissingBookValue += line.BookValue
+			continue
+		}
+
+		if differsFromRegister(item.CountedBranchCode, item.Asset.BranchCode) ||
+			differsFromRegister(item.CountedLocation, item.Asset.Location) {
+			response.Relocated = append(response.Relocated, line)
+		}
+
+		if isConditionDowngrade(snapshot.Condition, item.Condition) {
+			response.Downgraded = append(response.Downgraded, line)
+
+			risk := riskFor(line.CategoryID)
+			risk.DowngradedCount++
+			risk.DowngradedBookValue += line.BookValue
+		}
+	}
+
+	for _, item := range foundItems {
+		response.FoundItems = append(response.FoundItems, mapFoundItemToResponse(item))
+	}
+
+	for _, risk := range risks {
+		response.ValueAtRisk = append(response.ValueAtRisk, *risk)
+	}
+	if uncategorized != nil {
+		response.ValueAtRisk = append(response.ValueAtRisk, *uncategorized)
+	}
+	for i := range response.ValueAtRisk {
+		risk := &response.ValueAtRisk[i]
+		risk.TotalBookValue = risk.MissingBookValue + risk.DowngradedBookValue
+		response.TotalValueAtRisk += risk.TotalBookValue
+	}
+	sort.SliceStable(response.ValueAtRisk, func(i, j int) bool {
+		return response.ValueAtRisk[i].TotalBookValue > response.ValueAtRisk[j].TotalBookValue
+	})
+
+	return response, nil
+}
+
+// ============================================================
+// FOLLOW UP
+// Dipanggil setelah hasil hitung disubmit (REVIEW / APPROVAL / RECONCILED).
+// Tiap draft dibuat lewat service mutasi / disposal biasa, jadi validasinya
+// sama (status AVAILABLE, tidak sedang di transaksi lain, dst).
+// Asset yang gagal dicatat di skipped, tidak membatalkan draft lain.
+// ============================================================
+
+func CreateStockOpnameFollowUp(userID string, transactionNumber string, req dto.CreateStockOpnameFollowUpRequest) (*dto.StockOpnameFollowUpResponse, error) {
+	if !req.Mutations && !req.Disposals {
+		return nil, errors.New("nothing to follow up, set mutations and/or disposals")
+	}
+
+	transaction, err := getStockOpnameTransaction(transactionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	switch transaction.CurrentStage {
+	case models.StageOpnameReview, models.StageOpnameApproval, models.StageOpnameReconciled:
+	default:
+		return nil, fmt.Errorf("follow up is only available after counting is submitted (current stage: %s)", transaction.CurrentStage)
+	}
+
+	variance, err := GetStockOpnameVariance(transactionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.StockOpnameFollowUpResponse{
+		Mutations: []dto.StockOpnameFollowUpDraft{},
+		Disposals: []dto.StockOpnameFollowUpDraft{},
+		Skipped:   []dto.StockOpnameFollowUpSkipped{},
+	}
+	skip := func(assetNumber, action, reason string) {
+		response.Skipped = append(response.Skipped, dto.StockOpnameFollowUpSkipped{
+			AssetNumber: assetNumber,
+			Action:      action,
+			Reason:      reason,
+		})
+	}
+
+	notes := fmt.Sprintf("Follow up stock opname %s", transactionNumber)
+
+	// ---- Kandidat disposal: MISSING + turun kondisi ke BROKEN ----
+	var disposals []dto.StockOpnameVarianceLine
+	disposalReasons := map[uint]string{}
+	if req.Disposals {
+		for _, line := range variance.Missing {
+			disposals = append(disposals, line)
+			disposalReasons[line.AssetID] = fmt.Sprintf("Stock opname %s: %s", transactionNumber, models.PhysicalStatusMissing)
+		}
+		for _, line := range variance.Downgraded {
+			if line.CountedCondition == models.ConditionBroken {
+				disposals = append(disposals, line)
+				disposalReasons[line.AssetID] = fmt.Sprintf("Stock opname %s: %s", transactionNumber, models.ConditionBroken)
+			}
+		}
+	}
+
+	// ---- Draft mutasi per (kategori, cabang ditemukan) ----
+	if req.Mutations {
+		type mutationGroup struct {
+			categoryID uint
+			toBranch   string
+			lines      []dto.StockOpnameVarianceLine
+		}
+		groups := map[string]*mutationGroup{}
+		var groupKeys []string
+
+		for _, line := range variance.Relocated {
+			if _, disposing := disposalReasons[line.AssetID]; disposing {
+				continue
+			}
+			if !differsFromRegister(line.CountedBranchCode, line.RegisterBranchCode) {
+				skip(line.AssetNumber, FollowUpMutation, "location changed within the same branch, update the asset location instead")
+				continue
+			}
+			if line.CategoryID == nil {
+				skip(line.AssetNumber, FollowUpMutation, "asset has no category")
+				continue
+			}
+
+			key := fmt.Sprintf("%d|%s", *line.CategoryID, *line.CountedBranchCode)
+			if _, ok := groups[key]; !ok {
+				groups[key] = &mutationGroup{categoryID: *line.CategoryID, toBranch: *line.CountedBranchCode}
+				groupKeys = append(groupKeys, key)
+			}
+			groups[key].lines = append(groups[key].lines, line)
+		}
+
+		for _, key := range groupKeys {
+			group := groups[key]
+			eligible := filterAvailableAssets(group.lines, FollowUpMutation, skip)
+			if len(eligible) == 0 {
+				continue
+			}
+
+			draft, err := CreateMutationDraft(userID, dto.CreateMutationDraftRequest{
+				TransactionDate: req.TransactionDate,
+				CategoryID:      group.categoryID,
+				ToBranchCode:    group.toBranch,
+				Notes:           &notes,
+			})
+			if err != nil {
+				for _, line := range eligible {
+					skip(line.AssetNumber, FollowUpMutation, err.Error())
+				}
+				continue
+			}
+
+			result := dto.StockOpnameFollowUpDraft{
+				TransactionNumber: draft.Transaction.TransactionNumber,
+				ToBranchCode:      &group.toBranch,
+				CategoryID:        &group.categoryID,
+				AssetNumbers:      []string{},
+			}
+			for _, line := range eligible {
+				if _, err := AddAssetToMutation(userID, result.TransactionNumber, dto.AddMutationAssetRequest{
+					AssetID:      line.AssetID,
+					AssetNumber:  line.AssetNumber,
+					FromLocation: line.RegisterLocation,
+					ToLocation:   line.CountedLocation,
+					Notes:        &notes,
+				}); err != nil {
+					skip(line.AssetNumber, FollowUpMutation, err.Error())
+					continue
+				}
+				result.AssetNumbers = append(result.AssetNumbers, line.AssetNumber)
+			}
+			response.Mutations = append(response.Mutations, result)
+		}
+	}
+
+	// ---- Satu draft disposal ----
+	if req.Disposals {
+		eligible := filterAvailableAssets(disposals, FollowUpDisposal, skip)
+		if len(eligible) > 0 {
+			disposalType := models.DisposalTypeDispose
+			if req.DisposalType != nil {
+				disposalType = *req.DisposalType
+			}
+
+			draft, err := CreateDisposalDraft(userID, dto.CreateDisposalDraftRequest{
+				TransactionDate: req.TransactionDate,
+				DisposalType:    disposalType,
+				Notes:           &notes,
+			})
+			if err != nil {
+				for _, line := range eligible {
+					skip(line.AssetNumber, FollowUpDisposal, err.Error())
+				}
+			} else {
+				result := dto.StockOpnameFollowUpDraft{
+					TransactionNumber: draft.Transaction.TransactionNumber,
+					AssetNumbers:      []string{},
+				}
+				for _, line := range eligible {
+					reason := disposalReasons[line.AssetID]
+					if _, err := AddAssetToDisposal(userID, result.TransactionNumber, dto.AddDisposalAssetRequest{
+						AssetID:        line.AssetID,
+						AssetNumber:    line.AssetNumber,
+						DisposalReason: &reason,
+						Notes:          &notes,
+					}); err != nil {
+						skip(line.AssetNumber, FollowUpDisposal, err.Error())
+						continue
+					}
+					result.AssetNumbers = append(result.AssetNumbers, line.AssetNumber)
+				}
+				response.Disposals = append(response.Disposals, result)
+			}
+		}
+	}
+
+	return response, nil
+}
+
+// filterAvailableAssets - hanya asset AVAILABLE yang bisa masuk draft mutasi / disposal,
+// dicek dulu supaya tidak ada draft kosong
+func filterAvailableAssets(lines []dto.StockOpnameVarianceLine, action string, skip func(assetNumber, action, reason string)) []dto.StockOpnameVarianceLine {
+	if len(lines) == 0 {
+		return nil
+	}
+
+	assetIDs := make([]uint, 0, len(lines))
+	for _, line := range lines {
+		assetIDs = append(assetIDs, line.AssetID)
+	}
+
+	var assets []models.Asset
+	config.DB.Select("id", "asset_status").Where("id IN ?", assetIDs).Find(&assets)
+	statuses := make(map[uint]string, len(assets))
+	for _, asset := range assets {
+		statuses[asset.ID] = asset.AssetStatus
+	}
+
+	eligible := make([]dto.StockOpnameVarianceLine, 0, len(lines))
+	for _, line := range lines {
+		if status := statuses[line.AssetID]; status != models.AssetStatusAvailable {
+			skip(line.AssetNumber, action, fmt.Sprintf("asset status is %s", status))
+			continue
+		}
+		eligible = append(eligible, line)
+	}
+	return eligible
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ============================================================
+// STOCK OPNAME PER CABANG
+// Alternatif CreateStockOpname: client cukup kirim branch_code + period,
+// semua asset cabang yang belum DISPOSED di-snapshot ke asset_stock_opnames
+// (book value, lokasi, grouping, kategori) dan transaksi langsung masuk
+// stage COUNTING. Tim lapangan tinggal isi hasil hitung fisik lewat
+// PUT /counting/counts.
+//
+// Satu asset hanya boleh ada di satu opname terbuka (DRAFT / LOCKED)
+// per period.
+// ============================================================
+
+const stockOpnameBatchSize = 200
+
+func StartBranchStockOpname(userID string, req dto.StartBranchStockOpnameRequest, meta dto.RequestMeta) (*dto.StockOpnameResponse, error) {
+	// ---- VALIDASI DULU SEBELUM GENERATE NOMOR TRANSAKSI ----
+	if _, err := time.Parse("2006-01", req.Period); err != nil {
+		return nil, errors.New("invalid period format, use YYYY-MM")
+	}
+
+	transactionDate := time.Now().Truncate(24 * time.Hour)
+	if req.TransactionDate != nil && *req.TransactionDate != "" {
+		parsed, err := time.Parse("2006-01-02", *req.TransactionDate)
+		if err != nil {
+			return nil, errors.New("invalid transaction date format, use YYYY-MM-DD")
+		}
+		transactionDate = parsed
+	}
+
+	if err := validateBranchExists(req.BranchCode); err != nil {
+		return nil, err
+	}
+
+	transactionNumber, err := GenerateTransactionNumber(userID, TxStockOpname)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := config.DB.Begin()
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// Lock asset cabang supaya dua opname paralel untuk cabang yang sama tidak lolos bersamaan
+	var assets []models.Asset
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("branch_code = ? AND asset_status <> ? AND deleted_at IS NULL",
+			req.BranchCode, models.AssetStatusDisposed).
+		Order("asset_number ASC").
+		Find(&assets).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if len(assets) == 0 {
+		tx.Rollback()
+		return nil, fmt.Errorf("no active assets found for branch %s", req.BranchCode)
+	}
+
+	assetIDs := make([]uint, 0, len(assets))
+	for _, asset := range assets {
+		assetIDs = append(assetIDs, asset.ID)
+	}
+	if err := ensureAssetsNotInOpenOpname(tx, req.Period, assetIDs); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	transaction := models.Transaction{
+		TransactionNumber: transactionNumber,
+		TransactionType:   TxStockOpname,
+		TransactionDate:   transactionDate,
+		Status:            models.TransactionStatusDraft,
+		CurrentStage:      models.StageOpnameDraft,
+		Notes:             req.Notes,
+		CreatedBy:         userID,
+	}
+	if err := tx.Create(&transaction).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	branchCode := req.BranchCode
+	header := models.StockOpname{
+		TransactionID:     &transaction.ID,
+		TransactionNumber: &transaction.TransactionNumber,
+		BranchCode:        &branchCode,
+		DocumentNumber:    transactionNumber,
+		OpnameDate:        transactionDate,
+		Period:            req.Period,
+		Status:            models.StockOpnameStatusDraft,
+		Notes:             derefString(req.Notes),
+		CreatedBy:         userID,
+	}
+	if err := tx.Create(&header).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Asset value aktif diambil sekaligus, bukan per asset
+	var values []models.AssetValue
+	if err := tx.Where("asset_id IN ? AND is_active = ?", assetIDs, true).
+		Order("effective_date ASC").
+		Find(&values).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	activeValues := make(map[uint]*models.AssetValue, len(values))
+	for i := range values {
+		activeValues[values[i].AssetID] = &values[i] // effective_date terbaru menang
+	}
+
+	lines := make([]models.TransactionStockOpname, 0, len(assets))
+	snapshots := make([]models.AssetStockOpname, 0, len(assets))
+	for _, asset := range assets {
+		// physical_status & condition kosong = belum dihitung
+		lines = append(lines, models.TransactionStockOpname{
+			TransactionID:     transaction.ID,
+			TransactionNumber: transactionNumber,
+			AssetID:           asset.ID,
+			AssetNumber:       asset.AssetNumber,
+			AssetStatus:       asset.AssetStatus,
+		})
+		snapshots = append(snapshots, buildAssetOpnameSnapshot(header, transaction, asset, activeValues[asset.ID]))
+	}
+
+	if err := tx.CreateInBatches(&lines, stockOpnameBatchSize).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.CreateInBatches(&snapshots, stockOpnameBatchSize).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := updateTransactionStage(tx, &transaction, models.StageOpnameCounting); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := recordStage(tx, transaction.ID, transactionNumber,
+		models.StageOpnameDraft, models.StageOpnameCounting,
+		models.ActionStartCounting, userID, nil, req.Notes, meta); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return GetStockOpnameByTransactionNumber(transactionNumber)
+}
+
+// ensureAssetsNotInOpenOpname - tolak kalau ada asset yang sudah masuk worksheet
+// opname lain (DRAFT / LOCKED) di period yang sama
+func ensureAssetsNotInOpenOpname(tx *gorm.DB, period string, assetIDs []uint) error {
+	if len(assetIDs) == 0 {
+		return nil
+	}
+
+	var conflicts []struct {
+		AssetNumber    string
+		DocumentNumber string
+	}
+	if err := tx.Table("asset_stock_opnames").
+		Select("asset_stock_opnames.asset_number, stock_opnames.document_number").
+		Joins("JOIN stock_opnames ON stock_opnames.id = asset_stock_opnames.stock_opname_id").
+		Where("stock_opnames.period = ? AND stock_opnames.status IN ?", period,
+			[]string{models.StockOpnameStatusDraft, models.StockOpnameStatusLocked}).
+		Where("asset_stock_opnames.asset_id IN ?", assetIDs).
+		Order("asset_stock_opnames.asset_number ASC").
+		Scan(&conflicts).Error; err != nil {
+		return err
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	const maxListed = 10
+	listed := make([]string, 0, maxListed)
+	for i, c := range conflicts {
+		if i == maxListed {
+			listed = append(listed, fmt.Sprintf("and %d more", len(conflicts)-maxListed))
+			break
+		}
+		listed = append(listed, fmt.Sprintf("%s (%s)", c.AssetNumber, c.DocumentNumber))
+	}
+
+	return fmt.Errorf("%d asset(s) already in an open stock opname for period %s: %s",
+		len(conflicts), period, strings.Join(listed, ", "))
+}
+
+// ============================================================
+// WORKSHEET
+// Snapshot data sistem + hasil hitung per asset
+// ============================================================
+
+func GetStockOpnameWorksheet(transactionNumber string) (*dto.StockOpnameWorksheetResponse, error) {
+	transaction, err := getStockOpnameTransaction(transactionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := getStockOpnameHeader(config.DB, transaction.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []models.AssetStockOpname
+	if err := config.DB.
+		Where("stock_opname_id = ?", header.ID).
+		Order("asset_number ASC").
+		Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+
+	var items []models.TransactionStockOpname
+	if err := config.DB.Where("transaction_id = ?", transaction.ID).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[uint]models.TransactionStockOpname, len(items))
+	for _, item := range items {
+		counts[item.AssetID] = item
+	}
+
+	response := &dto.StockOpnameWorksheetResponse{
+		ID:                header.ID,
+		TransactionID:     header.TransactionID,
+		TransactionNumber: header.TransactionNumber,
+		DocumentNumber:    header.DocumentNumber,
+		BranchCode:        header.BranchCode,
+		Period:            header.Period,
+		OpnameDate:        header.OpnameDate,
+		Status:            header.Status,
+		TotalAssets:       len(snapshots),
+		Lines:             make([]dto.StockOpnameWorksheetLine, 0, len(snapshots)),
+	}
+
+	for _, snapshot := range snapshots {
+		line := dto.StockOpnameWorksheetLine{
+			AssetStockOpnameID:   snapshot.ID,
+			AssetID:              snapshot.AssetID,
+			AssetNumber:          snapshot.AssetNumber,
+			AssetName:            snapshot.AssetName,
+			CategoryID:           snapshot.CategoryID,
+			BranchCode:           snapshot.BranchCode,
+			Location:             snapshot.Location,
+			Grouping:             snapshot.Grouping,
+			BookValue:            snapshot.BookValue,
+			SystemCondition:      snapshot.Condition,
+			SystemPhysicalStatus: snapshot.PhysicalStatus,
+			SystemAssetStatus:    snapshot.AssetStatus,
+		}
+
+		if item, ok := counts[snapshot.AssetID]; ok {
+			line.PhysicalStatus = item.PhysicalStatus
+			line.Condition = item.Condition
+			line.AssetStatus = item.AssetStatus
+			line.CountedBranchCode = item.CountedBranchCode
+			line.CountedLocation = item.CountedLocation
+			line.Notes = item.Notes
+			line.CountedAt = item.CountedAt
+			line.Counted = item.PhysicalStatus != "" && item.Condition != ""
+		}
+		if line.Counted {
+			response.CountedAssets++
+		}
+
+		response.Lines = append(response.Lines, line)
+	}
+
+	return response, nil
+}
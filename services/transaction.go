@@ -37,24 +37,7 @@ func GetTransactionByNumber(transactionNumber string) (*dto.TransactionHeaderRes
 
 // GetAllTransactions gets all transactions with filters
 func GetAllTransactions(filter dto.TransactionListFilter) ([]dto.TransactionHeaderResponse, int64, error) {
-	query := config.DB.Model(&models.Transaction{})
-
-	// Apply filters
-	if filter.TransactionType != nil {
-		query = query.Where("transaction_type = ?", *filter.TransactionType)
-	}
-
-	if filter.Status != nil {
-		query = query.Where("status = ?", *filter.Status)
-	}
-
-	if filter.StartDate != nil {
-		query = query.Where("transaction_date >= ?", *filter.StartDate)
-	}
-
-	if filter.EndDate != nil {
-		query = query.Where("transaction_date <= ?", *filter.EndDate)
-	}
+	query := applyTransactionListFilter(config.DB.Model(&models.Transaction{}), filter)
 
 	// Count total
 	var total int64
@@ -79,6 +62,29 @@ func GetAllTransactions(filter dto.TransactionListFilter) ([]dto.TransactionHead
 	return mapTransactionHeadersToResponse(transactions), total, nil
 }
 
+// applyTransactionListFilter - dipakai list & export
+func applyTransactionListFilter(query *gorm.DB, filter dto.TransactionListFilter) *gorm.DB {
+	if filter.TransactionType != nil {
+		query = query.Where("transactions.transaction_type = ?", *filter.TransactionType)
+	}
+	if filter.Status != nil {
+		query = query.Where("transactions.status = ?", *filter.Status)
+	}
+	if filter.CurrentStage != nil {
+		query = query.Where("transactions.current_stage = ?", *filter.CurrentStage)
+	}
+	if filter.CreatedBy != nil {
+		query = query.Where("transactions.created_by = ?", *filter.CreatedBy)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("transactions.transaction_date >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("transactions.transaction_date <= ?", *filter.EndDate)
+	}
+	return query
+}
+
 // GetTransactionsByUser gets transactions created by specific user
 func GetTransactionsByUser(userID string, filter dto.TransactionListFilter) ([]dto.TransactionHeaderResponse, int64, error) {
 	query := config.DB.Model(&models.Transaction{}).
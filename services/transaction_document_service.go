@@ -0,0 +1,398 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"backend-go/utils"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ============================================================
+// TRANSACTION DOCUMENT
+// PDF resmi untuk procurement, mutasi (serah terima), disposal dan stock opname.
+// Tanda tangan diambil dari ApprovalSignature status signed yang masih is_recent;
+// id signature tersebut ikut dihitung ke kode verifikasi di QR.
+// ============================================================
+
+var transactionDocumentTitles = map[string]string{
+	TxProcurement:  "Dokumen Pengadaan Aset",
+	TxMutationFlow: "Berita Acara Serah Terima Aset",
+	TxDisposalFlow: "Berita Acara Penghapusan Aset",
+	TxStockOpname:  "Berita Acara Stock Opname",
+}
+
+var procurementDocumentColumns = []utils.ExportColumn{
+	{Header: "Item", Type: utils.ExportText, Width: 28},
+	{Header: "Category", Type: utils.ExportText, Width: 16},
+	{Header: "Branch", Type: utils.ExportText, Width: 10},
+	{Header: "Qty", Type: utils.ExportNumber, Width: 6},
+	{Header: "Unit Price", Type: utils.ExportMoney, Width: 14},
+	{Header: "Total", Type: utils.ExportMoney, Width: 16},
+}
+
+var mutationDocumentColumns = []utils.ExportColumn{
+	{Header: "Asset Number", Type: utils.ExportText, Width: 16},
+	{Header: "Asset Name", Type: utils.ExportText, Width: 24},
+	{Header: "From", Type: utils.ExportText, Width: 10},
+	{Header: "To", Type: utils.ExportText, Width: 10},
+	{Header: "To Location", Type: utils.ExportText, Width: 16},
+	{Header: "Document", Type: utils.ExportText, Width: 12},
+	{Header: "Status", Type: utils.ExportText, Width: 10},
+}
+
+var disposalDocumentColumns = []utils.ExportColumn{
+	{Header: "Asset Number", Type: utils.ExportText, Width: 16},
+	{Header: "Asset Name", Type: utils.ExportText, Width: 22},
+	{Header: "Type", Type: utils.ExportText, Width: 8},
+	{Header: "Reason", Type: utils.ExportText, Width: 22},
+	{Header: "Sale Value", Type: utils.ExportMoney, Width: 14},
+	{Header: "Status", Type: utils.ExportText, Width: 10},
+}
+
+var stockOpnameDocumentColumns = []utils.ExportColumn{
+	{Header: "Asset Number", Type: utils.ExportText, Width: 16},
+	{Header: "Asset Name", Type: utils.ExportText, Width: 24},
+	{Header: "Physical", Type: utils.ExportText, Width: 10},
+	{Header: "Condition", Type: utils.ExportText, Width: 10},
+	{Header: "Counted Branch", Type: utils.ExportText, Width: 10},
+	{Header: "Counted Location", Type: utils.ExportText, Width: 16},
+	{Header: "Counted At", Type: utils.ExportDateTime, Width: 14},
+}
+
+// GetTransactionDocumentPDF - PDF dokumen resmi untuk transaction number
+func GetTransactionDocumentPDF(transactionNumber string) ([]byte, error) {
+	var transaction models.Transaction
+	if err := config.DB.Where("transaction_number = ?", transactionNumber).First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transaction not found")
+		}
+		return nil, err
+	}
+
+	title, ok := transactionDocumentTitles[transaction.TransactionType]
+	if !ok {
+		return nil, fmt.Errorf("document is not available for transaction type %s", transaction.TransactionType)
+	}
+
+	signatures, err := loadDocumentSignatures(transaction.TransactionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := utils.TransactionDocument{
+		Title:             title,
+		TransactionNumber: transaction.TransactionNumber,
+		VerificationCode:  documentCodeForSignatures(transaction.TransactionNumber, signatures),
+		Fields:            transactionDocumentFields(transaction),
+		Signatures:        mapDocumentSignatures(signatures),
+	}
+
+	if doc.IONumbers, err = loadDocumentIONumbers(transaction); err != nil {
+		return nil, err
+	}
+
+	switch transaction.TransactionType {
+	case TxProcurement:
+		err = fillProcurementDocument(&doc, transaction)
+	case TxMutationFlow:
+		err = fillMutationDocument(&doc, transaction)
+	case TxDisposalFlow:
+		err = fillDisposalDocument(&doc, transaction)
+	case TxStockOpname:
+		err = fillStockOpnameDocument(&doc, transaction)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return utils.RenderTransactionDocumentPDF(doc)
+}
+
+// VerifyTransactionDocument - target QR dokumen. Detail transaksi hanya
+// dikembalikan kalau kode cocok dengan tanda tangan yang berlaku sekarang.
+func VerifyTransactionDocument(req dto.TransactionDocumentVerifyRequest) (*dto.TransactionDocumentVerifyResponse, error) {
+	var transaction models.Transaction
+	if err := config.DB.Where("transaction_number = ?", req.Number).First(&transaction).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transaction not found")
+		}
+		return nil, err
+	}
+
+	signatures, err := loadDocumentSignatures(transaction.TransactionNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := documentCodeForSignatures(transaction.TransactionNumber, signatures)
+	if !utils.TransactionDocumentCodeEqual(expected, req.Code) {
+		return &dto.TransactionDocumentVerifyResponse{
+			Valid:             false,
+			Message:           "verification code does not match, the document may be altered or outdated",
+			TransactionNumber: transaction.TransactionNumber,
+		}, nil
+	}
+
+	response := &dto.TransactionDocumentVerifyResponse{
+		Valid:             true,
+		Message:           "document is valid",
+		TransactionNumber: transaction.TransactionNumber,
+		TransactionType:   transaction.TransactionType,
+		TransactionDate:   &transaction.TransactionDate,
+		Status:            transaction.Status,
+		CurrentStage:      transaction.CurrentStage,
+		Signatures:        make([]dto.TransactionDocumentSignatureResponse, len(signatures)),
+	}
+	for i, signature := range signatures {
+		item := dto.TransactionDocumentSignatureResponse{
+			Name:     signatureDisplayName(signature),
+			StepRole: signature.StepRole,
+			SignedAt: signature.SignedAt,
+		}
+		if signature.Role != nil {
+			item.RoleName = &signature.Role.Name
+		}
+		if signature.OnBehalfOf != nil {
+			item.OnBehalfOfName = &signature.OnBehalfOf.Fullname
+		}
+		response.Signatures[i] = item
+	}
+
+	return response, nil
+}
+
+func loadDocumentSignatures(transactionNumber string) ([]models.ApprovalSignature, error) {
+	var signatures []models.ApprovalSignature
+	if err := config.DB.
+		Preload("User").
+		Preload("OnBehalfOf").
+		Preload("Role").
+		Where("transaction_number = ? AND status = ? AND is_recent = ?", transactionNumber, "signed", true).
+		Order("signed_at ASC").
+		Find(&signatures).Error; err != nil {
+		return nil, err
+	}
+	return signatures, nil
+}
+
+func documentCodeForSignatures(transactionNumber string, signatures []models.ApprovalSignature) string {
+	ids := make([]string, len(signatures))
+	for i, signature := range signatures {
+		ids[i] = signature.ID
+	}
+	return utils.TransactionDocumentCode(transactionNumber, ids)
+}
+
+func signatureDisplayName(signature models.ApprovalSignature) string {
+	if signature.User != nil {
+		return signature.User.Fullname
+	}
+	return "System"
+}
+
+// documentStepTitle - "approver" → "Approver"
+func documentStepTitle(stepRole string) string {
+	if stepRole == "" {
+		return ""
+	}
+	return strings.ToUpper(stepRole[:1]) + stepRole[1:]
+}
+
+func mapDocumentSignatures(signatures []models.ApprovalSignature) []utils.DocumentSignature {
+	result := make([]utils.DocumentSignature, len(signatures))
+	for i, signature := range signatures {
+		item := utils.DocumentSignature{
+			Title:    documentStepTitle(signature.StepRole),
+			Name:     signatureDisplayName(signature),
+			SignedAt: signature.SignedAt,
+		}
+		if signature.Role != nil {
+			item.Role = signature.Role.Name
+		}
+		if signature.OnBehalfOf != nil {
+			item.OnBehalfOf = signature.OnBehalfOf.Fullname
+		}
+		// File tanda tangan yang hilang tidak menggagalkan dokumen, kotak dibiarkan kosong
+		if signature.SignaturePath != nil && *signature.SignaturePath != "" {
+			if image, err := os.ReadFile(*signature.SignaturePath); err == nil {
+				item.Image = image
+			}
+		}
+		result[i] = item
+	}
+	return result
+}
+
+func transactionDocumentFields(transaction models.Transaction) []utils.DocumentField {
+	fields := []utils.DocumentField{
+		{Label: "Tanggal", Value: transaction.TransactionDate.Format("2006-01-02")},
+		{Label: "Status", Value: transaction.Status},
+		{Label: "Stage", Value: transaction.CurrentStage},
+		{Label: "Dibuat oleh", Value: documentUserName(transaction.CreatedBy)},
+	}
+
+	if transaction.ApprovedAt != nil {
+		fields = append(fields, utils.DocumentField{
+			Label: "Disetujui",
+			Value: documentUserName(derefString(transaction.ApprovedBy)) + " (" + transaction.ApprovedAt.Format("2006-01-02 15:04") + ")",
+		})
+	}
+
+	switch transaction.TransactionType {
+	case TxMutationFlow:
+		fields = append(fields, utils.DocumentField{Label: "Cabang Tujuan", Value: derefString(transaction.MutationToBranchCode)})
+		if transaction.MutationCategoryID != nil {
+			var category models.AssetCategory
+			if err := config.DB.First(&category, *transaction.MutationCategoryID).Error; err == nil {
+				fields = append(fields, utils.DocumentField{Label: "Kategori", Value: category.CategoryName})
+			}
+		}
+	case TxDisposalFlow:
+		fields = append(fields,
+			utils.DocumentField{Label: "Tipe Disposal", Value: derefString(transaction.DisposalType)},
+			utils.DocumentField{Label: "No. Permohonan", Value: derefString(transaction.ApprovalRequestNumber)},
+			utils.DocumentField{Label: "No. Persetujuan", Value: derefString(transaction.ApprovalAgreementNumber)},
+		)
+		if transaction.SaleValue != nil {
+			fields = append(fields, utils.DocumentField{Label: "Nilai Jual", Value: "Rp " + utils.FormatIDR(*transaction.SaleValue)})
+		}
+	}
+
+	fields = append(fields, utils.DocumentField{Label: "Catatan", Value: derefString(transaction.Notes)})
+	return fields
+}
+
+// documentUserName - fullname dari user id, fallback ke nilai aslinya
+func documentUserName(userID string) string {
+	if userID == "" {
+		return ""
+	}
+	var user models.User
+	if err := config.DB.Select("fullname").Where("id = ?", userID).First(&user).Error; err != nil {
+		return userID
+	}
+	return user.Fullname
+}
+
+// loadDocumentIONumbers - IO per branch; transaksi lama hanya punya transactions.io_number
+func loadDocumentIONumbers(transaction models.Transaction) ([]utils.DocumentField, error) {
+	var ioNumbers []models.TransactionIONumber
+	if err := config.DB.
+		Where("transaction_id = ?", transaction.ID).
+		Order("branch_code ASC").
+		Find(&ioNumbers).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]utils.DocumentField, 0, len(ioNumbers))
+	for _, io := range ioNumbers {
+		result = append(result, utils.DocumentField{Label: io.BranchCode, Value: io.IONumber})
+	}
+	if len(result) == 0 && transaction.IONumber != nil && *transaction.IONumber != "" {
+		result = append(result, utils.DocumentField{Label: "-", Value: *transaction.IONumber})
+	}
+	return result, nil
+}
+
+func fillProcurementDocument(doc *utils.TransactionDocument, transaction models.Transaction) error {
+	var items []models.TransactionProcurement
+	if err := config.DB.
+		Preload("Category").
+		Where("transaction_id = ?", transaction.ID).
+		Order("id ASC").
+		Find(&items).Error; err != nil {
+		return err
+	}
+
+	doc.ItemColumns = procurementDocumentColumns
+	grandTotal := 0.0
+	for _, item := range items {
+		var categoryName string
+		if item.Category != nil {
+			categoryName = item.Category.CategoryName
+		}
+		doc.Items = append(doc.Items, []interface{}{
+			item.ItemName, categoryName, item.BranchCode, item.Quantity, item.UnitPrice, item.TotalPrice,
+		})
+		grandTotal += item.TotalPrice
+	}
+	doc.Fields = append(doc.Fields, utils.DocumentField{Label: "Total", Value: "Rp " + utils.FormatIDR(grandTotal)})
+	return nil
+}
+
+func fillMutationDocument(doc *utils.TransactionDocument, transaction models.Transaction) error {
+	var items []models.TransactionMutationAsset
+	if err := config.DB.
+		Preload("Asset").
+		Where("transaction_id = ?", transaction.ID).
+		Order("id ASC").
+		Find(&items).Error; err != nil {
+		return err
+	}
+
+	doc.ItemColumns = mutationDocumentColumns
+	for _, item := range items {
+		var assetName string
+		if item.Asset != nil {
+			assetName = item.Asset.AssetName
+		}
+		doc.Items = append(doc.Items, []interface{}{
+			item.AssetNumber, assetName, item.FromBranchCode, item.ToBranchCode,
+			item.ToLocation, item.DocumentNumber, item.Status,
+		})
+	}
+	return nil
+}
+
+func fillDisposalDocument(doc *utils.TransactionDocument, transaction models.Transaction) error {
+	var items []models.TransactionDisposalAsset
+	if err := config.DB.
+		Preload("Asset").
+		Where("transaction_id = ?", transaction.ID).
+		Order("id ASC").
+		Find(&items).Error; err != nil {
+		return err
+	}
+
+	doc.ItemColumns = disposalDocumentColumns
+	for _, item := range items {
+		var assetName string
+		if item.Asset != nil {
+			assetName = item.Asset.AssetName
+		}
+		doc.Items = append(doc.Items, []interface{}{
+			item.AssetNumber, assetName, item.DisposalType, item.DisposalReason, item.SaleValue, item.Status,
+		})
+	}
+	return nil
+}
+
+func fillStockOpnameDocument(doc *utils.TransactionDocument, transaction models.Transaction) error {
+	var items []models.TransactionStockOpname
+	if err := config.DB.
+		Preload("Asset").
+		Where("transaction_id = ?", transaction.ID).
+		Order("asset_number ASC").
+		Find(&items).Error; err != nil {
+		return err
+	}
+
+	doc.ItemColumns = stockOpnameDocumentColumns
+	for _, item := range items {
+		var assetName string
+		if item.Asset != nil {
+			assetName = item.Asset.AssetName
+		}
+		doc.Items = append(doc.Items, []interface{}{
+			item.AssetNumber, assetName, item.PhysicalStatus, item.Condition,
+			item.CountedBranchCode, item.CountedLocation, item.CountedAt,
+		})
+	}
+	return nil
+}
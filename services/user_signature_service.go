@@ -0,0 +1,278 @@
+package services
+
+import (
+	"backend-go/config"
+	"backend-go/dto"
+	"backend-go/models"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Tanda tangan disimpan di {AttachmentStoragePath}/signatures/{user_id}/
+// Setiap upload / gambar baru = versi baru, versi lama dinonaktifkan tapi filenya tetap ada
+// karena ApprovalSignature.SignaturePath dokumen lama masih menunjuk ke sana
+const maxUserSignatureSize = 2 << 20 // 2 MB
+
+var pngMagic = []byte{0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'}
+
+// UploadUserSignature - upload file tanda tangan (PNG / JPEG)
+func UploadUserSignature(userID string, file multipart.File, fileHeader *multipart.FileHeader) (*dto.UserSignatureResponse, error) {
+	if fileHeader.Size > maxUserSignatureSize {
+		return nil, errors.New("signature image must not exceed 2 MB")
+	}
+
+	data, err := io.ReadAll(io.LimitReader(file, maxUserSignatureSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	mimeType, err := detectSignatureMimeType(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return createUserSignatureVersion(userID, data, mimeType, models.SignatureSourceUpload)
+}
+
+// SaveDrawnUserSignature - tanda tangan hasil gambar di aplikasi, wajib PNG
+func SaveDrawnUserSignature(userID string, req dto.DrawUserSignatureRequest) (*dto.UserSignatureResponse, error) {
+	encoded := strings.TrimSpace(req.ImageBase64)
+	if idx := strings.Index(encoded, ","); idx >= 0 && strings.HasPrefix(encoded, "data:") {
+		encoded = encoded[idx+1:]
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("image_base64 is not valid base64")
+	}
+
+	if !bytes.HasPrefix(data, pngMagic) {
+		return nil, errors.New("drawn signature must be a PNG image")
+	}
+
+	return createUserSignatureVersion(userID, data, "image/png", models.SignatureSourceDrawn)
+}
+
+func createUserSignatureVersion(userID string, data []byte, mimeType, source string) (*dto.UserSignatureResponse, error) {
+	if len(data) == 0 {
+		return nil, errors.New("signature image is empty")
+	}
+	if len(data) > maxUserSignatureSize {
+		return nil, errors.New("signature image must not exceed 2 MB")
+	}
+
+	var signature models.UserSignature
+	var filePath string
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock user supaya dua upload bersamaan tidak dapat nomor versi yang sama
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&user, "id = ?", userID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("user not found")
+			}
+			return err
+		}
+
+		var lastVersion int
+		if err := tx.Model(&models.UserSignature{}).
+			Where("user_id = ?", userID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&lastVersion).Error; err != nil {
+			return err
+		}
+
+		version := lastVersion + 1
+		path, err := storeUserSignatureFile(userID, version, mimeType, data)
+		if err != nil {
+			return err
+		}
+		filePath = path
+
+		if err := tx.Model(&models.UserSignature{}).
+			Where("user_id = ? AND is_active = ?", userID, true).
+			Update("is_active", false).Error; err != nil {
+			return err
+		}
+
+		signature = models.UserSignature{
+			UserID:   userID,
+			Version:  version,
+			FilePath: filePath,
+			MimeType: mimeType,
+			FileSize: int64(len(data)),
+			Source:   source,
+			IsActive: true,
+		}
+		return tx.Create(&signature).Error
+	})
+	if err != nil {
+		// Hapus file kalau DB error
+		if filePath != "" {
+			os.Remove(filePath)
+		}
+		return nil, err
+	}
+
+	response := mapUserSignatureToResponse(signature)
+	return &response, nil
+}
+
+// GetUserSignatures - semua versi tanda tangan user, terbaru di atas
+func GetUserSignatures(userID string) ([]dto.UserSignatureResponse, error) {
+	var signatures []models.UserSignature
+	if err := config.DB.
+		Where("user_id = ?", userID).
+		Order("version DESC").
+		Find(&signatures).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.UserSignatureResponse, 0, len(signatures))
+	for _, signature := range signatures {
+		result = append(result, mapUserSignatureToResponse(signature))
+	}
+	return result, nil
+}
+
+// ActivateUserSignature - pakai kembali versi lama sebagai tanda tangan aktif
+func ActivateUserSignature(userID, signatureID string) (*dto.UserSignatureResponse, error) {
+	var signature models.UserSignature
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND user_id = ?", signatureID, userID).
+			First(&signature).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("signature not found")
+			}
+			return err
+		}
+
+		if err := tx.Model(&models.UserSignature{}).
+			Where("user_id = ? AND id <> ? AND is_active = ?", userID, signatureID, true).
+			Update("is_active", false).Error; err != nil {
+			return err
+		}
+
+		signature.IsActive = true
+		return tx.Model(&signature).Update("is_active", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	response := mapUserSignatureToResponse(signature)
+	return &response, nil
+}
+
+// DeactivateUserSignature - user tidak punya tanda tangan aktif lagi.
+// File tidak dihapus, dokumen lama tetap bisa dicetak dengan tanda tangannya.
+func DeactivateUserSignature(userID, signatureID string) error {
+	result := config.DB.Model(&models.UserSignature{}).
+		Where("id = ? AND user_id = ?", signatureID, userID).
+		Update("is_active", false)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		var count int64
+		config.DB.Model(&models.UserSignature{}).
+			Where("id = ? AND user_id = ?", signatureID, userID).
+			Count(&count)
+		if count == 0 {
+			return errors.New("signature not found")
+		}
+	}
+	return nil
+}
+
+// GetUserSignatureImage - isi file tanda tangan untuk preview di profil
+func GetUserSignatureImage(userID, signatureID string) ([]byte, string, error) {
+	var signature models.UserSignature
+	if err := config.DB.
+		Where("id = ? AND user_id = ?", signatureID, userID).
+		First(&signature).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", errors.New("signature not found")
+		}
+		return nil, "", err
+	}
+
+	data, err := os.ReadFile(signature.FilePath)
+	if err != nil {
+		return nil, "", errors.New("signature file not found")
+	}
+	return data, signature.MimeType, nil
+}
+
+// getActiveUserSignature - nil kalau user belum punya tanda tangan aktif
+func getActiveUserSignature(db *gorm.DB, userID string) (*models.UserSignature, error) {
+	var signature models.UserSignature
+	err := db.
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Order("version DESC").
+		First(&signature).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &signature, nil
+}
+
+func storeUserSignatureFile(userID string, version int, mimeType string, data []byte) (string, error) {
+	dirPath := filepath.Join(AttachmentStoragePath, "signatures", sanitizePathSegment(userID))
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	ext := ".png"
+	if mimeType == "image/jpeg" {
+		ext = ".jpg"
+	}
+	fileName := fmt.Sprintf("v%d_%s%s", version, time.Now().Format("20060102150405"), ext)
+	filePath := filepath.Join(dirPath, fileName)
+
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to save file: %w", err)
+	}
+	return filePath, nil
+}
+
+// detectSignatureMimeType cek isi file, bukan extension — hanya PNG / JPEG yang bisa ditempel di PDF
+func detectSignatureMimeType(data []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(data, pngMagic):
+		return "image/png", nil
+	case bytes.HasPrefix(data, []byte{0xff, 0xd8, 0xff}):
+		return "image/jpeg", nil
+	default:
+		return "", errors.New("signature must be a PNG or JPEG image")
+	}
+}
+
+func mapUserSignatureToResponse(signature models.UserSignature) dto.UserSignatureResponse {
+	return dto.UserSignatureResponse{
+		ID:        signature.ID,
+		UserID:    signature.UserID,
+		Version:   signature.Version,
+		MimeType:  signature.MimeType,
+		FileSize:  signature.FileSize,
+		Source:    signature.Source,
+		IsActive:  signature.IsActive,
+		CreatedAt: signature.CreatedAt,
+	}
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+)
+
+// ============================================================================
+// EMAIL TEMPLATES
+// email_templates/<lang>/<transaction_type>.txt  - subject + body text
+// email_templates/<lang>/<transaction_type>.html - body html
+// email_templates/layout.html                    - kerangka html bersama
+//
+// Setiap file mendefinisikan template per event:
+//   {{define "<event>.subject"}} (hanya .txt) dan {{define "<event>"}}
+// Bahasa yang didukung: id (default), en
+// ============================================================================
+
+//go:embed email_templates
+var emailTemplateFS embed.FS
+
+const DefaultEmailLanguage = "id"
+
+var EmailLanguages = []string{"id", "en"}
+
+// EmailTemplateData - variabel yang tersedia di semua template email
+type EmailTemplateData struct {
+	RecipientName     string
+	TransactionNumber string
+	TransactionType   string
+	Stage             string
+	StepName          string
+	Notes             string
+	AttachmentName    string
+	Link              string // deep link ke transaksi, kosong kalau APP_BASE_URL tidak diisi
+}
+
+type emailTemplateSet struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+var (
+	emailTemplatesMu sync.Mutex
+	emailTemplates   = map[string]*emailTemplateSet{}
+	emailLayout      *htmltemplate.Template
+)
+
+// NormalizeEmailLanguage - bahasa tidak dikenal jatuh ke DefaultEmailLanguage
+func NormalizeEmailLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	for _, l := range EmailLanguages {
+		if l == lang {
+			return l
+		}
+	}
+	return DefaultEmailLanguage
+}
+
+// RenderEmail returns subject, body text, dan body html untuk event + jenis transaksi
+func RenderEmail(lang, transactionType, event string, data EmailTemplateData) (string, string, string, error) {
+	set, layout, err := loadEmailTemplates(NormalizeEmailLanguage(lang), transactionType)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	subject, err := executeText(set.text, event+".subject", data)
+	if err != nil {
+		return "", "", "", err
+	}
+	subject = strings.TrimSpace(subject)
+
+	text, err := executeText(set.text, event, data)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	var content bytes.Buffer
+	if err := set.html.ExecuteTemplate(&content, event, data); err != nil {
+		return "", "", "", fmt.Errorf("email template %s/%s: %w", transactionType, event, err)
+	}
+
+	var html bytes.Buffer
+	if err := layout.ExecuteTemplate(&html, "layout", map[string]interface{}{
+		"Lang":    NormalizeEmailLanguage(lang),
+		"Subject": subject,
+		"Content": htmltemplate.HTML(content.String()),
+	}); err != nil {
+		return "", "", "", err
+	}
+
+	return subject, strings.TrimSpace(text), html.String(), nil
+}
+
+func executeText(tmpl *texttemplate.Template, name string, data EmailTemplateData) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("email template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// loadEmailTemplates - parse sekali per bahasa + jenis transaksi, lalu di-cache
+func loadEmailTemplates(lang, transactionType string) (*emailTemplateSet, *htmltemplate.Template, error) {
+	emailTemplatesMu.Lock()
+	defer emailTemplatesMu.Unlock()
+
+	if emailLayout == nil {
+		layout, err := htmltemplate.ParseFS(emailTemplateFS, "email_templates/layout.html")
+		if err != nil {
+			return nil, nil, err
+		}
+		emailLayout = layout
+	}
+
+	key := lang + "/" + transactionType
+	if set, ok := emailTemplates[key]; ok {
+		return set, emailLayout, nil
+	}
+
+	base := fmt.Sprintf("email_templates/%s/%s", lang, transactionType)
+	text, err := texttemplate.ParseFS(emailTemplateFS, base+".txt")
+	if err != nil {
+		return nil, nil, fmt.Errorf("no email template for transaction type %q (%s)", transactionType, lang)
+	}
+	html, err := htmltemplate.ParseFS(emailTemplateFS, base+".html")
+	if err != nil {
+		return nil, nil, fmt.Errorf("no email template for transaction type %q (%s)", transactionType, lang)
+	}
+
+	set := &emailTemplateSet{text: text, html: html}
+	emailTemplates[key] = set
+	return set, emailLayout, nil
+}
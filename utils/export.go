@@ -0,0 +1,559 @@
+package utils
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/xuri/excelize/v2"
+)
+
+// ============================================================================
+// EXPORT LIST (CSV / XLSX / PDF)
+// Service menulis baris satu per satu lewat ExportWriter sambil membaca cursor DB,
+// jadi hasil query tidak pernah ditampung utuh di memory:
+//   - CSV  : langsung ditulis ke response, di-flush tiap exportFlushEvery baris
+//   - XLSX : excelize StreamWriter (baris di-spill ke temp file), dikirim saat Close
+//   - PDF  : fpdf menyimpan dokumen di memory → dibatasi MaxPDFExportRows
+// Nilai uang: CSV / PDF pakai format rupiah (1.500.000), XLSX tetap angka
+// dengan number format ribuan supaya bisa dijumlah di Excel.
+// ============================================================================
+
+const (
+	ExportFormatCSV  = "CSV"
+	ExportFormatXLSX = "XLSX"
+	ExportFormatPDF  = "PDF"
+
+	MaxPDFExportRows = 5000
+	exportFlushEvery = 500
+	exportSheetName  = "Data"
+)
+
+// Tipe kolom menentukan format nilai di tiap writer
+const (
+	ExportText     = "TEXT"
+	ExportMoney    = "MONEY"
+	ExportNumber   = "NUMBER"
+	ExportDate     = "DATE"
+	ExportDateTime = "DATETIME"
+)
+
+var ErrPDFExportTooLarge = fmt.Errorf("too many rows for PDF export (max %d), use XLSX or CSV", MaxPDFExportRows)
+
+// ExportColumn - Width dalam satuan karakter (dipakai XLSX & proporsi lebar kolom PDF)
+type ExportColumn struct {
+	Header string
+	Type   string
+	Width  float64
+}
+
+// ExportWriter - Close menulis sisa output ke response,
+// Discard membuang hasil (dipanggil kalau service error di tengah jalan)
+type ExportWriter interface {
+	WriteRow(values ...interface{}) error
+	Close() error
+	Discard()
+}
+
+// NormalizeExportFormat - csv / xlsx / pdf (case-insensitive)
+func NormalizeExportFormat(format string) (string, error) {
+	switch strings.ToUpper(strings.TrimSpace(format)) {
+	case ExportFormatCSV:
+		return ExportFormatCSV, nil
+	case ExportFormatXLSX, "EXCEL":
+		return ExportFormatXLSX, nil
+	case ExportFormatPDF:
+		return ExportFormatPDF, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q, use csv, xlsx or pdf", format)
+	}
+}
+
+// ExportContentType returns content type + extension file
+func ExportContentType(format string) (string, string) {
+	switch format {
+	case ExportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", ".xlsx"
+	case ExportFormatPDF:
+		return "application/pdf", ".pdf"
+	default:
+		return "text/csv; charset=utf-8", ".csv"
+	}
+}
+
+func NewExportWriter(format string, w io.Writer, title string, columns []ExportColumn) (ExportWriter, error) {
+	switch format {
+	case ExportFormatCSV:
+		return newCSVExportWriter(w, columns), nil
+	case ExportFormatXLSX:
+		return newXLSXExportWriter(w, columns)
+	case ExportFormatPDF:
+		return newPDFExportWriter(w, title, columns), nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %s", format)
+	}
+}
+
+// ============================================================================
+// FORMAT NILAI
+// ============================================================================
+
+// FormatIDR - 1500000.5 → "1.500.000,50", tanpa desimal kalau bulat
+func FormatIDR(value float64) string {
+	negative := value < 0
+	value = math.Abs(value)
+
+	rounded := math.Round(value*100) / 100
+	whole := int64(rounded)
+	cents := int64(math.Round((rounded - float64(whole)) * 100))
+
+	digits := strconv.FormatInt(whole, 10)
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	result := grouped.String()
+	if cents > 0 {
+		result += fmt.Sprintf(",%02d", cents)
+	}
+	if negative {
+		result = "-" + result
+	}
+	return result
+}
+
+// exportValue - normalisasi pointer / tipe dasar, nil kalau kosong
+func exportValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case *string:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *float64:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *int:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *uint:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *bool:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+func exportFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// formatExportText - representasi teks untuk CSV & PDF
+func formatExportText(column ExportColumn, value interface{}) string {
+	value = exportValue(value)
+	if value == nil {
+		return ""
+	}
+
+	switch column.Type {
+	case ExportMoney:
+		if number, ok := exportFloat(value); ok {
+			return FormatIDR(number)
+		}
+	case ExportNumber:
+		if number, ok := exportFloat(value); ok {
+			return strconv.FormatFloat(number, 'f', -1, 64)
+		}
+	case ExportDate:
+		if t, ok := value.(time.Time); ok {
+			return t.Format("2006-01-02")
+		}
+	case ExportDateTime:
+		if t, ok := value.(time.Time); ok {
+			return t.Format("2006-01-02 15:04")
+		}
+	}
+
+	if b, ok := value.(bool); ok {
+		if b {
+			return "Yes"
+		}
+		return "No"
+	}
+	return fmt.Sprint(value)
+}
+
+// ============================================================================
+// CSV
+// Header baru ditulis saat baris pertama / Close, jadi error sebelum itu
+// masih bisa dibalas sebagai JSON oleh controller
+// ============================================================================
+
+type csvExportWriter struct {
+	writer        *csv.Writer
+	columns       []ExportColumn
+	headerWritten bool
+	rows          int
+}
+
+func newCSVExportWriter(w io.Writer, columns []ExportColumn) *csvExportWriter {
+	return &csvExportWriter{writer: csv.NewWriter(w), columns: columns}
+}
+
+func (e *csvExportWriter) writeHeader() error {
+	if e.headerWritten {
+		return nil
+	}
+	e.headerWritten = true
+
+	headers := make([]string, len(e.columns))
+	for i, column := range e.columns {
+		headers[i] = column.Header
+	}
+	return e.writer.Write(headers)
+}
+
+func (e *csvExportWriter) WriteRow(values ...interface{}) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+
+	record := make([]string, len(e.columns))
+	for i, column := range e.columns {
+		if i < len(values) {
+			record[i] = formatExportText(column, values[i])
+		}
+	}
+	if err := e.writer.Write(record); err != nil {
+		return err
+	}
+
+	e.rows++
+	if e.rows%exportFlushEvery == 0 {
+		e.writer.Flush()
+		return e.writer.Error()
+	}
+	return nil
+}
+
+// Discard - isi buffer yang belum di-flush dibuang
+func (e *csvExportWriter) Discard() {}
+
+func (e *csvExportWriter) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// ============================================================================
+// XLSX
+// ============================================================================
+
+type xlsxExportWriter struct {
+	out     io.Writer
+	file    *excelize.File
+	stream  *excelize.StreamWriter
+	columns []ExportColumn
+	styles  map[string]int
+	row     int
+}
+
+func newXLSXExportWriter(w io.Writer, columns []ExportColumn) (*xlsxExportWriter, error) {
+	file := excelize.NewFile()
+	if err := file.SetSheetName("Sheet1", exportSheetName); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	stream, err := file.NewStreamWriter(exportSheetName)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	e := &xlsxExportWriter{out: w, file: file, stream: stream, columns: columns, styles: map[string]int{}}
+
+	headerStyle, err := file.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"D9E1F2"}},
+	})
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	moneyFormat := "#,##0"
+	numberFormat := "#,##0.##"
+	dateFormat := "yyyy-mm-dd"
+	dateTimeFormat := "yyyy-mm-dd hh:mm"
+	for columnType, format := range map[string]*string{
+		ExportMoney:    &moneyFormat,
+		ExportNumber:   &numberFormat,
+		ExportDate:     &dateFormat,
+		ExportDateTime: &dateTimeFormat,
+	} {
+		style, err := file.NewStyle(&excelize.Style{CustomNumFmt: format})
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		e.styles[columnType] = style
+	}
+
+	for i, column := range columns {
+		if column.Width > 0 {
+			if err := stream.SetColWidth(i+1, i+1, column.Width); err != nil {
+				file.Close()
+				return nil, err
+			}
+		}
+	}
+
+	// Freeze baris header, harus sebelum SetRow pertama
+	if err := stream.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	header := make([]interface{}, len(columns))
+	for i, column := range columns {
+		header[i] = excelize.Cell{StyleID: headerStyle, Value: column.Header}
+	}
+	if err := stream.SetRow("A1", header, excelize.RowOpts{}); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	e.row = 1
+	return e, nil
+}
+
+func (e *xlsxExportWriter) WriteRow(values ...interface{}) error {
+	e.row++
+
+	cells := make([]interface{}, len(e.columns))
+	for i, column := range e.columns {
+		var value interface{}
+		if i < len(values) {
+			value = exportValue(values[i])
+		}
+		if b, ok := value.(bool); ok {
+			value = formatExportText(column, b)
+		}
+		if value == nil {
+			cells[i] = nil
+			continue
+		}
+		if style, ok := e.styles[column.Type]; ok {
+			cells[i] = excelize.Cell{StyleID: style, Value: value}
+			continue
+		}
+		cells[i] = value
+	}
+
+	cell, err := excelize.CoordinatesToCellName(1, e.row)
+	if err != nil {
+		return err
+	}
+	return e.stream.SetRow(cell, cells)
+}
+
+func (e *xlsxExportWriter) Discard() { e.file.Close() }
+
+func (e *xlsxExportWriter) Close() error {
+	defer e.file.Close()
+
+	if err := e.stream.Flush(); err != nil {
+		return err
+	}
+	if e.row > 1 {
+		lastCell, err := excelize.CoordinatesToCellName(len(e.columns), e.row)
+		if err != nil {
+			return err
+		}
+		if err := e.file.AutoFilter(exportSheetName, "A1:"+lastCell, nil); err != nil {
+			return err
+		}
+	}
+	return e.file.Write(e.out)
+}
+
+// ============================================================================
+// PDF
+// A4 landscape, header tabel diulang tiap halaman
+// ============================================================================
+
+const (
+	pdfExportMargin     = 10.0
+	pdfExportRowHeight  = 5.0
+	pdfExportFontSize   = 7.0
+	pdfExportPageWidth  = 297.0
+	pdfExportPageHeight = 210.0
+)
+
+type pdfExportWriter struct {
+	out     io.Writer
+	pdf     *fpdf.Fpdf
+	tr      func(string) string
+	title   string
+	columns []ExportColumn
+	widths  []float64
+	rows    int
+}
+
+func newPDFExportWriter(w io.Writer, title string, columns []ExportColumn) *pdfExportWriter {
+	pdf := fpdf.New("L", "mm", "A4", "")
+	pdf.SetMargins(pdfExportMargin, pdfExportMargin, pdfExportMargin)
+	pdf.SetAutoPageBreak(false, pdfExportMargin)
+	pdf.SetTitle(title, true)
+	pdf.AliasNbPages("")
+
+	e := &pdfExportWriter{
+		out:     w,
+		pdf:     pdf,
+		tr:      pdf.UnicodeTranslatorFromDescriptor(""),
+		title:   title,
+		columns: columns,
+	}
+
+	// Lebar kolom proporsional terhadap ExportColumn.Width
+	total := 0.0
+	for _, column := range columns {
+		total += pdfColumnWeight(column)
+	}
+	available := pdfExportPageWidth - 2*pdfExportMargin
+	e.widths = make([]float64, len(columns))
+	for i, column := range columns {
+		e.widths[i] = available * pdfColumnWeight(column) / total
+	}
+
+	generatedAt := time.Now().Format("2006-01-02 15:04")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-8)
+		pdf.SetFont("Helvetica", "", 6)
+		pdf.CellFormat(0, 4, fmt.Sprintf("Generated %s - Page %d/{nb}", generatedAt, pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+
+	e.addPage()
+	return e
+}
+
+func pdfColumnWeight(column ExportColumn) float64 {
+	if column.Width > 0 {
+		return column.Width
+	}
+	return 12
+}
+
+func (e *pdfExportWriter) addPage() {
+	e.pdf.AddPage()
+
+	if e.pdf.PageNo() == 1 {
+		e.pdf.SetFont("Helvetica", "B", 12)
+		e.pdf.CellFormat(0, 7, e.tr(e.title), "", 1, "L", false, 0, "")
+		e.pdf.Ln(1)
+	}
+
+	e.pdf.SetFont("Helvetica", "B", pdfExportFontSize)
+	e.pdf.SetFillColor(217, 225, 242)
+	for i, column := range e.columns {
+		e.pdf.CellFormat(e.widths[i], pdfExportRowHeight+1, e.fit(column.Header, e.widths[i]), "1", 0, "C", true, 0, "")
+	}
+	e.pdf.Ln(-1)
+	e.pdf.SetFont("Helvetica", "", pdfExportFontSize)
+}
+
+// fit - potong teks supaya muat di satu sel
+func (e *pdfExportWriter) fit(text string, width float64) string {
+	text = e.tr(text)
+	limit := width - 1.5
+	if e.pdf.GetStringWidth(text) <= limit {
+		return text
+	}
+	for len(text) > 0 && e.pdf.GetStringWidth(text+"...") > limit {
+		text = text[:len(text)-1]
+	}
+	return text + "..."
+}
+
+func (e *pdfExportWriter) WriteRow(values ...interface{}) error {
+	e.rows++
+	if e.rows > MaxPDFExportRows {
+		return ErrPDFExportTooLarge
+	}
+
+	if e.pdf.GetY()+pdfExportRowHeight > pdfExportPageHeight-pdfExportMargin-4 {
+		e.addPage()
+	}
+
+	for i, column := range e.columns {
+		var text string
+		if i < len(values) {
+			text = formatExportText(column, values[i])
+		}
+
+		align := "L"
+		if column.Type == ExportMoney || column.Type == ExportNumber {
+			align = "R"
+		}
+		e.pdf.CellFormat(e.widths[i], pdfExportRowHeight, e.fit(text, e.widths[i]), "1", 0, align, false, 0, "")
+	}
+	e.pdf.Ln(-1)
+
+	return e.pdf.Error()
+}
+
+func (e *pdfExportWriter) Close() error {
+	if e.rows == 0 {
+		e.pdf.SetFont("Helvetica", "I", pdfExportFontSize)
+		e.pdf.CellFormat(0, pdfExportRowHeight, "No data", "1", 1, "C", false, 0, "")
+	}
+	return e.pdf.Output(e.out)
+}
+
+func (e *pdfExportWriter) Discard() {}
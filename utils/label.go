@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/url"
+	"strings"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// ============================================================================
+// ASSET LABEL
+// Payload QR: ASSET:{asset_number}, di-resolve lewat GET /assets/labels/lookup dari aplikasi
+// (route asset butuh login, jadi QR tidak berisi URL yang dibuka langsung dari kamera HP).
+// Code128 selalu berisi asset number saja.
+// ParseAssetLabelPayload menerima ASSET:, asset number polos, dan URL .../assets/scan/{asset_number}
+// dari label yang sudah tercetak sebelumnya.
+// ============================================================================
+
+const (
+	LabelFormatQR      = "QR"
+	LabelFormatCode128 = "CODE128"
+
+	assetLabelPrefix   = "ASSET:"
+	assetLabelScanPath = "/assets/scan/"
+)
+
+// AssetLabelPayload - isi QR untuk asset number
+func AssetLabelPayload(assetNumber string) string {
+	return assetLabelPrefix + assetNumber
+}
+
+// ParseAssetLabelPayload returns asset number dari hasil scan QR / barcode
+func ParseAssetLabelPayload(payload string) string {
+	payload = strings.TrimSpace(payload)
+
+	if len(payload) >= len(assetLabelPrefix) && strings.EqualFold(payload[:len(assetLabelPrefix)], assetLabelPrefix) {
+		return strings.TrimSpace(payload[len(assetLabelPrefix):])
+	}
+
+	if u, err := url.Parse(payload); err == nil && u.Scheme != "" {
+		if i := strings.LastIndex(u.Path, assetLabelScanPath); i >= 0 {
+			if number, err := url.PathUnescape(u.Path[i+len(assetLabelScanPath):]); err == nil {
+				return strings.Trim(number, "/")
+			}
+		}
+	}
+
+	return payload
+}
+
+// NormalizeLabelFormat - default QR
+func NormalizeLabelFormat(format string) (string, error) {
+	switch strings.ToUpper(strings.TrimSpace(format)) {
+	case "", LabelFormatQR:
+		return LabelFormatQR, nil
+	case LabelFormatCode128, "BARCODE":
+		return LabelFormatCode128, nil
+	default:
+		return "", fmt.Errorf("unsupported label format %q, use QR or CODE128", format)
+	}
+}
+
+// EncodeAssetCode - barcode mentah (belum di-scale) sesuai format
+func EncodeAssetCode(format, assetNumber string) (barcode.Barcode, error) {
+	switch format {
+	case LabelFormatCode128:
+		return code128.Encode(assetNumber)
+	default:
+		return qr.Encode(AssetLabelPayload(assetNumber), qr.M, qr.Auto)
+	}
+}
+
+// RenderAssetLabelPNG - barcode + asset number di bawahnya
+func RenderAssetLabelPNG(format, assetNumber string) ([]byte, error) {
+	code, err := EncodeAssetCode(format, assetNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", format, err)
+	}
+
+	codeWidth, codeHeight := 300, 300
+	if format == LabelFormatCode128 {
+		codeWidth, codeHeight = 480, 120
+	}
+	scaled, err := barcode.Scale(code, codeWidth, codeHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	const margin = 16
+	const textHeight = 24
+	canvas := image.NewRGBA(image.Rect(0, 0, codeWidth+2*margin, codeHeight+2*margin+textHeight))
+	draw.Draw(canvas, canvas.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(canvas, image.Rect(margin, margin, margin+codeWidth, margin+codeHeight), scaled, image.Point{}, draw.Over)
+
+	face := basicfont.Face7x13
+	drawer := &font.Drawer{Dst: canvas, Src: image.NewUniform(color.Black), Face: face}
+	textWidth := drawer.MeasureString(assetNumber).Ceil()
+	drawer.Dot = fixed.P((canvas.Bounds().Dx()-textWidth)/2, margin+codeHeight+textHeight-6)
+	drawer.DrawString(assetNumber)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, canvas); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
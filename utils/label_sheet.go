@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"image/png"
+
+	"github.com/boombuler/barcode"
+	"github.com/go-pdf/fpdf"
+)
+
+// ============================================================================
+// LABEL SHEET PDF
+// A4 portrait, 3 x 7 label @ 63.5 x 38.1 mm (layout kertas label L7160 / 121)
+// Label QR     : QR di kiri, asset number + nama + cabang di kanan
+// Label CODE128: barcode di atas, asset number + nama di bawah
+// ============================================================================
+
+const (
+	labelSheetColumns = 3
+	labelSheetRows    = 7
+	labelWidth        = 63.5
+	labelHeight       = 38.1
+	labelMarginLeft   = 7.2
+	labelMarginTop    = 15.1
+	labelGapX         = 2.5
+	labelPadding      = 2.5
+)
+
+// AssetLabel - data yang dicetak di satu label
+type AssetLabel struct {
+	AssetNumber  string
+	AssetName    string
+	BranchCode   string
+	CategoryName string
+}
+
+// RenderAssetLabelSheetPDF - satu label per asset, otomatis tambah halaman
+func RenderAssetLabelSheetPDF(format string, labels []AssetLabel) ([]byte, error) {
+	if len(labels) == 0 {
+		return nil, fmt.Errorf("no labels to render")
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(0, 0, 0)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetTitle("Asset Labels", true)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	perPage := labelSheetColumns * labelSheetRows
+	for i, label := range labels {
+		if i%perPage == 0 {
+			pdf.AddPage()
+		}
+
+		slot := i % perPage
+		x := labelMarginLeft + float64(slot%labelSheetColumns)*(labelWidth+labelGapX)
+		y := labelMarginTop + float64(slot/labelSheetColumns)*labelHeight
+
+		imageName := fmt.Sprintf("label-%d", i)
+		if err := registerLabelImage(pdf, imageName, format, label.AssetNumber); err != nil {
+			return nil, fmt.Errorf("asset %s: %w", label.AssetNumber, err)
+		}
+
+		// Garis potong tipis
+		pdf.SetDrawColor(200, 200, 200)
+		pdf.SetLineWidth(0.1)
+		pdf.Rect(x, y, labelWidth, labelHeight, "D")
+
+		if format == LabelFormatCode128 {
+			drawCode128Label(pdf, tr, imageName, label, x, y)
+		} else {
+			drawQRLabel(pdf, tr, imageName, label, x, y)
+		}
+	}
+
+	if err := pdf.Error(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func registerLabelImage(pdf *fpdf.Fpdf, name, format, assetNumber string) error {
+	code, err := EncodeAssetCode(format, assetNumber)
+	if err != nil {
+		return err
+	}
+
+	width, height := 300, 300
+	if format == LabelFormatCode128 {
+		width, height = 600, 120
+	}
+	scaled, err := barcode.Scale(code, width, height)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, scaled); err != nil {
+		return err
+	}
+
+	pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{ImageType: "PNG"}, &buf)
+	return pdf.Error()
+}
+
+func drawQRLabel(pdf *fpdf.Fpdf, tr func(string) string, imageName string, label AssetLabel, x, y float64) {
+	qrSize := labelHeight - 2*labelPadding
+	pdf.ImageOptions(imageName, x+labelPadding, y+labelPadding, qrSize, qrSize, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+	textX := x + labelPadding + qrSize + 1.5
+	textWidth := labelWidth - (textX - x) - labelPadding
+
+	pdf.SetXY(textX, y+labelPadding+1)
+	pdf.SetFont("Helvetica", "B", 8)
+	pdf.CellFormat(textWidth, 4, tr(label.AssetNumber), "", 2, "L", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 7)
+	writeLines(pdf, tr(label.AssetName), textX, textWidth, 3.2, 4)
+
+	pdf.SetFont("Helvetica", "", 6)
+	if label.CategoryName != "" {
+		pdf.SetX(textX)
+		pdf.CellFormat(textWidth, 3, tr(label.CategoryName), "", 2, "L", false, 0, "")
+	}
+	if label.BranchCode != "" {
+		pdf.SetX(textX)
+		pdf.CellFormat(textWidth, 3, tr(label.BranchCode), "", 2, "L", false, 0, "")
+	}
+}
+
+func drawCode128Label(pdf *fpdf.Fpdf, tr func(string) string, imageName string, label AssetLabel, x, y float64) {
+	width := labelWidth - 2*labelPadding
+	pdf.ImageOptions(imageName, x+labelPadding, y+labelPadding, width, 14, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+	pdf.SetXY(x+labelPadding, y+labelPadding+15)
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.CellFormat(width, 4.5, tr(label.AssetNumber), "", 2, "C", false, 0, "")
+
+	pdf.SetFont("Helvetica", "", 7)
+	writeLines(pdf, tr(label.AssetName), x+labelPadding, width, 3.2, 2)
+
+	footer := label.BranchCode
+	if label.CategoryName != "" {
+		if footer != "" {
+			footer += " - "
+		}
+		footer += label.CategoryName
+	}
+	if footer != "" {
+		pdf.SetFont("Helvetica", "", 6)
+		pdf.SetX(x + labelPadding)
+		pdf.CellFormat(width, 3, tr(footer), "", 2, "C", false, 0, "")
+	}
+}
+
+// writeLines - wrap teks ke maksimal maxLines baris, sisanya dipotong
+func writeLines(pdf *fpdf.Fpdf, text string, x, width, lineHeight float64, maxLines int) {
+	lines := pdf.SplitText(text, width)
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		last := lines[maxLines-1]
+		for len(last) > 0 && pdf.GetStringWidth(last+"...") > width {
+			last = last[:len(last)-1]
+		}
+		lines[maxLines-1] = last + "..."
+	}
+	for _, line := range lines {
+		pdf.SetX(x)
+		pdf.CellFormat(width, lineHeight, line, "", 2, "L", false, 0, "")
+	}
+}
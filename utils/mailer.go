@@ -0,0 +1,220 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EmailMessage - satu email multipart (text + html)
+type EmailMessage struct {
+	To       []string
+	Subject  string
+	TextBody string
+	HTMLBody string
+}
+
+// Mailer - pengirim email.
+// Implementasi: SMTPMailer (server SMTP apa pun, termasuk server lokal
+// seperti MailHog / Mailpit untuk testing) dan FakeMailer (memory + log)
+type Mailer interface {
+	Send(ctx context.Context, msg EmailMessage) error
+}
+
+// ============================================================================
+// SMTP MAILER
+// SMTP_HOST      - kosong = pakai FakeMailer
+// SMTP_PORT      - default 587
+// SMTP_USERNAME  - kosong = tanpa AUTH (server lokal)
+// SMTP_PASSWORD
+// SMTP_FROM      - alamat pengirim, default SMTP_USERNAME
+// STARTTLS dipakai otomatis kalau server mendukung
+// ============================================================================
+
+type SMTPMailer struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// NewMailerFromEnv returns SMTPMailer kalau SMTP_HOST diisi, selain itu FakeMailer
+func NewMailerFromEnv() Mailer {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		log.Println("[Mail] SMTP_HOST not set, using fake mailer")
+		return NewFakeMailer()
+	}
+
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+
+	from := os.Getenv("SMTP_FROM")
+	if from == "" {
+		from = os.Getenv("SMTP_USERNAME")
+	}
+
+	return &SMTPMailer{
+		Host:     host,
+		Port:     port,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     from,
+	}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg EmailMessage) error {
+	if len(msg.To) == 0 {
+		return nil
+	}
+	if m.From == "" {
+		return fmt.Errorf("smtp: sender address (SMTP_FROM) is not configured")
+	}
+
+	raw, err := buildEmail(m.From, msg)
+	if err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if m.Username != "" {
+		auth = smtp.PlainAuth("", m.Username, m.Password, m.Host)
+	}
+
+	// net/smtp tidak menerima context → jalankan di goroutine dan hormati deadline ctx
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(net.JoinHostPort(m.Host, m.Port), auth, m.From, msg.To, raw)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// buildEmail - RFC 5322 message dengan multipart/alternative (text dulu, lalu html)
+func buildEmail(from string, msg EmailMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	headers := []string{
+		"From: " + from,
+		"To: " + strings.Join(msg.To, ", "),
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"Message-ID: " + messageID(from),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + writer.Boundary(),
+	}
+
+	var out bytes.Buffer
+	out.WriteString(strings.Join(headers, "\r\n"))
+	out.WriteString("\r\n\r\n")
+
+	parts := []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", msg.TextBody},
+		{"text/html; charset=utf-8", msg.HTMLBody},
+	}
+
+	for _, p := range parts {
+		if p.body == "" {
+			continue
+		}
+		part, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(part)
+		if _, err := qp.Write([]byte(p.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	out.Write(buf.Bytes())
+	return out.Bytes(), nil
+}
+
+func messageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(from, "@"); at >= 0 {
+		domain = strings.Trim(from[at+1:], "> ")
+	}
+
+	b := make([]byte, 12)
+	rand.Read(b)
+	return fmt.Sprintf("<%s@%s>", hex.EncodeToString(b), domain)
+}
+
+// ============================================================================
+// FAKE MAILER
+// Dipakai kalau SMTP belum dikonfigurasi (development) dan untuk testing
+// ============================================================================
+
+type FakeMailer struct {
+	mu     sync.Mutex
+	sent   []EmailMessage
+	Silent bool // true = tidak log setiap email
+}
+
+func NewFakeMailer() *FakeMailer {
+	return &FakeMailer{}
+}
+
+func (f *FakeMailer) Send(ctx context.Context, msg EmailMessage) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.sent = append(f.sent, msg)
+	if !f.Silent {
+		log.Printf("[Mail] (fake) %s → %s", strings.Join(msg.To, ", "), msg.Subject)
+	}
+	return nil
+}
+
+// Sent returns salinan semua email yang sudah "dikirim"
+func (f *FakeMailer) Sent() []EmailMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sent := make([]EmailMessage, len(f.sent))
+	copy(sent, f.sent)
+	return sent
+}
+
+func (f *FakeMailer) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = nil
+}
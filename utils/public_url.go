@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"os"
+	"strings"
+)
+
+// APIPrefix - prefix semua route backend (routes.SetupRoutes)
+const APIPrefix = "/api/v1"
+
+// PublicAPIURL - URL absolut route backend untuk di-encode di QR.
+// API_BASE_URL = base URL publik backend tanpa /api/v1 (beda dengan APP_BASE_URL yang menunjuk frontend).
+// Kosong kalau API_BASE_URL tidak diisi
+func PublicAPIURL(path string) string {
+	base := strings.TrimRight(os.Getenv("API_BASE_URL"), "/")
+	if base == "" {
+		return ""
+	}
+	return base + APIPrefix + path
+}
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+)
+
+// PushMessage - isi push notification
+type PushMessage struct {
+	Title string
+	Body  string
+	Data  map[string]string // payload untuk deep link di app, misal transaction_number
+}
+
+// PushSender - pengirim push notification ke satu device token.
+// Implementasi: FCMSender (FCM HTTP v1) dan FakePushSender (lokal / testing)
+type PushSender interface {
+	Send(ctx context.Context, token string, msg PushMessage) error
+}
+
+// ErrPushTokenInvalid - token sudah tidak terdaftar di provider, sebaiknya dinonaktifkan
+var ErrPushTokenInvalid = errors.New("push token is no longer registered")
+
+// ============================================================================
+// FAKE SENDER
+// Dipakai kalau FCM belum dikonfigurasi (development) dan untuk testing:
+// pesan hanya disimpan di memory + di-log, tidak dikirim ke mana pun
+// ============================================================================
+
+type FakePush struct {
+	Token   string
+	Message PushMessage
+}
+
+type FakePushSender struct {
+	mu            sync.Mutex
+	sent          []FakePush
+	InvalidTokens map[string]bool // token yang disimulasikan sudah tidak terdaftar
+	Silent        bool            // true = tidak log setiap pesan
+}
+
+func NewFakePushSender() *FakePushSender {
+	return &FakePushSender{InvalidTokens: map[string]bool{}}
+}
+
+func (f *FakePushSender) Send(ctx context.Context, token string, msg PushMessage) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.InvalidTokens[token] {
+		return ErrPushTokenInvalid
+	}
+
+	f.sent = append(f.sent, FakePush{Token: token, Message: msg})
+	if !f.Silent {
+		log.Printf("[Push] (fake) %s → %s: %s", shortToken(token), msg.Title, msg.Body)
+	}
+	return nil
+}
+
+// Sent returns salinan semua pesan yang sudah "dikirim"
+func (f *FakePushSender) Sent() []FakePush {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	sent := make([]FakePush, len(f.sent))
+	copy(sent, f.sent)
+	return sent
+}
+
+func (f *FakePushSender) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sent = nil
+}
+
+func shortToken(token string) string {
+	if len(token) <= 12 {
+		return token
+	}
+	return token[:12] + "..."
+}
@@ -0,0 +1,25 @@
+package utils
+
+import (
+	"backend-go/dto"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxUserAgentLength sesuai kolom approval_signatures.user_agent varchar(255)
+const maxUserAgentLength = 255
+
+// GetRequestMeta - IP client (ClientIP sudah memperhitungkan trusted proxies), user agent & request ID
+func GetRequestMeta(c *gin.Context) dto.RequestMeta {
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = strings.ToValidUTF8(userAgent[:maxUserAgentLength], "")
+	}
+
+	return dto.RequestMeta{
+		IPAddress: c.ClientIP(),
+		UserAgent: userAgent,
+		RequestID: c.GetString("request_id"),
+	}
+}
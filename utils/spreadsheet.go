@@ -0,0 +1,246 @@
+package utils
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ============================================================================
+// SPREADSHEET READER
+// CSV  : delimiter dideteksi dari header (, ; atau tab), BOM UTF-8 dibuang
+// XLSX : nilai mentah (RawCellValue) supaya angka tidak ikut format tampilan,
+//        tanggal tetap berupa serial Excel → pakai ParseSpreadsheetDate
+// ============================================================================
+
+const (
+	SpreadsheetCSV  = "CSV"
+	SpreadsheetXLSX = "XLSX"
+)
+
+// SpreadsheetRow - satu baris data, Number = nomor baris di file (header = 1)
+type SpreadsheetRow struct {
+	Number int
+	Cells  []string
+}
+
+// SpreadsheetType - tipe file dari extension
+func SpreadsheetType(fileName string) (string, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return SpreadsheetCSV, nil
+	case ".xlsx":
+		return SpreadsheetXLSX, nil
+	default:
+		return "", errors.New("unsupported file type, use .csv or .xlsx")
+	}
+}
+
+// ReadSpreadsheet returns header + baris data (baris kosong dilewati).
+// sheet hanya dipakai untuk XLSX, kosong = sheet pertama
+func ReadSpreadsheet(r io.Reader, fileType, sheet string) ([]string, []SpreadsheetRow, string, error) {
+	switch fileType {
+	case SpreadsheetCSV:
+		headers, rows, err := readCSV(r)
+		return headers, rows, "", err
+	case SpreadsheetXLSX:
+		return readXLSX(r, sheet)
+	default:
+		return nil, nil, "", fmt.Errorf("unsupported file type %s", fileType)
+	}
+}
+
+func readCSV(r io.Reader) ([]string, []SpreadsheetRow, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	if !utf8.ValidString(text) {
+		return nil, nil, errors.New("CSV file must be UTF-8 encoded")
+	}
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = detectCSVDelimiter(text)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var headers []string
+	var rows []SpreadsheetRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if headers == nil {
+			headers = record
+			continue
+		}
+		if isBlankRow(record) {
+			continue
+		}
+		rows = append(rows, SpreadsheetRow{Number: line, Cells: record})
+	}
+
+	if headers == nil {
+		return nil, nil, errors.New("file is empty")
+	}
+	return headers, rows, nil
+}
+
+// detectCSVDelimiter - Excel locale Indonesia menyimpan CSV dengan ';'
+func detectCSVDelimiter(text string) rune {
+	firstLine := text
+	if i := strings.IndexAny(text, "\r\n"); i >= 0 {
+		firstLine = text[:i]
+	}
+
+	best, bestCount := ',', strings.Count(firstLine, ",")
+	for _, candidate := range []rune{';', '\t'} {
+		if count := strings.Count(firstLine, string(candidate)); count > bestCount {
+			best, bestCount = candidate, count
+		}
+	}
+	return best
+}
+
+func readXLSX(r io.Reader, sheet string) ([]string, []SpreadsheetRow, string, error) {
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("invalid XLSX: %w", err)
+	}
+	defer file.Close()
+
+	if sheet == "" {
+		sheet = file.GetSheetName(file.GetActiveSheetIndex())
+		if sheet == "" {
+			sheet = file.GetSheetName(0)
+		}
+	} else if index, err := file.GetSheetIndex(sheet); err != nil || index < 0 {
+		return nil, nil, "", fmt.Errorf("sheet %q not found", sheet)
+	}
+
+	iterator, err := file.Rows(sheet)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	defer iterator.Close()
+
+	var headers []string
+	var rows []SpreadsheetRow
+	for number := 1; iterator.Next(); number++ {
+		cells, err := iterator.Columns(excelize.Options{RawCellValue: true})
+		if err != nil {
+			return nil, nil, "", err
+		}
+		if headers == nil {
+			if isBlankRow(cells) {
+				continue // baris kosong di atas header
+			}
+			headers = cells
+			continue
+		}
+		if isBlankRow(cells) {
+			continue
+		}
+		rows = append(rows, SpreadsheetRow{Number: number, Cells: cells})
+	}
+	if err := iterator.Error(); err != nil {
+		return nil, nil, "", err
+	}
+
+	if headers == nil {
+		return nil, nil, "", fmt.Errorf("sheet %q is empty", sheet)
+	}
+	return headers, rows, sheet, nil
+}
+
+func isBlankRow(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// NormalizeSpreadsheetHeader - "Nama Aset " → "nama_aset"
+func NormalizeSpreadsheetHeader(header string) string {
+	header = strings.ToLower(strings.TrimSpace(header))
+	return strings.Join(strings.FieldsFunc(header, func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-' || r == '.'
+	}), "_")
+}
+
+// ParseSpreadsheetNumber - terima angka mentah (1500000.5) maupun format
+// rupiah (Rp 1.500.000,50 / 1,500,000.50)
+func ParseSpreadsheetNumber(value string) (float64, error) {
+	cleaned := strings.TrimSpace(value)
+	cleaned = strings.TrimPrefix(strings.TrimPrefix(cleaned, "Rp"), "IDR")
+	cleaned = strings.ReplaceAll(strings.TrimSpace(cleaned), " ", "")
+	if cleaned == "" {
+		return 0, errors.New("empty number")
+	}
+
+	lastDot := strings.LastIndex(cleaned, ".")
+	lastComma := strings.LastIndex(cleaned, ",")
+	switch {
+	case lastDot >= 0 && lastComma >= 0:
+		if lastComma > lastDot {
+			// 1.500.000,50
+			cleaned = strings.ReplaceAll(cleaned, ".", "")
+			cleaned = strings.Replace(cleaned, ",", ".", 1)
+		} else {
+			// 1,500,000.50
+			cleaned = strings.ReplaceAll(cleaned, ",", "")
+		}
+	case lastComma >= 0:
+		// 1500000,50 → desimal; 1,500,000 → ribuan
+		if strings.Count(cleaned, ",") == 1 && len(cleaned)-lastComma-1 <= 2 {
+			cleaned = strings.Replace(cleaned, ",", ".", 1)
+		} else {
+			cleaned = strings.ReplaceAll(cleaned, ",", "")
+		}
+	case strings.Count(cleaned, ".") > 1:
+		// 1.500.000
+		cleaned = strings.ReplaceAll(cleaned, ".", "")
+	}
+
+	number, err := strconv.ParseFloat(cleaned, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", value)
+	}
+	return number, nil
+}
+
+// ParseSpreadsheetDate - YYYY-MM-DD, DD/MM/YYYY, DD-MM-YYYY atau serial tanggal Excel
+func ParseSpreadsheetDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range []string{"2006-01-02", "02/01/2006", "2/1/2006", "02-01-2006", "2006/01/02", "2006-01-02 15:04:05"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			return parsed, nil
+		}
+	}
+
+	if serial, err := strconv.ParseFloat(value, 64); err == nil && serial > 0 {
+		parsed, err := excelize.ExcelDateToTime(serial, false)
+		if err == nil {
+			return time.Date(parsed.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, time.UTC), nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD", value)
+}
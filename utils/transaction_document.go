@@ -0,0 +1,376 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/qr"
+	"github.com/go-pdf/fpdf"
+)
+
+// ============================================================================
+// TRANSACTION DOCUMENT (PDF)
+// Dokumen resmi procurement / serah terima mutasi / disposal / stock opname:
+// header transaksi, IO number, tabel item, blok tanda tangan dari
+// ApprovalSignature, dan QR verifikasi.
+//
+// Kode verifikasi = HMAC(transaction_number + id signature yang tercetak),
+// jadi kode berubah kalau ada tanda tangan baru setelah dokumen dicetak.
+// Secret: DOCUMENT_VERIFY_SECRET, fallback ke JWT_SECRET.
+// Payload QR:
+//   - API_BASE_URL diisi → {API_BASE_URL}/api/v1/transactions/verify?number=..&code=..
+//   - selain itu         → DOC:{transaction_number}:{code}
+// ============================================================================
+
+const (
+	docMargin         = 12.0
+	docPageWidth      = 210.0
+	docPageHeight     = 297.0
+	docRowHeight      = 5.5
+	docFontSize       = 8.0
+	docQRSize         = 28.0
+	docSignatureCols  = 4
+	docSignatureH     = 44.0
+	docSignatureImage = 18.0
+
+	documentVerifyPrefix = "DOC:"
+	documentVerifyPath   = "/transactions/verify"
+)
+
+// DocumentField - pasangan label : nilai di header dokumen
+type DocumentField struct {
+	Label string
+	Value string
+}
+
+// DocumentSignature - satu kotak tanda tangan; Image boleh kosong
+type DocumentSignature struct {
+	Title      string // judul kotak, misal "Approver"
+	Name       string
+	Role       string
+	OnBehalfOf string // approver asli kalau ditandatangani delegate
+	SignedAt   time.Time
+	Image      []byte // PNG / JPEG / GIF
+}
+
+type TransactionDocument struct {
+	Title             string
+	TransactionNumber string
+	VerificationCode  string
+	Fields            []DocumentField
+	IONumbers         []DocumentField // Label = branch code, Value = IO number
+	ItemColumns       []ExportColumn
+	Items             [][]interface{}
+	Signatures        []DocumentSignature
+}
+
+// TransactionDocumentCode - kode verifikasi 16 karakter hex
+func TransactionDocumentCode(transactionNumber string, signatureIDs []string) string {
+	secret := os.Getenv("DOCUMENT_VERIFY_SECRET")
+	if secret == "" {
+		secret = os.Getenv("JWT_SECRET")
+	}
+
+	ids := append([]string(nil), signatureIDs...)
+	sort.Strings(ids)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(transactionNumber + "\n" + strings.Join(ids, ",")))
+	return strings.ToUpper(hex.EncodeToString(mac.Sum(nil))[:16])
+}
+
+// TransactionDocumentCodeEqual - perbandingan constant time, tidak case sensitive
+func TransactionDocumentCodeEqual(expected, actual string) bool {
+	return hmac.Equal([]byte(expected), []byte(strings.ToUpper(strings.TrimSpace(actual))))
+}
+
+// TransactionDocumentVerifyPayload - isi QR di dokumen
+func TransactionDocumentVerifyPayload(transactionNumber, code string) string {
+	verifyURL := PublicAPIURL(documentVerifyPath)
+	if verifyURL == "" {
+		return documentVerifyPrefix + transactionNumber + ":" + code
+	}
+
+	query := url.Values{}
+	query.Set("number", transactionNumber)
+	query.Set("code", code)
+	return verifyURL + "?" + query.Encode()
+}
+
+// RenderTransactionDocumentPDF - A4 portrait, tabel item lanjut ke halaman berikutnya
+func RenderTransactionDocumentPDF(doc TransactionDocument) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(docMargin, docMargin, docMargin)
+	pdf.SetAutoPageBreak(false, docMargin)
+	pdf.SetTitle(doc.Title+" "+doc.TransactionNumber, true)
+	pdf.AliasNbPages("")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	printedAt := time.Now().Format("2006-01-02 15:04")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-9)
+		pdf.SetFont("Helvetica", "", 6.5)
+		pdf.CellFormat(0, 4, tr(fmt.Sprintf("%s - Kode verifikasi %s - Dicetak %s", doc.TransactionNumber, doc.VerificationCode, printedAt)), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 4, fmt.Sprintf("Page %d/{nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+	})
+
+	if err := registerDocumentQR(pdf, doc); err != nil {
+		return nil, err
+	}
+
+	pdf.AddPage()
+	drawDocumentHeader(pdf, tr, doc)
+	drawDocumentIONumbers(pdf, tr, doc.IONumbers)
+	drawDocumentItems(pdf, tr, doc.ItemColumns, doc.Items)
+	if err := drawDocumentSignatures(pdf, tr, doc.Signatures); err != nil {
+		return nil, err
+	}
+
+	if err := pdf.Error(); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func registerDocumentQR(pdf *fpdf.Fpdf, doc TransactionDocument) error {
+	code, err := qr.Encode(TransactionDocumentVerifyPayload(doc.TransactionNumber, doc.VerificationCode), qr.M, qr.Auto)
+	if err != nil {
+		return fmt.Errorf("failed to encode verification QR: %w", err)
+	}
+	scaled, err := barcode.Scale(code, 300, 300)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, scaled); err != nil {
+		return err
+	}
+	pdf.RegisterImageOptionsReader("verify-qr", fpdf.ImageOptions{ImageType: "PNG"}, &buf)
+	return pdf.Error()
+}
+
+func drawDocumentHeader(pdf *fpdf.Fpdf, tr func(string) string, doc TransactionDocument) {
+	qrX := docPageWidth - docMargin - docQRSize
+	pdf.ImageOptions("verify-qr", qrX, docMargin, docQRSize, docQRSize, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	pdf.SetXY(qrX-4, docMargin+docQRSize)
+	pdf.SetFont("Helvetica", "", 6)
+	pdf.CellFormat(docQRSize+8, 3, tr("Scan untuk verifikasi"), "", 2, "C", false, 0, "")
+	pdf.SetX(qrX - 4)
+	pdf.SetFont("Courier", "B", 7)
+	pdf.CellFormat(docQRSize+8, 3.5, doc.VerificationCode, "", 0, "C", false, 0, "")
+
+	textWidth := qrX - docMargin - 4
+	pdf.SetXY(docMargin, docMargin)
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.MultiCell(textWidth, 7, tr(doc.Title), "", "L", false)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(textWidth, 6, tr("No. "+doc.TransactionNumber), "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+
+	labelWidth := 38.0
+	for _, field := range doc.Fields {
+		if field.Value == "" {
+			continue
+		}
+		pdf.SetX(docMargin)
+		pdf.SetFont("Helvetica", "B", docFontSize)
+		pdf.CellFormat(labelWidth, 4.5, tr(field.Label), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", docFontSize)
+		pdf.MultiCell(textWidth-labelWidth, 4.5, tr(": "+field.Value), "", "L", false)
+	}
+
+	if y := docMargin + docQRSize + 8; pdf.GetY() < y {
+		pdf.SetY(y)
+	}
+	pdf.Ln(2)
+}
+
+func drawDocumentIONumbers(pdf *fpdf.Fpdf, tr func(string) string, ioNumbers []DocumentField) {
+	if len(ioNumbers) == 0 {
+		return
+	}
+
+	drawDocumentSection(pdf, tr, "IO Number")
+	width := (docPageWidth - 2*docMargin) / 2
+	pdf.SetFont("Helvetica", "B", docFontSize)
+	pdf.SetFillColor(217, 225, 242)
+	pdf.CellFormat(width, docRowHeight, "Branch", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(width, docRowHeight, "IO Number", "1", 1, "C", true, 0, "")
+	pdf.SetFont("Helvetica", "", docFontSize)
+	for _, io := range ioNumbers {
+		ensureDocumentSpace(pdf, docRowHeight)
+		pdf.CellFormat(width, docRowHeight, tr(io.Label), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(width, docRowHeight, tr(io.Value), "1", 1, "L", false, 0, "")
+	}
+	pdf.Ln(3)
+}
+
+func drawDocumentItems(pdf *fpdf.Fpdf, tr func(string) string, columns []ExportColumn, items [][]interface{}) {
+	if len(columns) == 0 {
+		return
+	}
+
+	drawDocumentSection(pdf, tr, fmt.Sprintf("Item (%d)", len(items)))
+
+	total := 0.0
+	for _, column := range columns {
+		total += pdfColumnWeight(column)
+	}
+	available := docPageWidth - 2*docMargin
+	widths := make([]float64, len(columns))
+	for i, column := range columns {
+		widths[i] = available * pdfColumnWeight(column) / total
+	}
+
+	drawHeader := func() {
+		pdf.SetFont("Helvetica", "B", docFontSize)
+		pdf.SetFillColor(217, 225, 242)
+		for i, column := range columns {
+			pdf.CellFormat(widths[i], docRowHeight+1, fitDocumentText(pdf, tr(column.Header), widths[i]), "1", 0, "C", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont("Helvetica", "", docFontSize)
+	}
+
+	drawHeader()
+	if len(items) == 0 {
+		pdf.SetFont("Helvetica", "I", docFontSize)
+		pdf.CellFormat(available, docRowHeight, "No data", "1", 1, "C", false, 0, "")
+	}
+	for _, item := range items {
+		if ensureDocumentSpace(pdf, docRowHeight) {
+			drawHeader()
+		}
+		for i, column := range columns {
+			var text string
+			if i < len(item) {
+				text = formatExportText(column, item[i])
+			}
+			align := "L"
+			if column.Type == ExportMoney || column.Type == ExportNumber {
+				align = "R"
+			}
+			pdf.CellFormat(widths[i], docRowHeight, fitDocumentText(pdf, tr(text), widths[i]), "1", 0, align, false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+	pdf.Ln(4)
+}
+
+func drawDocumentSignatures(pdf *fpdf.Fpdf, tr func(string) string, signatures []DocumentSignature) error {
+	ensureDocumentSpace(pdf, 8+docSignatureH)
+	drawDocumentSection(pdf, tr, "Tanda Tangan")
+
+	if len(signatures) == 0 {
+		pdf.SetFont("Helvetica", "I", docFontSize)
+		pdf.CellFormat(0, docRowHeight, tr("Belum ada tanda tangan"), "", 1, "L", false, 0, "")
+		return nil
+	}
+
+	width := (docPageWidth - 2*docMargin) / docSignatureCols
+	for i, signature := range signatures {
+		col := i % docSignatureCols
+		if col == 0 {
+			if i > 0 {
+				pdf.SetY(pdf.GetY() + docSignatureH)
+			}
+			ensureDocumentSpace(pdf, docSignatureH)
+		}
+
+		x := docMargin + float64(col)*width
+		y := pdf.GetY()
+		pdf.SetDrawColor(160, 160, 160)
+		pdf.Rect(x, y, width, docSignatureH, "D")
+
+		pdf.SetXY(x, y+1)
+		pdf.SetFont("Helvetica", "B", docFontSize)
+		pdf.CellFormat(width, 4, fitDocumentText(pdf, tr(signature.Title), width), "", 2, "C", false, 0, "")
+
+		if len(signature.Image) > 0 {
+			name := fmt.Sprintf("signature-%d", i)
+			if registerSignatureImage(pdf, name, signature.Image) {
+				pdf.ImageOptions(name, x+4, y+5.5, width-8, docSignatureImage, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+			}
+		}
+
+		pdf.SetXY(x, y+6+docSignatureImage)
+		pdf.SetFont("Helvetica", "BU", docFontSize)
+		pdf.CellFormat(width, 4, fitDocumentText(pdf, tr(signature.Name), width), "", 2, "C", false, 0, "")
+		pdf.SetFont("Helvetica", "", 7)
+		if signature.Role != "" {
+			pdf.CellFormat(width, 3.5, fitDocumentText(pdf, tr(signature.Role), width), "", 2, "C", false, 0, "")
+		}
+		if signature.OnBehalfOf != "" {
+			pdf.CellFormat(width, 3.5, fitDocumentText(pdf, tr("a.n. "+signature.OnBehalfOf), width), "", 2, "C", false, 0, "")
+		}
+		pdf.CellFormat(width, 3.5, signature.SignedAt.Format("2006-01-02 15:04"), "", 2, "C", false, 0, "")
+		pdf.SetY(y)
+	}
+	pdf.SetDrawColor(0, 0, 0)
+	pdf.SetY(pdf.GetY() + docSignatureH)
+
+	return pdf.Error()
+}
+
+// registerSignatureImage - decode lalu encode ulang ke PNG supaya format apa pun
+// (JPEG, GIF, PNG interlaced) bisa dipakai fpdf. File rusak dilewati saja.
+func registerSignatureImage(pdf *fpdf.Fpdf, name string, data []byte) bool {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return false
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return false
+	}
+
+	pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{ImageType: "PNG"}, &buf)
+	return pdf.Error() == nil
+}
+
+func drawDocumentSection(pdf *fpdf.Fpdf, tr func(string) string, title string) {
+	ensureDocumentSpace(pdf, 2*docRowHeight)
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(0, 6, tr(title), "", 1, "L", false, 0, "")
+}
+
+// ensureDocumentSpace - pindah halaman kalau tinggi yang dibutuhkan tidak muat,
+// return true kalau halaman baru ditambahkan
+func ensureDocumentSpace(pdf *fpdf.Fpdf, height float64) bool {
+	if pdf.GetY()+height <= docPageHeight-docMargin-4 {
+		return false
+	}
+	pdf.AddPage()
+	return true
+}
+
+func fitDocumentText(pdf *fpdf.Fpdf, text string, width float64) string {
+	limit := width - 1.5
+	if pdf.GetStringWidth(text) <= limit {
+		return text
+	}
+	for len(text) > 0 && pdf.GetStringWidth(text+"...") > limit {
+		text = text[:len(text)-1]
+	}
+	return text + "..."
+}